@@ -0,0 +1,252 @@
+/*
+   Package analyze works out production statistics from stored test
+   results: first pass yield by assembly part number, by module part
+   number and by week, and a Pareto of the tests that fail most often.
+
+   The analysis uses the results alone, so results stored before runs
+   were recorded count too. A unit passes first time when every result
+   of the first run of each test it was given passed, a sequence often
+   storing several checks under one test name; a retest that passes does
+   not change that. Results stored without a run count only the first
+   result of each test.
+*/
+
+package analyze
+
+import (
+        "fmt"
+        "sort"
+        "time"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* First pass yield of one group of units */
+
+type Yield struct {
+  Group string                     // Part number or week
+  Units int                        // Units tested
+  FirstPass int                    // Units that passed every test first time
+}
+
+/* One test in the failure Pareto */
+
+type Failure struct {
+  Name string                      // Test name
+  Failures int                     // Number of failed results
+  Percent float64                  // Share of all failed results
+  Cumulative float64               // Share of this and every worse test
+}
+
+/* Analysis */
+
+type Analysis struct {
+  Results int                      // Number of results analysed
+  Assemblies []Yield               // First pass yield by assembly part number
+  Modules []Yield                  // First pass yield by module part number
+  Weeks []Yield                    // First pass yield by week of first test
+  Tests []Yield                    // First pass yield of every run by part number and test
+  Pareto []Failure                 // Failing tests, worst first
+}
+
+/*
+    Procedure Name : FPY
+
+    Description    : Returns the first pass yield of a group.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : First pass yield in percent
+*/
+
+func (y Yield) FPY() float64 {
+  if y.Units == 0 {
+    return 0
+  }
+
+  return 100*(float64) (y.FirstPass)/(float64) (y.Units)
+}
+
+/*
+    Procedure Name : Week
+
+    Description    : Returns the ISO week a result was taken in, from its
+                     timestamp or, failing that, its local date.
+
+    Arguments      : r - Result
+
+    Return Value   : The week, such as "2026-W42"
+*/
+
+func Week( r results.TestResult ) string {
+  t, err := time.Parse( results.TIMESTAMP_FORMAT, r.Timestamp )
+
+  if err != nil {
+    t, err = time.Parse( "01/02/2006", r.Date )
+  }
+
+  if err != nil {
+    return "unknown"
+  }
+
+  year, week := t.ISOWeek()
+
+  return fmt.Sprintf("%d-W%2.2d", year, week)
+}
+
+/* A unit, or a module of a unit, as it went through the tests */
+
+type unit struct {
+  group string                     // Part number
+  week string                      // Week of the first result
+  tested map[string]string         // Run of the first result of each test
+  firstPass bool                   // Every first result passed
+}
+
+/*
+    Procedure Name : tally
+
+    Description    : Counts the units of each group and the ones that
+                     passed first time.
+
+    Arguments      : units - Units by serial number
+                     group - Returns the group a unit is counted in
+
+    Return Value   : Yield of each group, sorted by group
+*/
+
+func tally( units map[string]*unit, group func( *unit ) string ) []Yield {
+  byGroup := map[string]*Yield{}
+
+  for _, u := range units {
+    g := group( u )
+
+    y, ok := byGroup[g]
+
+    if !ok {
+      y = &Yield{ Group: g }
+      byGroup[g] = y
+    }
+
+    y.Units++
+
+    if u.firstPass {
+      y.FirstPass++
+    }
+  }
+
+  list := []Yield{}
+
+  for _, y := range byGroup {
+    list = append(list, *y)
+  }
+
+  sort.Slice( list, func( a, b int ) bool { return list[a].Group < list[b].Group } )
+
+  return list
+}
+
+/*
+    Procedure Name : record
+
+    Description    : Adds a result to the unit it belongs to, failing
+                     the unit first time if the result is from the
+                     first run of its test and did not pass.
+
+    Arguments      : units  - Units by serial number
+                     serial - Serial number of the unit
+                     group  - Part number of the unit
+                     r      - Result
+
+    Return Value   : This routine has no return value.
+*/
+
+func record( units map[string]*unit, serial string, group string, r results.TestResult ) {
+  u, ok := units[serial]
+
+  if !ok {
+    u = &unit{ group: group, week: Week( r ), tested: map[string]string{}, firstPass: true }
+    units[serial] = u
+  }
+
+  run, ok := u.tested[r.TestName]
+
+  if !ok {
+    u.tested[r.TestName] = r.RunID
+  } else if run == "" || run != r.RunID {
+    return
+  }
+
+  if !r.Pass {
+    u.firstPass = false
+  }
+}
+
+/*
+    Procedure Name : Analyze
+
+    Description    : Works out the first pass yields and the failure
+                     Pareto of a set of results.
+
+    Arguments      : list - Results in any order
+
+    Return Value   : The analysis
+*/
+
+func Analyze( list []results.TestResult ) Analysis {
+  sorted := append([]results.TestResult{}, list...)
+
+  sort.SliceStable( sorted, func( a, b int ) bool { return sorted[a].Timestamp < sorted[b].Timestamp } )
+
+  assemblies := map[string]*unit{}
+  modules := map[string]*unit{}
+  failures := map[string]int{}
+  failed := 0
+
+  for _, r := range sorted {
+    serial := r.AssemblySerialNumber
+
+    if serial == "" {
+      serial = r.SerialNumber
+    }
+
+    record( assemblies, serial, r.AssemblyPartNumber, r )
+
+    if r.PartNumber != "" {
+      record( modules, r.PartNumber + "/" + r.SerialNumber, r.PartNumber, r )
+    }
+
+    if !r.Pass {
+      failures[r.TestName]++
+      failed++
+    }
+  }
+
+  a := Analysis{ Results: len(list) }
+
+  a.Assemblies = tally( assemblies, func( u *unit ) string { return u.group } )
+  a.Modules = tally( modules, func( u *unit ) string { return u.group } )
+  a.Weeks = tally( assemblies, func( u *unit ) string { return u.week } )
+
+  for name, n := range failures {
+    a.Pareto = append(a.Pareto, Failure{ Name: name, Failures: n, Percent: 100*(float64) (n)/(float64) (failed) })
+  }
+
+  sort.Slice( a.Pareto, func( i, j int ) bool {
+    if a.Pareto[i].Failures != a.Pareto[j].Failures {
+      return a.Pareto[i].Failures > a.Pareto[j].Failures
+    }
+
+    return a.Pareto[i].Name < a.Pareto[j].Name
+  } )
+
+  cumulative := 0.0
+
+  for i := 0;i < len(a.Pareto);i++ {
+    cumulative += a.Pareto[i].Percent
+
+    a.Pareto[i].Cumulative = cumulative
+  }
+
+  return a
+}
@@ -0,0 +1,143 @@
+package analyze
+
+import (
+        "fmt"
+        "math"
+        "testing"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/*
+    Procedure Name : run
+
+    Description    : Makes the results of one run of a test of a unit,
+                     one result per check, all under the same test name
+                     as a sequence stores them.
+
+    Arguments      : serial - Assembly serial number
+                     runID  - Run ID, empty for results stored before runs
+                     minute - Minute of the hour the run was made in
+                     passes - Pass of each check
+
+    Return Value   : The results
+*/
+
+func run( serial string, runID string, minute int, passes ...bool ) []results.TestResult {
+  list := []results.TestResult{}
+
+  for i, pass := range passes {
+    r := results.New( results.RANGE )
+
+    r.RunID = runID
+    r.AssemblyPartNumber = "251470-100"
+    r.AssemblySerialNumber = serial
+    r.TestName = "80 Volt"
+    r.Timestamp = fmt.Sprintf("2026-10-05T10:%2.2d:%2.2d.000Z", minute, i)
+    r.Pass = pass
+
+    list = append(list, r)
+  }
+
+  return list
+}
+
+/*
+    Procedure Name : TestFirstPass
+
+    Description    : Checks that a unit passes first time only when every
+                     check of its first run passed, whatever a retest
+                     did, and that results without a run are judged by
+                     their first result.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestFirstPass( t *testing.T ) {
+  p, f := true, false
+
+  tests := []struct {
+    name string
+    runs [][]results.TestResult
+    want bool
+  }{
+    { "all passed", [][]results.TestResult{ run( "A", "r1", 0, p, p, p, p, p, p, p, p ) }, true },
+
+    { "fifth check failed", [][]results.TestResult{ run( "A", "r1", 0, p, p, p, p, f, p, p, p ) }, false },
+
+    { "fifth check failed, retest passed", [][]results.TestResult{
+        run( "A", "r1", 0, p, p, p, p, f, p, p, p ),
+        run( "A", "r2", 5, p, p, p, p, p, p, p, p ) }, false },
+
+    { "first run passed, retest failed", [][]results.TestResult{
+        run( "A", "r1", 0, p, p, p, p, p, p, p, p ),
+        run( "A", "r2", 5, p, f, p, p, p, p, p, p ) }, true },
+
+    { "no run, first result passed", [][]results.TestResult{ run( "A", "", 0, p, f, p ) }, true },
+
+    { "no run, first result failed", [][]results.TestResult{ run( "A", "", 0, f, p, p ) }, false },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      var list []results.TestResult
+
+      for i := len(tt.runs) - 1;i >= 0;i-- {
+        list = append(list, tt.runs[i]...)
+      }
+
+      a := Analyze( list )
+
+      if len(a.Assemblies) != 1 || a.Assemblies[0].Units != 1 {
+        t.Fatalf("assemblies %+v, want one unit", a.Assemblies)
+      }
+
+      if got := a.Assemblies[0].FirstPass == 1; got != tt.want {
+        t.Errorf("first pass %v, want %v", got, tt.want)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestPareto
+
+    Description    : Checks that the failure Pareto is sorted worst first,
+                     by name among equals, with the cumulative share
+                     reaching 100%.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestPareto( t *testing.T ) {
+  var list []results.TestResult
+
+  for i, name := range []string{ "B", "A", "C", "A", "B", "A" } {
+    r := results.New( results.PASS_FAIL )
+
+    r.AssemblySerialNumber = fmt.Sprint( i )
+    r.TestName = name
+
+    list = append(list, r)
+  }
+
+  a := Analyze( list )
+
+  want := []Failure{ { "A", 3, 50, 50 }, { "B", 2, 100.0/3, 50 + 100.0/3 }, { "C", 1, 100.0/6, 100 } }
+
+  if len(a.Pareto) != len(want) {
+    t.Fatalf("Pareto %+v, want %+v", a.Pareto, want)
+  }
+
+  for i := 0;i < len(want);i++ {
+    got := a.Pareto[i]
+
+    if got.Name != want[i].Name || got.Failures != want[i].Failures || math.Abs( got.Percent - want[i].Percent ) > 1e-9 || math.Abs( got.Cumulative - want[i].Cumulative ) > 1e-9 {
+      t.Errorf("Pareto[%d] = %+v, want %+v", i, got, want[i])
+    }
+  }
+}
@@ -0,0 +1,109 @@
+package analyze
+
+import (
+        "encoding/csv"
+        "fmt"
+        "io"
+        "text/tabwriter"
+)
+
+/*
+    Procedure Name : yields
+
+    Description    : Writes one table of first pass yields.
+
+    Arguments      : w      - Where to write it
+                     title  - Heading of the group column
+                     list   - Yields
+
+    Return Value   : This routine has no return value.
+*/
+
+func yields( w io.Writer, title string, list []Yield ) {
+  fmt.Fprintf(w, "%s\tUnits\tFirst Pass\tFPY\n", title)
+
+  for _, y := range list {
+    fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\n", y.Group, y.Units, y.FirstPass, y.FPY())
+  }
+
+  fmt.Fprintf(w, "\n")
+}
+
+/*
+    Procedure Name : Text
+
+    Description    : Writes the analysis as plain text tables.
+
+    Arguments      : w   - Where to write it
+                     top - Number of tests in the Pareto, 0 for all
+
+    Return Value   : Any error writing
+*/
+
+func (a Analysis) Text( w io.Writer, top int ) error {
+  t := tabwriter.NewWriter( w, 0, 8, 2, ' ', 0 )
+
+  fmt.Fprintf(t, "%d results\n\nFIRST PASS YIELD\n\n", a.Results)
+
+  yields( t, "Assembly", a.Assemblies )
+  yields( t, "Module", a.Modules )
+  yields( t, "Week", a.Weeks )
+
+  if len(a.Tests) > 0 {
+    yields( t, "Assembly and Test (all runs)", a.Tests )
+  }
+
+  fmt.Fprintf(t, "FAILURE PARETO\n\n")
+  fmt.Fprintf(t, "Test\tFailures\tShare\tCumulative\n")
+
+  for i, f := range a.Pareto {
+    if top > 0 && i >= top {
+      break
+    }
+
+    fmt.Fprintf(t, "%s\t%d\t%.1f%%\t%.1f%%\n", f.Name, f.Failures, f.Percent, f.Cumulative)
+  }
+
+  return t.Flush()
+}
+
+/*
+    Procedure Name : CSV
+
+    Description    : Writes the analysis as CSV for a spreadsheet. The
+                     first column says which table each row belongs to.
+
+    Arguments      : w   - Where to write it
+                     top - Number of tests in the Pareto, 0 for all
+
+    Return Value   : Any error writing
+*/
+
+func (a Analysis) CSV( w io.Writer, top int ) error {
+  c := csv.NewWriter( w )
+
+  c.Write( []string{ "table", "group", "units", "firstpass", "fpy" } )
+
+  for _, table := range []struct {
+    name string
+    list []Yield
+  }{ { "assembly", a.Assemblies }, { "module", a.Modules }, { "week", a.Weeks }, { "test", a.Tests } } {
+    for _, y := range table.list {
+      c.Write( []string{ table.name, y.Group, fmt.Sprint( y.Units ), fmt.Sprint( y.FirstPass ), fmt.Sprintf("%.1f", y.FPY()) } )
+    }
+  }
+
+  c.Write( []string{ "table", "name", "failures", "percent", "cumulative" } )
+
+  for i, f := range a.Pareto {
+    if top > 0 && i >= top {
+      break
+    }
+
+    c.Write( []string{ "pareto", f.Name, fmt.Sprint( f.Failures ), fmt.Sprintf("%.1f", f.Percent), fmt.Sprintf("%.1f", f.Cumulative) } )
+  }
+
+  c.Flush()
+
+  return c.Error()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+        "flag"
+        "fmt"
+        "io"
+        "os"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/analyze"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/*
+    Procedure Name : datedResults
+
+    Description    : Reads the results stored between two dates from the
+                     by_date view.
+
+    Arguments      : db   - Test database
+                     from - First day, zero for no limit
+                     to   - Last day, zero for no limit
+
+    Return Value   : The results, oldest first
+                     Any error querying the view
+*/
+
+func datedResults( db *couchdb.Database, from time.Time, to time.Time ) ([]results.TestResult, error) {
+  options := map[string]interface{}{ "include_docs": true }
+
+  if !from.IsZero() {
+    options["startkey"] = results.Timestamp( from )
+  }
+
+  if !to.IsZero() {
+    options["endkey"] = results.Timestamp( to.AddDate( 0, 0, 1 ) )
+    options["inclusive_end"] = false
+  }
+
+  v, err := db.View( "results/by_date", nil, options )
+
+  var rows []couchdb.Row
+
+  if err == nil {
+    rows, err = v.Rows()
+  }
+
+  list := []results.TestResult{}
+
+  for i := 0;i < len(rows) && err == nil;i++ {
+    var r results.TestResult
+
+    err = decode( rows[i].Doc, &r )
+
+    list = append(list, r)
+  }
+
+  return list, err
+}
+
+/*
+    Procedure Name : testYields
+
+    Description    : Reads the first pass yield of every assembly part
+                     number and test from the first_pass view, over every
+                     run stored.
+
+    Arguments      : db - Test database
+
+    Return Value   : Yields grouped as "part number / test"
+                     Any error querying the view
+*/
+
+func testYields( db *couchdb.Database ) ([]analyze.Yield, error) {
+  v, err := db.View( "results/first_pass", nil, map[string]interface{}{ "group_level": 2 } )
+
+  var rows []couchdb.Row
+
+  if err == nil {
+    rows, err = v.Rows()
+  }
+
+  list := []analyze.Yield{}
+
+  for i := 0;i < len(rows) && err == nil;i++ {
+    var key []string
+    var counts struct {
+      Units int `json:"units"`
+      FirstPass int `json:"first_pass"`
+    }
+
+    err = decode( rows[i].Key, &key )
+
+    if err == nil {
+      err = decode( rows[i].Val, &counts )
+    }
+
+    if err == nil && len(key) == 2 {
+      list = append(list, analyze.Yield{ Group: key[0] + " / " + key[1], Units: counts.Units, FirstPass: counts.FirstPass })
+    }
+  }
+
+  return list, err
+}
+
+/*
+    Procedure Name : RunAnalyze
+
+    Description    : The analyze subcommand. Writes the first pass yields
+                     and the failure Pareto of the stored results, and
+                     the first pass yield of each test over every run.
+
+                       iemtestdb analyze [-from 2026-01-01] [-to 2026-03-31] [-top 10] [-format text|csv] [-o file]
+
+    Arguments      : args - Command line after "analyze"
+
+    Return Value   : Any error making the analysis
+*/
+
+func RunAnalyze( args []string ) error {
+  flags := flag.NewFlagSet( "analyze", flag.ContinueOnError )
+
+  configFile := flags.String("config", DEFAULT_CONFIG_FILE, "tester configuration file")
+  fromDate := flags.String("from", "", "first day to analyse, YYYY-MM-DD in UTC")
+  toDate := flags.String("to", "", "last day to analyse, YYYY-MM-DD in UTC")
+  top := flags.Int("top", 10, "number of tests in the failure Pareto, 0 for all")
+  format := flags.String("format", "text", "text or csv")
+  output := flags.String("o", "", "file to write, standard output if not given")
+
+  if flags.Parse( args ) != nil {
+    return ExitError{ EXIT_TESTER }
+  }
+
+  var from, to time.Time
+  var err error
+
+  if *fromDate != "" {
+    from, err = time.Parse( "2006-01-02", *fromDate )
+  }
+
+  if err == nil && *toDate != "" {
+    to, err = time.Parse( "2006-01-02", *toDate )
+  }
+
+  if err == nil {
+    Settings, err = LoadConfig( *configFile )
+  }
+
+  var db *couchdb.Database
+
+  if err == nil {
+    db, err = couchdb.NewDatabase( Settings.Database )
+  }
+
+  if err == nil {
+    _, err = results.InstallViews( db )
+  }
+
+  var list []results.TestResult
+
+  if err == nil {
+    list, err = datedResults( db, from, to )
+  }
+
+  var tests []analyze.Yield
+
+  if err == nil {
+    tests, err = testYields( db )
+  }
+
+  if err != nil {
+    return err
+  }
+
+  analysis := analyze.Analyze( list )
+  analysis.Tests = tests
+
+  var w io.Writer = os.Stdout
+
+  if *output != "" {
+    f, err := os.Create( *output )
+
+    if err != nil {
+      return err
+    }
+
+    defer f.Close()
+
+    w = f
+  }
+
+  switch *format {
+    case "text" :
+      return analysis.Text( w, *top )
+
+    case "csv" :
+      return analysis.CSV( w, *top )
+  }
+
+  return fmt.Errorf("Unknown analysis format %q", *format)
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+        "bufio"
+        "encoding/json"
+        "flag"
+        "fmt"
+        "io"
+        "os"
+        "strings"
+
+        "github.com/questrail/IEMTestDB/iemclient"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* Exit codes of the run subcommand */
+
+const (
+  EXIT_PASS = 0                      // Every test passed and every result was saved
+  EXIT_FAIL = 1                      // The unit failed a test
+  EXIT_INCOMPLETE = 2                // A test was stopped by an error
+  EXIT_NOT_SAVED = 3                 // The unit passed but results could not be saved
+  EXIT_TESTER = 4                    // Bad arguments, or the tester could not start
+)
+
+/* Exit status returned by a subcommand that sets its own exit code */
+
+type ExitError struct {
+  Code int                           // Exit code
+}
+
+func (e ExitError) Error() string {
+  return fmt.Sprintf("exit status %d", e.Code)
+}
+
+/* Tests by their batch names, in the order they are run */
+
+var batchTests = []struct {
+  key string                         // Name given with -tests
+  name string                        // Name of the test, as shown on the menu
+}{
+  { "4-20", "4-20 mA Test" },
+  { "cpu", "CPU Main Test" },
+  { "abcm", "ABCM Test" },
+  { "adcm", "ADCM Test" },
+}
+
+/* Module numbers asked for at the menu, by their name in the modules file */
+
+type batchModule struct {
+  name string                        // Name in the modules file
+  identifier string                  // Name shown in messages
+  majors []int                       // Valid upper parts of the part number
+  minor int                          // Valid lower part, 0 for any
+  partNumber *string                 // Where the part number goes
+  serialNumber *string               // Where the serial number goes
+  majorPart *int                     // Where the upper part goes
+  minorPart *int                     // Where the lower part goes
+}
+
+var batchModules = []batchModule{
+  { "main", "IEM Main Circuit", []int{227411}, 100, &MainCircuitPartNumber, &MainCircuitSerialNumber, &MainCircuitMajorPartNumber, &MainCircuitMinorPartNumber },
+  { "power", "IEM Dual Power Supply", []int{227411}, 200, &DualPowerSupplyPartNumber, &DualPowerSupplySerialNumber, &DualPowerSupplyMajorPartNumber, &DualPowerSupplyMinorPartNumber },
+  { "cab", "IEM Cab Connector Card", []int{227411}, 300, &CabConnectorCardPartNumber, &CabConnectorSerialNumber, &CabConnectorMajorPartNumber, &CabConnectorMinorPartNumber },
+  { "io", "IEM IO Connector Card", []int{227411}, 400, &IOConnectorPartNumber, &IOConnectorSerialNumber, &IOConnectorMajorPartNumber, &IOConnectorMinorPartNumber },
+  { "4-20", "IEM 4-20mA", []int{227411}, 500, &IEM4_20mAPartNumber, &IEM4_20mASerialNumber, &IEM4_20mAMajorPartNumber, &IEM4_20mAMinorPartNumber },
+  { "abcm", "IEM Alerter Brake Control Module", []int{227411}, 600, &ABCMPartNumber, &ABCMSerialNumber, &ABCMMajorPartNumber, &ABCMMinorPartNumber },
+  { "adcm", "IEM Alerter Display Control Module", []int{250470}, 0, &ADCMPartNumber, &ADCMSerialNumber, &ADCMMajorPartNumber, &ADCMMinorPartNumber },
+}
+
+/* Summary written when a batch finishes */
+
+type BatchTest struct {
+  Test string `json:"test"`                               // Name of the test
+  RunID string `json:"run_id"`                            // Run holding its results
+  Verdict string `json:"verdict"`                         // Verdict of the run
+  Error string `json:"error,omitempty"`                   // Error that stopped the test
+  UnsavedResults int `json:"unsavedresults"`              // Results that could not be saved
+  Link *results.LinkStats `json:"link,omitempty"`          // IEM link counts over the run
+}
+
+type BatchSummary struct {
+  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
+  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
+  Operator string `json:"operator"`                         // Operator running the tests
+  Station string `json:"station"`                           // Tester the tests ran on
+  Verdict string `json:"verdict,omitempty"`                 // Verdict of the whole batch, left out if no test ran
+  ExitCode int `json:"exitcode"`                            // Exit code of the tester
+  Tests []BatchTest `json:"tests"`                          // One entry for every test run
+  Pending int `json:"pending"`                              // Results still waiting in the journal
+  Link *results.LinkStats `json:"link,omitempty"`            // IEM link counts over the whole batch
+  Error string `json:"error,omitempty"`                     // Why the tests could not be run
+}
+
+/* Console input that answers every question the same way, each answer
+   followed by a new line */
+
+type answerReader byte
+
+func (a answerReader) Read( p []byte ) (int, error) {
+  for i := 0;i < len(p);i++ {
+    if i % 2 == 0 {
+      p[i] = (byte) (a)
+    } else {
+      p[i] = '\n'
+    }
+  }
+
+  return len(p), nil
+}
+
+/*
+    Procedure Name : checkNumberPair
+
+    Description    : Checks a part number and serial number the way they
+                     are checked when they are typed in at the menu.
+
+    Arguments      : m            - Module the numbers belong to
+                     partNumber   - Part number
+                     serialNumber - Serial number
+
+    Return Value   : Upper part of the part number
+                     Lower part of the part number
+                     An error if either number is not valid
+*/
+
+func checkNumberPair( m batchModule, partNumber string, serialNumber string ) (int, int, error) {
+  var firstPart int
+  var secondPart int
+
+  if !ValidatePartNumber( []byte(partNumber) ) {
+    return 0, 0, fmt.Errorf("Invalid %s Part Number %q", m.identifier, partNumber)
+  }
+
+  if !ValidateSerialNumber( []byte(serialNumber) ) {
+    return 0, 0, fmt.Errorf("Invalid %s Serial Number %q", m.identifier, serialNumber)
+  }
+
+  fmt.Sscanf( partNumber[:6], "%d", &firstPart )
+
+  fmt.Sscanf( partNumber[7:], "%d", &secondPart )
+
+  for i := 0;i < len(m.majors);i++ {
+    if m.majors[i] == firstPart && (m.minor == 0 || m.minor == secondPart) {
+      return firstPart, secondPart, nil
+    }
+  }
+
+  return 0, 0, fmt.Errorf("Invalid %s Part Number %q", m.identifier, partNumber)
+}
+
+/*
+    Procedure Name : setAssembly
+
+    Description    : Sets the top level assembly numbers.
+
+    Arguments      : partNumber   - Assembly part number
+                     serialNumber - Assembly serial number
+
+    Return Value   : Any error if either number is not valid
+*/
+
+func setAssembly( partNumber string, serialNumber string ) error {
+  assembly := batchModule{ identifier: "Assembly", majors: []int{251470, 251469, 250470} }
+
+  major, minor, err := checkNumberPair( assembly, partNumber, serialNumber )
+
+  if err == nil {
+    AssemblyPartNumber, AssemblySerialNumber = partNumber, serialNumber
+    AssemblyMajorPartNumber, AssemblyMinorPartNumber = major, minor
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : setModules
+
+    Description    : Sets the module numbers of the assembly from a modules
+                     file, a JSON list in the same form as the modules of a
+                     run:
+
+                       [ { "name": "main", "partnumber": "227411-100",
+                           "serialnumber": "1234QE00002" }, ... ]
+
+                     Every module of the assembly must be given.
+
+    Arguments      : path    - Modules file
+                     alerter - Top level assembly, as returned by
+                               AssemblyType
+
+    Return Value   : Any error reading the file or a missing or invalid
+                     module
+*/
+
+func setModules( path string, alerter int ) error {
+  if alerter == IEM_ADCM_ONLY {
+    return nil
+  }
+
+  if path == "" {
+    return fmt.Errorf("-modules is needed for assembly %s", AssemblyPartNumber)
+  }
+
+  data, err := os.ReadFile( path )
+
+  var list []results.Module
+
+  if err == nil {
+    err = json.Unmarshal( data, &list )
+  }
+
+  if err != nil {
+    return fmt.Errorf("%s: %s", path, err)
+  }
+
+  err = applyModules( list, alerter )
+
+  if err != nil {
+    return fmt.Errorf("%s: %s", path, err)
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : applyModules
+
+    Description    : Sets the module numbers of the assembly from a list
+                     of modules, such as those of a run. Every module of
+                     the assembly must be in the list.
+
+    Arguments      : list    - Modules with their numbers
+                     alerter - Top level assembly, as returned by
+                               AssemblyType
+
+    Return Value   : Any missing or invalid module
+*/
+
+func applyModules( list []results.Module, alerter int ) error {
+  given := map[string]results.Module{}
+
+  for _, m := range list {
+    given[m.Name] = m
+  }
+
+  for _, m := range batchModules {
+    if alerter != IEM_WITH_ALERTER && (m.name == "abcm" || m.name == "adcm") {
+      continue
+    }
+
+    numbers, ok := given[m.name]
+
+    if !ok {
+      return fmt.Errorf("no %q module", m.name)
+    }
+
+    major, minor, err := checkNumberPair( m, numbers.PartNumber, numbers.SerialNumber )
+
+    if err != nil {
+      return err
+    }
+
+    *m.partNumber, *m.serialNumber = numbers.PartNumber, numbers.SerialNumber
+    *m.majorPart, *m.minorPart = major, minor
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : selectTests
+
+    Description    : Picks the tests to run from the -tests list. The tests
+                     are run in menu order whatever order they are given
+                     in, and only the tests on the menu for the assembly
+                     may be asked for.
+
+    Arguments      : list    - Comma separated batch test names, all of the
+                               assembly's tests if empty
+                     alerter - Top level assembly, as returned by
+                               AssemblyType
+
+    Return Value   : Names of the tests to run
+                     Any error naming a test
+*/
+
+func selectTests( list string, alerter int ) ([]string, error) {
+  available := map[string]bool{}
+
+  switch alerter {
+    case IEM_WITH_ALERTER :
+      available = map[string]bool{ "4-20": true, "cpu": true, "abcm": true, "adcm": true }
+
+    case IEM_WITHOUT_ALERTER :
+      available = map[string]bool{ "4-20": true, "cpu": true }
+
+    case IEM_ADCM_ONLY :
+      available = map[string]bool{ "adcm": true }
+  }
+
+  wanted := map[string]bool{}
+
+  for _, key := range strings.Split( list, "," ) {
+    key = strings.ToLower( strings.TrimSpace( key ) )
+
+    if key == "" {
+      continue
+    }
+
+    if !available[key] {
+      return nil, fmt.Errorf("Test %q is not available for assembly %s", key, AssemblyPartNumber)
+    }
+
+    wanted[key] = true
+  }
+
+  tests := []string{}
+
+  for _, t := range batchTests {
+    if available[t.key] && (len(wanted) == 0 || wanted[t.key]) {
+      tests = append(tests, t.name)
+    }
+  }
+
+  return tests, nil
+}
+
+/*
+    Procedure Name : RunBatch
+
+    Description    : The run subcommand. Tests one unit without the menu,
+                     for line control software and for CI against the
+                     simulator:
+
+                       iemtestdb run -assembly 251470-001 -serial 1234QE00001
+                                     -modules modules.json -tests cpu,abcm
+
+                     Progress goes to standard error and a JSON summary
+                     to standard output (or the -o file). The exit code
+                     gives the verdict: EXIT_PASS, EXIT_FAIL,
+                     EXIT_INCOMPLETE, EXIT_NOT_SAVED or EXIT_TESTER.
+
+    Arguments      : args - Command line after "run"
+
+    Return Value   : ExitError with the exit code when it is not EXIT_PASS
+*/
+
+func RunBatch( args []string ) error {
+  flags := flag.NewFlagSet( "run", flag.ContinueOnError )
+
+  configFile := flags.String("config", DEFAULT_CONFIG_FILE, "tester configuration file")
+  assembly := flags.String("assembly", "", "assembly part number")
+  serial := flags.String("serial", "", "assembly serial number")
+  moduleFile := flags.String("modules", "", "JSON file of the module part and serial numbers")
+  tests := flags.String("tests", "", "comma separated tests to run: 4-20, cpu, abcm, adcm (all by default)")
+  answer := flags.String("answer", "", "answer every operator question with this, such as y; read standard input if not given")
+  output := flags.String("o", "", "file to write the summary to, standard output if not given")
+
+  flags.StringVar(&Operator, "operator", "batch", "name of the operator or line controller running the tests")
+
+  err := flags.Parse( args )
+
+  if err != nil {
+    return ExitError{ EXIT_TESTER }
+  }
+
+  /*
+      Standard output is kept for the summary; everything the tests print
+      goes to standard error.
+  */
+
+  var w io.Writer = os.Stdout
+
+  os.Stdout = os.Stderr
+
+  if *output != "" {
+    f, err := os.Create( *output )
+
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "%s\n", err)
+
+      return ExitError{ EXIT_TESTER }
+    }
+
+    defer f.Close()
+
+    w = f
+  }
+
+  if *answer != "" {
+    ConsoleSource = answerReader( (*answer)[0] )
+  }
+
+  ConsoleInput = bufio.NewReader( ConsoleSource )
+
+  summary := BatchSummary{ AssemblyPartNumber: *assembly, AssemblySerialNumber: *serial, Operator: Operator, Tests: []BatchTest{} }
+
+  code, err := runBatch( &summary, *configFile, *moduleFile, *tests )
+
+  if err != nil {
+    summary.Error = err.Error()
+
+    fmt.Fprintf(os.Stderr, "%s\n", err)
+  }
+
+  summary.ExitCode = code
+
+  encoder := json.NewEncoder( w )
+
+  encoder.SetIndent( "", "  " )
+
+  encoder.Encode( summary )
+
+  if code != EXIT_PASS {
+    return ExitError{ code }
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : runBatch
+
+    Description    : Starts the tester and runs the tests of a batch,
+                     filling in the summary as it goes.
+
+    Arguments      : summary    - Summary to fill in
+                     configFile - Tester configuration file
+                     moduleFile - Modules file
+                     tests      - Comma separated batch test names
+
+    Return Value   : Exit code
+                     Any error that kept the tests from being run
+*/
+
+func runBatch( summary *BatchSummary, configFile string, moduleFile string, tests string ) (int, error) {
+  err := setAssembly( summary.AssemblyPartNumber, summary.AssemblySerialNumber )
+
+  if err != nil {
+    return EXIT_TESTER, err
+  }
+
+  alerter := AssemblyType()
+
+  err = setModules( moduleFile, alerter )
+
+  var names []string
+
+  if err == nil {
+    names, err = selectTests( tests, alerter )
+  }
+
+  if err == nil {
+    err = LoadSettings( configFile )
+  }
+
+  if err == nil {
+    err = OpenStorage()
+  }
+
+  if err != nil {
+    return EXIT_TESTER, err
+  }
+
+  defer CloseStorage()
+
+  summary.Station = Settings.Station
+
+  fix, err := PowerUp()
+
+  if fix == nil {
+    return EXIT_TESTER, err
+  }
+
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "%s\n", err)
+  }
+
+  if alerter > 0 {
+    WaitForModules()
+  }
+
+  /*
+      Every test is run, even after one fails, so the summary has the
+      whole unit. An interrupt stops the batch as incomplete.
+  */
+
+  code := EXIT_PASS
+  summary.Verdict = results.VERDICT_PASS
+
+  for _, name := range names {
+    _, err = RunTest( fix, name, alerter )
+
+    test := BatchTest{ Test: name, RunID: CurrentRun.RunID, Verdict: CurrentRun.Verdict, UnsavedResults: CurrentRun.UnsavedResults,
+                       Link: CurrentRun.Link }
+
+    if err != nil {
+      test.Error = err.Error()
+    }
+
+    summary.Tests = append(summary.Tests, test)
+
+    switch {
+      case test.Verdict == results.VERDICT_INCOMPLETE :
+        code = EXIT_INCOMPLETE
+        summary.Verdict = results.VERDICT_INCOMPLETE
+
+      case test.Verdict == results.VERDICT_FAIL && code != EXIT_INCOMPLETE :
+        code = EXIT_FAIL
+        summary.Verdict = results.VERDICT_FAIL
+
+      case test.UnsavedResults > 0 && code == EXIT_PASS :
+        code = EXIT_NOT_SAVED
+    }
+
+    if Interrupted {
+      break
+    }
+  }
+
+  PowerDown( fix )
+
+  summary.Link = LinkStats( IEM.Stats(), iemclient.Stats{} )
+
+  if ResultJournal != nil {
+    summary.Pending = ResultJournal.Status().Pending
+  }
+
+  return code, nil
+}
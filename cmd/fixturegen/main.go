@@ -0,0 +1,212 @@
+/*
+   fixturegen writes the fixture signal table from the bit map sheet of
+   the production tester test description spreadsheet, so the names used
+   by the tests always match the document.
+
+     fixturegen -o fixture/signals_gen.go Production_Tester_Test_Descriptions_A05.xlsx
+     fixturegen -check -o fixture/signals_gen.go Production_Tester_Test_Descriptions_A05.xlsx
+
+   With -check nothing is written; the program fails if the existing table
+   is out of date.
+*/
+
+package main
+
+import (
+        "bytes"
+        "flag"
+        "fmt"
+        "go/format"
+        "log"
+        "os"
+        "path/filepath"
+        "regexp"
+        "sort"
+        "strconv"
+        "strings"
+        "unicode"
+
+        "github.com/questrail/IEMTestDB/fixture"
+)
+
+var familyPattern = regexp.MustCompile(`^(.+)_(\d+)$`)
+
+/*
+    Procedure Name : identifier
+
+    Description    : Turns a spreadsheet bit description into a Go name,
+                     e.g. "Enable 80V Analog Outs 1 and 4" becomes
+                     SigEnable80VAnalogOuts1And4.
+
+    Arguments      : name - Bit description
+
+    Return Value   : Exported Go identifier
+*/
+
+func identifier( name string ) string {
+  name = strings.ReplaceAll( name, "±", " PM " )
+
+  words := strings.FieldsFunc( name, func( r rune ) bool {
+    return !unicode.IsLetter( r ) && !unicode.IsDigit( r )
+  })
+
+  id := "Sig"
+
+  for _, w := range words {
+    if strings.ToUpper( w ) == w {
+      id += w
+    } else {
+      id += strings.ToUpper( w[:1] ) + w[1:]
+    }
+  }
+
+  return id
+}
+
+/*
+    Procedure Name : generate
+
+    Description    : Builds the Go source of the signal table.
+
+    Arguments      : source  - Spreadsheet file the table came from
+                     signals - Signals read from the spreadsheet
+
+    Return Value   : Formatted Go source
+                     Any error, such as two signals with the same Go name
+*/
+
+func generate( source string, signals []fixture.Signal ) ([]byte, error) {
+  var b bytes.Buffer
+
+  fmt.Fprintf( &b, "// Code generated by fixturegen from %s. DO NOT EDIT.\n\n", filepath.Base( source ) )
+  fmt.Fprintf( &b, "package fixture\n\n" )
+
+  ids := make(map[string]string)
+  families := make(map[string]map[int]string)
+
+  for _, s := range signals {
+    id := identifier( s.Name )
+
+    if other, ok := ids[id]; ok {
+      return nil, fmt.Errorf("%q and %q both become %s", other, s.Name, id)
+    }
+
+    ids[id] = s.Name
+
+    direction := "Output"
+
+    if s.Direction == fixture.Input {
+      direction = "Input"
+    }
+
+    polarity := "ActiveHigh"
+
+    if s.Polarity == fixture.ActiveLow {
+      polarity = "ActiveLow"
+    }
+
+    fmt.Fprintf( &b, "// %s, port extender %d %s\n", s.Name, s.Chip, s.Port() )
+    fmt.Fprintf( &b, "var %s = Signal{ Name: %q, Chip: %d, Bit: %d, Direction: %s, Polarity: %s,\n  Group: %q,\n  Description: %q }\n\n",
+                 id, s.Name, s.Chip, s.Bit, direction, polarity, s.Group, s.Description )
+
+    if m := familyPattern.FindStringSubmatch( s.Name ); m != nil {
+      n, _ := strconv.Atoi( m[2] )
+
+      if families[m[1]] == nil {
+        families[m[1]] = make(map[int]string)
+      }
+
+      families[m[1]][n] = id
+    }
+  }
+
+  /*
+      Numbered signals such as 74V_NOGO_1 to 74V_NOGO_34 are also listed
+      in order so the tests can walk through them.
+  */
+
+  names := make([]string, 0, len(families))
+
+  for name := range families {
+    names = append(names, name)
+  }
+
+  sort.Strings( names )
+
+  for _, name := range names {
+    members := families[name]
+
+    fmt.Fprintf( &b, "// %s_1 to %s_%d in order\n", name, name, len(members) )
+    fmt.Fprintf( &b, "var %s = []Signal{\n", identifier( name ) )
+
+    for n := 1;n <= len(members);n++ {
+      id, ok := members[n]
+
+      if !ok {
+        return nil, fmt.Errorf("%s_%d is missing from the spreadsheet", name, n)
+      }
+
+      fmt.Fprintf( &b, "  %s,\n", id )
+    }
+
+    fmt.Fprintf( &b, "}\n\n" )
+  }
+
+  fmt.Fprintf( &b, "// Every signal keyed by its spreadsheet description\n" )
+  fmt.Fprintf( &b, "var Signals = map[string]Signal{\n" )
+
+  for _, s := range signals {
+    fmt.Fprintf( &b, "  %q: %s,\n", s.Name, identifier( s.Name ) )
+  }
+
+  fmt.Fprintf( &b, "}\n" )
+
+  return format.Source( b.Bytes() )
+}
+
+func main() {
+  output := flag.String("o", "signals_gen.go", "Go file to write")
+  check := flag.Bool("check", false, "Fail if the Go file does not match the spreadsheet")
+
+  flag.Parse()
+
+  if flag.NArg() != 1 {
+    log.Fatal("usage: fixturegen [-check] [-o file] spreadsheet.xlsx")
+  }
+
+  source := flag.Arg(0)
+
+  signals, warnings, err := fixture.LoadSpreadsheet( source )
+
+  if err != nil {
+    log.Fatal(err)
+  }
+
+  for _, w := range warnings {
+    log.Printf("warning: %s", w)
+  }
+
+  code, err := generate( source, signals )
+
+  if err != nil {
+    log.Fatal(err)
+  }
+
+  if *check {
+    existing, err := os.ReadFile( *output )
+
+    if err != nil {
+      log.Fatal(err)
+    }
+
+    if !bytes.Equal( existing, code ) {
+      log.Fatalf("%s does not match %s, run go generate", *output, source)
+    }
+
+    return
+  }
+
+  if err = os.WriteFile( *output, code, 0644 ); err != nil {
+    log.Fatal(err)
+  }
+}
@@ -0,0 +1,113 @@
+/*
+   iemsim runs the IEM simulator as a separate process. It can present a
+   pseudo terminal for the tester to open as its serial port, listen for
+   TCP connections, or both.
+
+     iemsim -pty -script faults.json
+     iemsim -listen :4001
+*/
+
+package main
+
+import (
+        "flag"
+        "io"
+        "log"
+        "net"
+
+        "github.com/questrail/IEMTestDB/iemsim"
+)
+
+/*
+   The simulator treats io.EOF as a read timeout, the way the serial
+   port reports one. A TCP connection returns io.EOF when the tester
+   hangs up, so it is reported as a closed link instead.
+*/
+
+type tcpLink struct {
+  net.Conn
+}
+
+func (l tcpLink) Read( p []byte ) (int, error) {
+  n, err := l.Conn.Read( p )
+
+  if err == io.EOF {
+    err = io.ErrClosedPipe
+  }
+
+  return n, err
+}
+
+func main() {
+  usePty := flag.Bool("pty", false, "Serve the simulator on a pseudo terminal")
+  listen := flag.String("listen", "", "Serve the simulator on this TCP address")
+  scriptFile := flag.String("script", "", "JSON script of responses and faults")
+
+  flag.Parse()
+
+  if !*usePty && *listen == "" {
+    log.Fatal("Nothing to do, give -pty and/or -listen")
+  }
+
+  sim := iemsim.New()
+
+  if *scriptFile != "" {
+    script, err := iemsim.LoadScript( *scriptFile )
+
+    if err == nil {
+      err = sim.Apply( script )
+    }
+
+    if err != nil {
+      log.Fatal(err)
+    }
+  }
+
+  done := make(chan error)
+
+  if *usePty {
+    master, name, err := iemsim.OpenPty()
+
+    if err != nil {
+      log.Fatal(err)
+    }
+
+    log.Printf("Simulated IEM on %s", name)
+
+    go func() {
+      done <- sim.Serve( master )
+    }()
+  }
+
+  if *listen != "" {
+    listener, err := net.Listen( "tcp", *listen )
+
+    if err != nil {
+      log.Fatal(err)
+    }
+
+    log.Printf("Simulated IEM listening on %s", listener.Addr())
+
+    go func() {
+      for {
+        conn, err := listener.Accept()
+
+        if err != nil {
+          done <- err
+
+          return
+        }
+
+        go func() {
+          defer conn.Close()
+
+          if err := sim.Serve( tcpLink{ conn } ); err != nil {
+            log.Printf("Connection from %s closed: %v", conn.RemoteAddr(), err)
+          }
+        }()
+      }
+    }()
+  }
+
+  log.Fatal(<-done)
+}
@@ -0,0 +1,116 @@
+/*
+   migrateresults rewrites the test results in a test database in the
+   current result schema. Results written by earlier versions of the
+   tester carry one of seven layouts and no schema version; each is given
+   its kind and moved onto the common field names. Results without a
+   UTC timestamp are given one from their local date and time, read in
+   the time zone given with -tz. Documents that are already current are
+   left alone, so the tool can be run more than once. The views are
+   installed or upgraded afterwards.
+
+     migrateresults -db http://127.0.0.1:5984/testdb -tz America/Chicago -n
+*/
+
+package main
+
+import (
+        "flag"
+        "fmt"
+        "log"
+        "strings"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+func main() {
+  dbURL := flag.String("db", "http://127.0.0.1:5984/testdb", "URL of the test database")
+  dryRun := flag.Bool("n", false, "Report what would change without writing it")
+  zone := flag.String("tz", "Local", "Time zone of the tester that stored the results")
+
+  flag.Parse()
+
+  loc, err := time.LoadLocation( *zone )
+
+  if err != nil {
+    log.Fatalf("Unknown time zone %s: %s", *zone, err)
+  }
+
+  db, err := couchdb.NewDatabase( *dbURL )
+
+  var ids []string
+
+  if err == nil {
+    ids, err = db.DocIDs()
+  }
+
+  if err != nil {
+    log.Fatalf("Could not open %s: %s", *dbURL, err)
+  }
+
+  migrated, current, failed := 0, 0, 0
+
+  for _, id := range ids {
+    if strings.HasPrefix( id, "_design/" ) {
+      continue
+    }
+
+    doc, err := db.Get( id, nil )
+
+    if err != nil {
+      log.Printf("%s: %s", id, err)
+      failed++
+      continue
+    }
+
+    changed, err := results.Migrate( doc, loc )
+
+    if err != nil {
+      log.Printf("%s: %s", id, err)
+      failed++
+      continue
+    }
+
+    if !changed {
+      current++
+      continue
+    }
+
+    if !*dryRun {
+      err = db.Set( id, doc )
+    }
+
+    if err != nil {
+      log.Printf("%s: %s", id, err)
+      failed++
+      continue
+    }
+
+    migrated++
+  }
+
+  if *dryRun {
+    fmt.Printf("%d documents would be migrated, %d left alone, %d failed\n", migrated, current, failed)
+  } else {
+    fmt.Printf("%d documents migrated, %d left alone, %d failed\n", migrated, current, failed)
+
+    /*
+        The views only index current documents, so bring them up to date
+        along with the documents.
+    */
+
+    written, err := results.InstallViews( db )
+
+    if err != nil {
+      log.Printf("Could not install the views: %s", err)
+      failed++
+    } else if written {
+      fmt.Printf("Installed views version %d\n", results.DESIGN_VERSION)
+    }
+  }
+
+  if failed > 0 {
+    log.Fatal("Some documents were not migrated")
+  }
+}
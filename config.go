@@ -0,0 +1,345 @@
+package main
+
+import (
+        "encoding/json"
+        "fmt"
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/iemclient"
+        "github.com/questrail/IEMTestDB/iemsim"
+        "github.com/questrail/IEMTestDB/sinks"
+        "github.com/questrail/IEMTestDB/transport"
+        "os"
+        "time"
+)
+
+const DEFAULT_CONFIG_FILE = "iemtestdb.json"
+
+/* Configuration Structures */
+
+// IEM link configuration
+
+type TransportConfig struct {
+  Type string `json:"type"`                   // "serial", "tcp", "simulator" or "replay"
+  Device string `json:"device"`               // Serial device name
+  Baud int `json:"baud"`                       // Serial baud rate
+  Address string `json:"address"`             // host:port of a networked serial server
+  ReadTimeout string `json:"readtimeout"`     // How long one read waits for bytes, such as "100ms"
+  DialTimeout int `json:"dialtimeout"`         // Seconds allowed to connect to a networked serial server
+  Script string `json:"script"`               // Simulator script file, optional
+}
+
+// IEM request configuration
+
+type ProtocolConfig struct {
+  Timeout string `json:"timeout"`             // Time allowed for a response, such as "2s"
+  Retries int `json:"retries"`                // Times a failed request is sent again
+  Backoff string `json:"backoff"`             // Pause before a request is sent again
+  Timeouts map[string]string `json:"timeouts"` // Timeouts of slower selectors, by constant name
+}
+
+// Result sink configuration
+
+type SinkConfig struct {
+  Type string `json:"type"`                   // "couchdb", "sqlite", "csv" or "jsonl"
+  Path string `json:"path"`                   // File or directory of a local sink
+}
+
+// Tester configuration
+
+type Config struct {
+  Transport TransportConfig `json:"transport"` // Link to the IEM
+  Protocol ProtocolConfig `json:"protocol"`    // Timeouts and retries of IEM requests
+  Fixture string `json:"fixture"`             // "mcp" for the port extenders or "fake"
+  Limits string `json:"limits"`               // Test limits file
+  Sequences string `json:"sequences"`         // Directory of test sequence files
+  Station string `json:"station"`             // Name of this tester, the host name by default
+  Database string `json:"database"`           // URL of the test database
+  Journal string `json:"journal"`             // Directory of results waiting to be saved
+  Sinks []SinkConfig `json:"sinks"`           // Where results are saved
+  Trace string `json:"trace"`                 // Directory of protocol traces, none if empty
+  Record string `json:"record"`               // Directory of session recordings, none if empty
+}
+
+/*
+    Procedure Name : DefaultConfig
+
+    Description    : Returns the configuration of the original test bench.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The default configuration
+*/
+
+func DefaultConfig() Config {
+  station, _ := os.Hostname()
+
+  return Config{
+    Transport: TransportConfig{ Type: "serial", Device: "/dev/ttyS0", Baud: 9600, ReadTimeout: "100ms" },
+    Protocol: ProtocolConfig{ Timeout: "2s", Retries: iemclient.DEFAULT_RETRIES, Backoff: "50ms" },
+    Fixture: "mcp",
+    Limits: DEFAULT_LIMITS_FILE,
+    Sequences: DEFAULT_SEQUENCE_DIR,
+    Station: station,
+    Database: DEFAULT_DATABASE,
+    Journal: DEFAULT_JOURNAL_DIR,
+    Sinks: []SinkConfig{ { Type: "couchdb" } },
+  }
+}
+
+/*
+    Procedure Name : LoadConfig
+
+    Description    : Reads the tester configuration file. Any setting that
+                     is not in the file keeps its default value. A missing
+                     file is not an error.
+
+    Arguments      : path - Name of the configuration file
+
+    Return Value   : The configuration
+                     Any error reading or decoding the file
+*/
+
+func LoadConfig( path string ) (Config, error) {
+  config := DefaultConfig()
+
+  data, err := os.ReadFile( path )
+
+  if os.IsNotExist( err ) {
+    return config, nil
+  }
+
+  if err == nil {
+    err = json.Unmarshal( data, &config )
+  }
+
+  return config, err
+}
+
+/*
+    Procedure Name : OpenTransport
+
+    Description    : Opens the link to the IEM selected by the configuration.
+
+    Arguments      : c - Link configuration
+
+    Return Value   : The open transport
+                     Any error opening the link
+*/
+
+func OpenTransport( c TransportConfig ) (transport.Transport, error) {
+  timeout := transport.DEFAULT_READ_TIMEOUT
+
+  if c.ReadTimeout != "" {
+    var err error
+
+    timeout, err = time.ParseDuration( c.ReadTimeout )
+
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  switch c.Type {
+    case "serial" :
+      return transport.OpenSerial( c.Device, c.Baud, timeout )
+
+    case "tcp" :
+      dialTimeout := transport.DEFAULT_DIAL_TIMEOUT
+
+      if c.DialTimeout > 0 {
+        dialTimeout = time.Duration(c.DialTimeout)*time.Second
+      }
+
+      return transport.DialTCP( c.Address, dialTimeout, timeout )
+
+    case "simulator" :
+      return OpenSimulator( c.Script, timeout )
+
+    case "replay" :
+      if ReplayPlayer == nil {
+        return nil, fmt.Errorf("No recording to replay")
+      }
+
+      return ReplayPlayer.Link( timeout ), nil
+  }
+
+  return nil, fmt.Errorf("Unknown transport type %q", c.Type)
+}
+
+/*
+    Procedure Name : OpenClient
+
+    Description    : Creates the IEM client on a link with the timeouts
+                     and retries of the configuration. The whole
+                     configuration is checked first, so a bad setting
+                     leaves the link untouched.
+
+    Arguments      : c    - Request configuration
+                     port - Link to the IEM
+
+    Return Value   : The client, nil if the configuration is bad
+                     Any error in the configuration
+*/
+
+func OpenClient( c ProtocolConfig, port transport.Transport ) (*iemclient.Client, error) {
+  var err error
+
+  policy := iemclient.Policy{ Timeout: iemclient.DEFAULT_TIMEOUT, Retries: c.Retries, Backoff: iemclient.DEFAULT_BACKOFF }
+  policies := map[byte]iemclient.Policy{}
+
+  if c.Timeout != "" {
+    policy.Timeout, err = time.ParseDuration( c.Timeout )
+  }
+
+  if err == nil && c.Backoff != "" {
+    policy.Backoff, err = time.ParseDuration( c.Backoff )
+  }
+
+  for name, timeout := range c.Timeouts {
+    if err != nil {
+      break
+    }
+
+    selector, ok := codeNames[name]
+
+    if !ok {
+      err = fmt.Errorf("Unknown selector %q in the protocol timeouts", name)
+
+      break
+    }
+
+    selectorPolicy := policy
+
+    selectorPolicy.Timeout, err = time.ParseDuration( timeout )
+
+    policies[(byte) (selector)] = selectorPolicy
+  }
+
+  if err != nil {
+    return nil, fmt.Errorf("protocol: %s", err)
+  }
+
+  /*
+      The client starts reading the link as soon as it is created.
+  */
+
+  client := iemclient.New( port )
+
+  client.Default = policy
+  client.Policies = policies
+
+  return client, nil
+}
+
+/*
+    Procedure Name : OpenSimulator
+
+    Description    : Starts a simulated IEM in the tester and returns a
+                     link to it.
+
+    Arguments      : script  - Simulator script file, empty for none
+                     timeout - Read timeout for the link
+
+    Return Value   : The tester end of the link
+                     Any error loading the script
+*/
+
+func OpenSimulator( script string, timeout time.Duration ) (transport.Transport, error) {
+  sim := iemsim.New()
+
+  if script != "" {
+    s, err := iemsim.LoadScript( script )
+
+    if err == nil {
+      err = sim.Apply( s )
+    }
+
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  tester, device := transport.Pipe( timeout )
+
+  go sim.Serve( device )
+
+  return tester, nil
+}
+
+/*
+    Procedure Name : OpenFixture
+
+    Description    : Opens the test fixture selected by the configuration.
+                     The fake fixture lets the tester run without the
+                     port extenders, usually with the simulator transport;
+                     the replay fixture plays back a recording.
+
+    Arguments      : kind - "mcp", "fake" or "replay"
+
+    Return Value   : The fixture, nil for an unknown type. A port
+                     extender that fails to open is reported in the
+                     error but the fixture is still returned.
+                     Any error opening the fixture
+*/
+
+func OpenFixture( kind string ) (fixture.Fixture, error) {
+  switch kind {
+    case "fake" :
+      return fixture.NewFake(), nil
+
+    case "mcp", "" :
+      return fixture.OpenMCP()
+
+    case "replay" :
+      if ReplayPlayer == nil {
+        return nil, fmt.Errorf("No recording to replay")
+      }
+
+      return ReplayPlayer.Fixture(), nil
+  }
+
+  return nil, fmt.Errorf("Unknown fixture type %q", kind)
+}
+
+/*
+    Procedure Name : OpenSink
+
+    Description    : Opens the result sink selected by the configuration.
+
+    Arguments      : c - Sink configuration
+
+    Return Value   : The sink
+                     Any error opening the sink
+*/
+
+func OpenSink( c SinkConfig ) (sinks.ResultSink, error) {
+  path := c.Path
+
+  switch c.Type {
+    case "couchdb" :
+      return OpenCouchDBSink()
+
+    case "sqlite" :
+      if path == "" {
+        path = "results.db"
+      }
+
+      return sinks.OpenSQLite( path )
+
+    case "csv" :
+      if path == "" {
+        path = "csv"
+      }
+
+      return sinks.OpenCSV( path )
+
+    case "jsonl" :
+      if path == "" {
+        path = "results.jsonl"
+      }
+
+      return sinks.OpenJSONL( path )
+  }
+
+  return nil, fmt.Errorf("Unknown sink type %q", c.Type)
+}
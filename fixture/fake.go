@@ -0,0 +1,189 @@
+package fixture
+
+import (
+        "fmt"
+        "sync"
+)
+
+/* Recorded Latch Change */
+
+type Change struct {
+  Chip int                           // Port extender number
+  Before uint16                      // Latch value before the change
+  After uint16                       // Latch value after the change
+}
+
+/* In-Memory Fixture */
+
+type Fake struct {
+  mu sync.Mutex                      // Protects everything below
+
+  latches Snapshot                   // Current output latches
+  directions Snapshot                // Port directions, 1 bits are inputs
+  inputs Snapshot                    // Pin states returned by ReadInputs
+  changes []Change                   // Every latch write, in order
+
+  OnChange func( chip int, latch uint16 ) // Called after each latch write, may be nil
+}
+
+/*
+    Procedure Name : NewFake
+
+    Description    : Creates an in-memory fixture with every latch and
+                     input at zero.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The fake fixture
+*/
+
+func NewFake() *Fake {
+  return &Fake{}
+}
+
+/*
+    Procedure Name : write
+
+    Description    : Records a latch write. The caller must hold the lock.
+
+    Arguments      : chip  - Port extender number
+                     latch - New latch value
+
+    Return Value   : An error for a chip that is not on the fixture
+*/
+
+func (f *Fake) write( chip int, latch uint16 ) error {
+  if chip < 0 || chip >= Chips {
+    return fmt.Errorf("No port extender %d", chip)
+  }
+
+  f.changes = append(f.changes, Change{ Chip: chip, Before: f.latches[chip], After: latch })
+  f.latches[chip] = latch
+
+  if f.OnChange != nil {
+    f.OnChange( chip, latch )
+  }
+
+  return nil
+}
+
+func (f *Fake) Configure( chip int, latch uint16, direction uint16 ) error {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  if err := f.write( chip, latch ); err != nil {
+    return err
+  }
+
+  f.directions[chip] = direction
+
+  return nil
+}
+
+func (f *Fake) Write( chip int, latch uint16 ) error {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  return f.write( chip, latch )
+}
+
+func (f *Fake) Update( chip int, clear uint16, set uint16 ) error {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  if chip < 0 || chip >= Chips {
+    return fmt.Errorf("No port extender %d", chip)
+  }
+
+  return f.write( chip, (f.latches[chip] &^ clear) | set )
+}
+
+func (f *Fake) Set( chip int, mask uint16 ) error {
+  return f.Update( chip, 0, mask )
+}
+
+func (f *Fake) Clear( chip int, mask uint16 ) error {
+  return f.Update( chip, mask, 0 )
+}
+
+func (f *Fake) ReadInputs( chip int ) (uint16, error) {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  if chip < 0 || chip >= Chips {
+    return 0, fmt.Errorf("No port extender %d", chip)
+  }
+
+  return f.inputs[chip], nil
+}
+
+func (f *Fake) Snapshot() Snapshot {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  return f.latches
+}
+
+func (f *Fake) Close() error {
+  return nil
+}
+
+/*
+    Procedure Name : SetInputs
+
+    Description    : Sets the pin states returned by ReadInputs.
+
+    Arguments      : chip   - Port extender number
+                     inputs - Pin states
+
+    Return Value   : This routine has no return value.
+*/
+
+func (f *Fake) SetInputs( chip int, inputs uint16 ) {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  f.inputs[chip] = inputs
+}
+
+/*
+    Procedure Name : Changes
+
+    Description    : Returns every latch write made so far.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The recorded changes, oldest first
+*/
+
+func (f *Fake) Changes() []Change {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  return append([]Change(nil), f.changes...)
+}
+
+/*
+    Procedure Name : Directions
+
+    Description    : Returns the port directions set with Configure.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The port directions, 1 bits are inputs
+*/
+
+func (f *Fake) Directions() Snapshot {
+  f.mu.Lock()
+
+  defer f.mu.Unlock()
+
+  return f.directions
+}
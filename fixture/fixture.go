@@ -0,0 +1,48 @@
+/*
+   Package fixture controls the test fixture's bank of MCP23S17 port
+   extenders. Each extender has two 8 bit ports. The output latch of an
+   extender is handled as one 16 bit value with port A in the low byte
+   and port B in the high byte, the same layout as the bit map in the
+   test description spreadsheet.
+
+   The fixture keeps its own copy of every output latch, so callers set
+   and clear bits by mask instead of keeping shadow registers of their
+   own.
+*/
+
+package fixture
+
+const Chips = 5                      // Number of port extenders on the fixture
+
+/* Latch Snapshot */
+
+type Snapshot [Chips]uint16
+
+/* Fixture Interface */
+
+type Fixture interface {
+  // Configure sets up an extender with an initial output latch and the
+  // port directions (a 1 bit is an input).
+  Configure( chip int, latch uint16, direction uint16 ) error
+
+  // Write replaces the whole output latch of an extender.
+  Write( chip int, latch uint16 ) error
+
+  // Update clears and then sets bits of an output latch in one write.
+  Update( chip int, clear uint16, set uint16 ) error
+
+  // Set turns on the latch bits in mask.
+  Set( chip int, mask uint16 ) error
+
+  // Clear turns off the latch bits in mask.
+  Clear( chip int, mask uint16 ) error
+
+  // ReadInputs returns the pin states of an extender.
+  ReadInputs( chip int ) (uint16, error)
+
+  // Snapshot returns the current output latches.
+  Snapshot() Snapshot
+
+  // Close releases the fixture.
+  Close() error
+}
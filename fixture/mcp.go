@@ -0,0 +1,177 @@
+package fixture
+
+import (
+        "fmt"
+        "sync"
+
+        "github.com/luismesas/goPi/MCP23S17"
+        "github.com/luismesas/goPi/spi"
+)
+
+const IOCON_SETTING = 0x7e           // Hardware addressing, sequential operation off
+
+/* MCP23S17 Fixture */
+
+type MCP struct {
+  mu sync.Mutex                      // Serializes access to the SPI bus
+  chips []*MCP23S17.MCP23S17         // Control structures for the port extenders
+  latches Snapshot                   // Last value written to each output latch
+}
+
+/*
+    Procedure Name : OpenMCP
+
+    Description    : Opens the port extenders on the default SPI bus. A
+                     chip that fails to open is reported but left in the
+                     bank, so the remaining chips can still be used.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The fixture
+                     The first error opening a chip
+*/
+
+func OpenMCP() (*MCP, error) {
+  var firstErr error
+
+  m := &MCP{}
+
+  for i := 0;i < Chips;i++ {
+    chip := MCP23S17.NewMCP23S17( (uint8) (i), spi.DEFAULT_BUS, spi.DEFAULT_CHIP )
+
+    if err := chip.Open(); err != nil && firstErr == nil {
+      firstErr = fmt.Errorf("error connecting to chip %d: %v", i, err)
+    }
+
+    m.chips = append(m.chips, chip)
+  }
+
+  return m, firstErr
+}
+
+/*
+    Procedure Name : writePorts
+
+    Description    : Writes the ports of an output latch that differ from
+                     the last value written. The caller must hold the lock.
+
+    Arguments      : chip  - Port extender number
+                     latch - New latch value
+                     force - Write both ports even if they have not changed
+
+    Return Value   : Any error writing to the chip
+*/
+
+func (m *MCP) writePorts( chip int, latch uint16, force bool ) error {
+  if chip < 0 || chip >= Chips {
+    return fmt.Errorf("No port extender %d", chip)
+  }
+
+  old := m.latches[chip]
+
+  if force || (old & 0xff) != (latch & 0xff) {
+    if err := m.chips[chip].Write( (byte) (latch & 0xff), MCP23S17.OLATA ); err != nil {
+      return err
+    }
+  }
+
+  if force || (old >> 8) != (latch >> 8) {
+    if err := m.chips[chip].Write( (byte) (latch >> 8), MCP23S17.OLATB ); err != nil {
+      return err
+    }
+  }
+
+  m.latches[chip] = latch
+
+  return nil
+}
+
+func (m *MCP) Configure( chip int, latch uint16, direction uint16 ) error {
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  if chip < 0 || chip >= Chips {
+    return fmt.Errorf("No port extender %d", chip)
+  }
+
+  c := m.chips[chip]
+
+  c.Write( IOCON_SETTING, MCP23S17.IOCON )
+  c.Write( IOCON_SETTING, MCP23S17.IOCON + 1 )
+
+  if err := m.writePorts( chip, latch, true ); err != nil {
+    return err
+  }
+
+  c.Write( (byte) (direction & 0xff), MCP23S17.IODIRA )
+
+  return c.Write( (byte) (direction >> 8), MCP23S17.IODIRB )
+}
+
+func (m *MCP) Write( chip int, latch uint16 ) error {
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  return m.writePorts( chip, latch, true )
+}
+
+func (m *MCP) Update( chip int, clear uint16, set uint16 ) error {
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  if chip < 0 || chip >= Chips {
+    return fmt.Errorf("No port extender %d", chip)
+  }
+
+  return m.writePorts( chip, (m.latches[chip] &^ clear) | set, false )
+}
+
+func (m *MCP) Set( chip int, mask uint16 ) error {
+  return m.Update( chip, 0, mask )
+}
+
+func (m *MCP) Clear( chip int, mask uint16 ) error {
+  return m.Update( chip, mask, 0 )
+}
+
+func (m *MCP) ReadInputs( chip int ) (uint16, error) {
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  if chip < 0 || chip >= Chips {
+    return 0, fmt.Errorf("No port extender %d", chip)
+  }
+
+  low := m.chips[chip].Read( MCP23S17.GPIOA )
+  high := m.chips[chip].Read( MCP23S17.GPIOB )
+
+  return ((uint16) (high) << 8) | (uint16) (low), nil
+}
+
+func (m *MCP) Snapshot() Snapshot {
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  return m.latches
+}
+
+func (m *MCP) Close() error {
+  var firstErr error
+
+  m.mu.Lock()
+
+  defer m.mu.Unlock()
+
+  for _, chip := range m.chips {
+    if err := chip.Close(); err != nil && firstErr == nil {
+      firstErr = err
+    }
+  }
+
+  return firstErr
+}
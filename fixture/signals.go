@@ -0,0 +1,183 @@
+package fixture
+
+import (
+        "fmt"
+)
+
+//go:generate go run ../cmd/fixturegen -o signals_gen.go ../Production_Tester_Test_Descriptions_A05.xlsx
+
+/* Signal Direction */
+
+type Direction int
+
+const (
+  Output Direction = iota            // Driven by the fixture
+  Input                              // Read by the fixture
+)
+
+/* Signal Polarity */
+
+type Polarity int
+
+const (
+  ActiveHigh Polarity = iota         // Asserted when the bit is 1
+  ActiveLow                          // Asserted when the bit is 0
+)
+
+/* Fixture Signal */
+
+type Signal struct {
+  Name string                        // Bit description from the spreadsheet
+  Chip int                           // Port extender number
+  Bit uint                           // Latch bit, 0-7 port A, 8-15 port B
+  Direction Direction                // Output or input
+  Polarity Polarity                  // Level that asserts the signal
+  Group string                       // Spreadsheet section the signal is listed under
+  Description string                 // Notes from the spreadsheet
+}
+
+/*
+    Procedure Name : Mask
+
+    Description    : Returns the latch bit of a signal.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Bit mask for the signal's port extender
+*/
+
+func (s Signal) Mask() uint16 {
+  return 1 << s.Bit
+}
+
+/*
+    Procedure Name : Port
+
+    Description    : Returns the spreadsheet name of the signal's pin,
+                     such as GPB5.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Pin name
+*/
+
+func (s Signal) Port() string {
+  if s.Bit >= 8 {
+    return fmt.Sprintf("GPB%d", s.Bit - 8)
+  }
+
+  return fmt.Sprintf("GPA%d", s.Bit)
+}
+
+/*
+    Procedure Name : Switch
+
+    Description    : Deasserts one set of signals and asserts another,
+                     writing each port extender once.
+
+    Arguments      : f        - Fixture to drive
+                     deassert - Signals to turn off
+                     assert   - Signals to turn on
+
+    Return Value   : The first error writing to the fixture
+*/
+
+func Switch( f Fixture, deassert []Signal, assert []Signal ) error {
+  var clear [Chips]uint16
+  var set [Chips]uint16
+  var used [Chips]bool
+
+  add := func( signals []Signal, on bool ) error {
+    for _, s := range signals {
+      if s.Chip < 0 || s.Chip >= Chips {
+        return fmt.Errorf("Signal %q is on unknown port extender %d", s.Name, s.Chip)
+      }
+
+      if s.Direction != Output {
+        return fmt.Errorf("Signal %q is not an output", s.Name)
+      }
+
+      used[s.Chip] = true
+
+      if on == (s.Polarity == ActiveHigh) {
+        set[s.Chip] |= s.Mask()
+        clear[s.Chip] &^= s.Mask()
+      } else {
+        clear[s.Chip] |= s.Mask()
+        set[s.Chip] &^= s.Mask()
+      }
+    }
+
+    return nil
+  }
+
+  if err := add( deassert, false ); err != nil {
+    return err
+  }
+
+  if err := add( assert, true ); err != nil {
+    return err
+  }
+
+  for chip := 0;chip < Chips;chip++ {
+    if used[chip] {
+      if err := f.Update( chip, clear[chip], set[chip] ); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : Assert
+
+    Description    : Turns on fixture output signals.
+
+    Arguments      : f       - Fixture to drive
+                     signals - Signals to turn on
+
+    Return Value   : The first error writing to the fixture
+*/
+
+func Assert( f Fixture, signals ...Signal ) error {
+  return Switch( f, nil, signals )
+}
+
+/*
+    Procedure Name : Deassert
+
+    Description    : Turns off fixture output signals.
+
+    Arguments      : f       - Fixture to drive
+                     signals - Signals to turn off
+
+    Return Value   : The first error writing to the fixture
+*/
+
+func Deassert( f Fixture, signals ...Signal ) error {
+  return Switch( f, signals, nil )
+}
+
+/*
+    Procedure Name : Sense
+
+    Description    : Reads a fixture signal.
+
+    Arguments      : f - Fixture to read
+                     s - Signal to read
+
+    Return Value   : true if the signal is asserted
+                     Any error reading the fixture
+*/
+
+func Sense( f Fixture, s Signal ) (bool, error) {
+  inputs, err := f.ReadInputs( s.Chip )
+
+  if err != nil {
+    return false, err
+  }
+
+  return ((inputs & s.Mask()) != 0) == (s.Polarity == ActiveHigh), nil
+}
@@ -0,0 +1,441 @@
+// Code generated by fixturegen from Production_Tester_Test_Descriptions_A05.xlsx. DO NOT EDIT.
+
+package fixture
+
+// Digital Stimulus Power Enable, port extender 0 GPB4
+var SigDigitalStimulusPowerEnable = Signal{Name: "Digital Stimulus Power Enable", Chip: 0, Bit: 12, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "Applies 32V_DIG_HI to dividers"}
+
+// 74V_NOGO_1, port extender 1 GPB7
+var Sig74VNOGO1 = Signal{Name: "74V_NOGO_1", Chip: 1, Bit: 15, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_2, port extender 1 GPB6
+var Sig74VNOGO2 = Signal{Name: "74V_NOGO_2", Chip: 1, Bit: 14, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_3, port extender 1 GPB5
+var Sig74VNOGO3 = Signal{Name: "74V_NOGO_3", Chip: 1, Bit: 13, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_4, port extender 1 GPB4
+var Sig74VNOGO4 = Signal{Name: "74V_NOGO_4", Chip: 1, Bit: 12, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_5, port extender 1 GPB3
+var Sig74VNOGO5 = Signal{Name: "74V_NOGO_5", Chip: 1, Bit: 11, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_6, port extender 1 GPB2
+var Sig74VNOGO6 = Signal{Name: "74V_NOGO_6", Chip: 1, Bit: 10, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_7, port extender 1 GPB1
+var Sig74VNOGO7 = Signal{Name: "74V_NOGO_7", Chip: 1, Bit: 9, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_8, port extender 1 GPB0
+var Sig74VNOGO8 = Signal{Name: "74V_NOGO_8", Chip: 1, Bit: 8, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_9, port extender 1 GPA7
+var Sig74VNOGO9 = Signal{Name: "74V_NOGO_9", Chip: 1, Bit: 7, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_10, port extender 1 GPA6
+var Sig74VNOGO10 = Signal{Name: "74V_NOGO_10", Chip: 1, Bit: 6, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_11, port extender 1 GPA5
+var Sig74VNOGO11 = Signal{Name: "74V_NOGO_11", Chip: 1, Bit: 5, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_12, port extender 1 GPA4
+var Sig74VNOGO12 = Signal{Name: "74V_NOGO_12", Chip: 1, Bit: 4, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_13, port extender 1 GPA3
+var Sig74VNOGO13 = Signal{Name: "74V_NOGO_13", Chip: 1, Bit: 3, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_14, port extender 1 GPA2
+var Sig74VNOGO14 = Signal{Name: "74V_NOGO_14", Chip: 1, Bit: 2, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_15, port extender 1 GPA1
+var Sig74VNOGO15 = Signal{Name: "74V_NOGO_15", Chip: 1, Bit: 1, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_16, port extender 1 GPA0
+var Sig74VNOGO16 = Signal{Name: "74V_NOGO_16", Chip: 1, Bit: 0, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_17, port extender 2 GPB7
+var Sig74VNOGO17 = Signal{Name: "74V_NOGO_17", Chip: 2, Bit: 15, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_18, port extender 2 GPB6
+var Sig74VNOGO18 = Signal{Name: "74V_NOGO_18", Chip: 2, Bit: 14, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_19, port extender 2 GPB5
+var Sig74VNOGO19 = Signal{Name: "74V_NOGO_19", Chip: 2, Bit: 13, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_20, port extender 2 GPB4
+var Sig74VNOGO20 = Signal{Name: "74V_NOGO_20", Chip: 2, Bit: 12, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_21, port extender 2 GPB3
+var Sig74VNOGO21 = Signal{Name: "74V_NOGO_21", Chip: 2, Bit: 11, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_22, port extender 2 GPB2
+var Sig74VNOGO22 = Signal{Name: "74V_NOGO_22", Chip: 2, Bit: 10, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_23, port extender 2 GPB1
+var Sig74VNOGO23 = Signal{Name: "74V_NOGO_23", Chip: 2, Bit: 9, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_24, port extender 2 GPB0
+var Sig74VNOGO24 = Signal{Name: "74V_NOGO_24", Chip: 2, Bit: 8, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_25, port extender 2 GPA7
+var Sig74VNOGO25 = Signal{Name: "74V_NOGO_25", Chip: 2, Bit: 7, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_26, port extender 2 GPA6
+var Sig74VNOGO26 = Signal{Name: "74V_NOGO_26", Chip: 2, Bit: 6, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_27, port extender 2 GPA5
+var Sig74VNOGO27 = Signal{Name: "74V_NOGO_27", Chip: 2, Bit: 5, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_28, port extender 2 GPA4
+var Sig74VNOGO28 = Signal{Name: "74V_NOGO_28", Chip: 2, Bit: 4, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_29, port extender 2 GPA3
+var Sig74VNOGO29 = Signal{Name: "74V_NOGO_29", Chip: 2, Bit: 3, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_30, port extender 2 GPA2
+var Sig74VNOGO30 = Signal{Name: "74V_NOGO_30", Chip: 2, Bit: 2, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_31, port extender 2 GPA1
+var Sig74VNOGO31 = Signal{Name: "74V_NOGO_31", Chip: 2, Bit: 1, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_32, port extender 2 GPA0
+var Sig74VNOGO32 = Signal{Name: "74V_NOGO_32", Chip: 2, Bit: 0, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_33, port extender 3 GPB7
+var Sig74VNOGO33 = Signal{Name: "74V_NOGO_33", Chip: 3, Bit: 15, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// 74V_NOGO_34, port extender 3 GPB6
+var Sig74VNOGO34 = Signal{Name: "74V_NOGO_34", Chip: 3, Bit: 14, Direction: Output, Polarity: ActiveHigh,
+	Group:       "74 Volt Input Testing (x34)",
+	Description: "\"1\" for NOGO (24V), \"0\" for GO (29V)"}
+
+// DIC_NOGO_1, port extender 0 GPB3
+var SigDICNOGO1 = Signal{Name: "DIC_NOGO_1", Chip: 0, Bit: 11, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIC_NOGO_2, port extender 0 GPB2
+var SigDICNOGO2 = Signal{Name: "DIC_NOGO_2", Chip: 0, Bit: 10, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIC_NOGO_3, port extender 0 GPB1
+var SigDICNOGO3 = Signal{Name: "DIC_NOGO_3", Chip: 0, Bit: 9, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIC_NOGO_4, port extender 0 GPB0
+var SigDICNOGO4 = Signal{Name: "DIC_NOGO_4", Chip: 0, Bit: 8, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIC_NOGO_5, port extender 0 GPA7
+var SigDICNOGO5 = Signal{Name: "DIC_NOGO_5", Chip: 0, Bit: 7, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIC_NOGO_6, port extender 0 GPA6
+var SigDICNOGO6 = Signal{Name: "DIC_NOGO_6", Chip: 0, Bit: 6, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIB_NOGO_1, port extender 0 GPA5
+var SigDIBNOGO1 = Signal{Name: "DIB_NOGO_1", Chip: 0, Bit: 5, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIB_NOGO_2, port extender 0 GPA4
+var SigDIBNOGO2 = Signal{Name: "DIB_NOGO_2", Chip: 0, Bit: 4, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIB_NOGO_3, port extender 0 GPA3
+var SigDIBNOGO3 = Signal{Name: "DIB_NOGO_3", Chip: 0, Bit: 3, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// DIB_NOGO_4, port extender 0 GPA2
+var SigDIBNOGO4 = Signal{Name: "DIB_NOGO_4", Chip: 0, Bit: 2, Direction: Output, Polarity: ActiveHigh,
+	Group:       "32 Volt Input Testing (x10)",
+	Description: "\"1\" for NOGO (3.8V), \"0\" for GO (9V)"}
+
+// Voltage Select for 80V Analog Outputs, port extender 3 GPB5
+var SigVoltageSelectFor80VAnalogOutputs = Signal{Name: "Voltage Select for 80V Analog Outputs", Chip: 3, Bit: 13, Direction: Output, Polarity: ActiveHigh,
+	Group:       "80V Analog Input Testing",
+	Description: "\"0\" sets level to 10V, \"1\" sets level to 50.5V.  Applying this voltage to IEM inputs is accomplished via 'throwing' the following DPDT relays"}
+
+// Enable 80V Analog Outs 1 and 4, port extender 3 GPB4
+var SigEnable80VAnalogOuts1And4 = Signal{Name: "Enable 80V Analog Outs 1 and 4", Chip: 3, Bit: 12, Direction: Output, Polarity: ActiveHigh,
+	Group:       "80V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (10V/50.5V),"}
+
+// Enable 80V Analog Outs 2 and 3, port extender 3 GPB3
+var SigEnable80VAnalogOuts2And3 = Signal{Name: "Enable 80V Analog Outs 2 and 3", Chip: 3, Bit: 11, Direction: Output, Polarity: ActiveHigh,
+	Group:       "80V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (10V/50.5V),"}
+
+// Enable 80V Analog Outs 6 and 7, port extender 3 GPB2
+var SigEnable80VAnalogOuts6And7 = Signal{Name: "Enable 80V Analog Outs 6 and 7", Chip: 3, Bit: 10, Direction: Output, Polarity: ActiveHigh,
+	Group:       "80V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (10V/50.5V),"}
+
+// Enable 80V Analog Out 5, port extender 3 GPB1
+var SigEnable80VAnalogOut5 = Signal{Name: "Enable 80V Analog Out 5", Chip: 3, Bit: 9, Direction: Output, Polarity: ActiveHigh,
+	Group:       "80V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (10V/50.5V),"}
+
+// Voltage Select for ±10V Analog Outputs, port extender 3 GPB0
+var SigVoltageSelectForPM10VAnalogOutputs = Signal{Name: "Voltage Select for ±10V Analog Outputs", Chip: 3, Bit: 8, Direction: Output, Polarity: ActiveHigh,
+	Group:       "±10V Analog Input Testing",
+	Description: "\"0\" sets level to +7.5V, \"1\" sets level to -7.5V.  Applying this voltage to IEM inputs is accomplished via 'throwing' the following DPDT relays"}
+
+// Enable ±10V Analog Outs 1 and 2, port extender 3 GPA7
+var SigEnablePM10VAnalogOuts1And2 = Signal{Name: "Enable ±10V Analog Outs 1 and 2", Chip: 3, Bit: 7, Direction: Output, Polarity: ActiveHigh,
+	Group:       "±10V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (+7V/-7V),"}
+
+// Enable ±10V Analog Outs 3 and 4, port extender 3 GPA6
+var SigEnablePM10VAnalogOuts3And4 = Signal{Name: "Enable ±10V Analog Outs 3 and 4", Chip: 3, Bit: 6, Direction: Output, Polarity: ActiveHigh,
+	Group:       "±10V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (+7V/-7V),"}
+
+// Enable ±10V Analog Outs 5 and 6, port extender 3 GPA5
+var SigEnablePM10VAnalogOuts5And6 = Signal{Name: "Enable ±10V Analog Outs 5 and 6", Chip: 3, Bit: 5, Direction: Output, Polarity: ActiveHigh,
+	Group:       "±10V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (+7V/-7V),"}
+
+// Voltage Select for 16V Analog Output, port extender 3 GPA4
+var SigVoltageSelectFor16VAnalogOutput = Signal{Name: "Voltage Select for 16V Analog Output", Chip: 3, Bit: 4, Direction: Output, Polarity: ActiveHigh,
+	Group:       "16V Analog Input Testing",
+	Description: "\"0\" sets level to 2V, \"1\" sets level to 14.36V.  Applying this voltage to IEM inputs is accomplished via 'throwing' the following DPDT relays"}
+
+// Enable 16V Analog Output, port extender 3 GPA3
+var SigEnable16VAnalogOutput = Signal{Name: "Enable 16V Analog Output", Chip: 3, Bit: 3, Direction: Output, Polarity: ActiveHigh,
+	Group:       "16V Analog Input Testing",
+	Description: "\"0\" to pull down to COM, \"1\" to apply analog out (+7V/-7V),"}
+
+// Pressure Sensor Output Drivers Enable, port extender 0 GPB6
+var SigPressureSensorOutputDriversEnable = Signal{Name: "Pressure Sensor Output Drivers Enable", Chip: 0, Bit: 14, Direction: Output, Polarity: ActiveHigh,
+	Group:       "Pressure Sensor Input Testing (Performed at Board-Level Only)",
+	Description: "\"0\"  disables power rail to drivers,\"1\" enables power rail to drivers.  Setting the level driven out to the IEM's inputs (2V/4V) is accomplished via the following output bit."}
+
+// Voltage Select for Pressure Sensor Outputs, port extender 0 GPB7
+var SigVoltageSelectForPressureSensorOutputs = Signal{Name: "Voltage Select for Pressure Sensor Outputs", Chip: 0, Bit: 15, Direction: Output, Polarity: ActiveHigh,
+	Group:       "Pressure Sensor Input Testing (Performed at Board-Level Only)",
+	Description: "\"0\" sets level to 4V, \"1\" sets level to 2V."}
+
+// Voltage Select for Current Sensor Outputs, port extender 0 GPB6
+var SigVoltageSelectForCurrentSensorOutputs = Signal{Name: "Voltage Select for Current Sensor Outputs", Chip: 0, Bit: 14, Direction: Output, Polarity: ActiveHigh,
+	Group:       "Current Sensor Input Testing",
+	Description: "\"0\" sets level to 4V, \"1\" sets level to 2V."}
+
+// Current Sensor Load Enable, port extender 0 GPB5
+var SigCurrentSensorLoadEnable = Signal{Name: "Current Sensor Load Enable", Chip: 0, Bit: 13, Direction: Output, Polarity: ActiveHigh,
+	Group:       "Current Sensor Input Testing",
+	Description: "\"0\" disables the load, \"1\" enables the load."}
+
+// Speed Sensor 2V Offset Enable, port extender 3 GPA2
+var SigSpeedSensor2VOffsetEnable = Signal{Name: "Speed Sensor 2V Offset Enable", Chip: 3, Bit: 2, Direction: Output, Polarity: ActiveHigh,
+	Group:       "Speed Sensor Input Testing",
+	Description: "\"0\" sets speed pulse crossing to 0V, \"1\" sets speed pulse crossing to 2V."}
+
+// Detection of Mag Valve Driver, port extender 4 GPA0
+var SigDetectionOfMagValveDriver = Signal{Name: "Detection of Mag Valve Driver", Chip: 4, Bit: 0, Direction: Input, Polarity: ActiveHigh,
+	Group:       "Mag Valve Output Testing",
+	Description: "\"0\" Mag Valve Driver is Disabled, \"1\" Mag Valve Driver is Enabled"}
+
+// DUT Power Enable, port extender 3 GPA1
+var SigDUTPowerEnable = Signal{Name: "DUT Power Enable", Chip: 3, Bit: 1, Direction: Output, Polarity: ActiveHigh,
+	Group:       "DUT Power Enable",
+	Description: "Drive this line high to swtich 54VDC to power the IEM"}
+
+// 74V_NOGO_1 to 74V_NOGO_34 in order
+var Sig74VNOGO = []Signal{
+	Sig74VNOGO1,
+	Sig74VNOGO2,
+	Sig74VNOGO3,
+	Sig74VNOGO4,
+	Sig74VNOGO5,
+	Sig74VNOGO6,
+	Sig74VNOGO7,
+	Sig74VNOGO8,
+	Sig74VNOGO9,
+	Sig74VNOGO10,
+	Sig74VNOGO11,
+	Sig74VNOGO12,
+	Sig74VNOGO13,
+	Sig74VNOGO14,
+	Sig74VNOGO15,
+	Sig74VNOGO16,
+	Sig74VNOGO17,
+	Sig74VNOGO18,
+	Sig74VNOGO19,
+	Sig74VNOGO20,
+	Sig74VNOGO21,
+	Sig74VNOGO22,
+	Sig74VNOGO23,
+	Sig74VNOGO24,
+	Sig74VNOGO25,
+	Sig74VNOGO26,
+	Sig74VNOGO27,
+	Sig74VNOGO28,
+	Sig74VNOGO29,
+	Sig74VNOGO30,
+	Sig74VNOGO31,
+	Sig74VNOGO32,
+	Sig74VNOGO33,
+	Sig74VNOGO34,
+}
+
+// DIB_NOGO_1 to DIB_NOGO_4 in order
+var SigDIBNOGO = []Signal{
+	SigDIBNOGO1,
+	SigDIBNOGO2,
+	SigDIBNOGO3,
+	SigDIBNOGO4,
+}
+
+// DIC_NOGO_1 to DIC_NOGO_6 in order
+var SigDICNOGO = []Signal{
+	SigDICNOGO1,
+	SigDICNOGO2,
+	SigDICNOGO3,
+	SigDICNOGO4,
+	SigDICNOGO5,
+	SigDICNOGO6,
+}
+
+// Every signal keyed by its spreadsheet description
+var Signals = map[string]Signal{
+	"Digital Stimulus Power Enable":         SigDigitalStimulusPowerEnable,
+	"74V_NOGO_1":                            Sig74VNOGO1,
+	"74V_NOGO_2":                            Sig74VNOGO2,
+	"74V_NOGO_3":                            Sig74VNOGO3,
+	"74V_NOGO_4":                            Sig74VNOGO4,
+	"74V_NOGO_5":                            Sig74VNOGO5,
+	"74V_NOGO_6":                            Sig74VNOGO6,
+	"74V_NOGO_7":                            Sig74VNOGO7,
+	"74V_NOGO_8":                            Sig74VNOGO8,
+	"74V_NOGO_9":                            Sig74VNOGO9,
+	"74V_NOGO_10":                           Sig74VNOGO10,
+	"74V_NOGO_11":                           Sig74VNOGO11,
+	"74V_NOGO_12":                           Sig74VNOGO12,
+	"74V_NOGO_13":                           Sig74VNOGO13,
+	"74V_NOGO_14":                           Sig74VNOGO14,
+	"74V_NOGO_15":                           Sig74VNOGO15,
+	"74V_NOGO_16":                           Sig74VNOGO16,
+	"74V_NOGO_17":                           Sig74VNOGO17,
+	"74V_NOGO_18":                           Sig74VNOGO18,
+	"74V_NOGO_19":                           Sig74VNOGO19,
+	"74V_NOGO_20":                           Sig74VNOGO20,
+	"74V_NOGO_21":                           Sig74VNOGO21,
+	"74V_NOGO_22":                           Sig74VNOGO22,
+	"74V_NOGO_23":                           Sig74VNOGO23,
+	"74V_NOGO_24":                           Sig74VNOGO24,
+	"74V_NOGO_25":                           Sig74VNOGO25,
+	"74V_NOGO_26":                           Sig74VNOGO26,
+	"74V_NOGO_27":                           Sig74VNOGO27,
+	"74V_NOGO_28":                           Sig74VNOGO28,
+	"74V_NOGO_29":                           Sig74VNOGO29,
+	"74V_NOGO_30":                           Sig74VNOGO30,
+	"74V_NOGO_31":                           Sig74VNOGO31,
+	"74V_NOGO_32":                           Sig74VNOGO32,
+	"74V_NOGO_33":                           Sig74VNOGO33,
+	"74V_NOGO_34":                           Sig74VNOGO34,
+	"DIC_NOGO_1":                            SigDICNOGO1,
+	"DIC_NOGO_2":                            SigDICNOGO2,
+	"DIC_NOGO_3":                            SigDICNOGO3,
+	"DIC_NOGO_4":                            SigDICNOGO4,
+	"DIC_NOGO_5":                            SigDICNOGO5,
+	"DIC_NOGO_6":                            SigDICNOGO6,
+	"DIB_NOGO_1":                            SigDIBNOGO1,
+	"DIB_NOGO_2":                            SigDIBNOGO2,
+	"DIB_NOGO_3":                            SigDIBNOGO3,
+	"DIB_NOGO_4":                            SigDIBNOGO4,
+	"Voltage Select for 80V Analog Outputs": SigVoltageSelectFor80VAnalogOutputs,
+	"Enable 80V Analog Outs 1 and 4":        SigEnable80VAnalogOuts1And4,
+	"Enable 80V Analog Outs 2 and 3":        SigEnable80VAnalogOuts2And3,
+	"Enable 80V Analog Outs 6 and 7":        SigEnable80VAnalogOuts6And7,
+	"Enable 80V Analog Out 5":               SigEnable80VAnalogOut5,
+	"Voltage Select for ±10V Analog Outputs":     SigVoltageSelectForPM10VAnalogOutputs,
+	"Enable ±10V Analog Outs 1 and 2":            SigEnablePM10VAnalogOuts1And2,
+	"Enable ±10V Analog Outs 3 and 4":            SigEnablePM10VAnalogOuts3And4,
+	"Enable ±10V Analog Outs 5 and 6":            SigEnablePM10VAnalogOuts5And6,
+	"Voltage Select for 16V Analog Output":       SigVoltageSelectFor16VAnalogOutput,
+	"Enable 16V Analog Output":                   SigEnable16VAnalogOutput,
+	"Pressure Sensor Output Drivers Enable":      SigPressureSensorOutputDriversEnable,
+	"Voltage Select for Pressure Sensor Outputs": SigVoltageSelectForPressureSensorOutputs,
+	"Voltage Select for Current Sensor Outputs":  SigVoltageSelectForCurrentSensorOutputs,
+	"Current Sensor Load Enable":                 SigCurrentSensorLoadEnable,
+	"Speed Sensor 2V Offset Enable":              SigSpeedSensor2VOffsetEnable,
+	"Detection of Mag Valve Driver":              SigDetectionOfMagValveDriver,
+	"DUT Power Enable":                           SigDUTPowerEnable,
+}
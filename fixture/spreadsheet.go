@@ -0,0 +1,384 @@
+package fixture
+
+import (
+        "archive/zip"
+        "encoding/xml"
+        "fmt"
+        "io"
+        "path"
+        "regexp"
+        "strconv"
+        "strings"
+)
+
+const BIT_MAP_SHEET = "Address and Bit Mapping" // Sheet of the test description spreadsheet holding the bit map
+
+const MCP23S17_ADDRESS_BASE = 0x20    // Fixed upper bits of a port extender address (0b0100xxx)
+
+/* Spreadsheet XML Structures */
+
+type xlsxWorkbook struct {
+  Sheets []struct {
+    Name string `xml:"name,attr"`
+    RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+  } `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+  Relationships []struct {
+    ID string `xml:"Id,attr"`
+    Target string `xml:"Target,attr"`
+  } `xml:"Relationship"`
+}
+
+type xlsxText struct {
+  T string `xml:"t"`
+  R []struct {
+    T string `xml:"t"`
+  } `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+  s := t.T
+
+  for _, r := range t.R {
+    s += r.T
+  }
+
+  return s
+}
+
+type xlsxSharedStrings struct {
+  Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+  Rows []struct {
+    Number int `xml:"r,attr"`
+    Cells []struct {
+      Ref string `xml:"r,attr"`
+      Type string `xml:"t,attr"`
+      V string `xml:"v"`
+      Is xlsxText `xml:"is"`
+    } `xml:"c"`
+  } `xml:"sheetData>row"`
+}
+
+// Spreadsheet column headings
+
+const (
+  colName = "Bit Description"
+  colAddress = "GPIO Address"
+  colBit = "GPIO Expander Bit / Name"
+  colDirection = "Bit Direction"
+  colNotes = "Other Notes"
+  colPolarity = "Polarity"
+)
+
+/* Worksheet Row */
+
+type sheetRow struct {
+  Number int                         // Row number shown in the spreadsheet
+  Cells map[string]string            // Cell text keyed by column letter
+}
+
+var bitPattern = regexp.MustCompile(`^\s*(\d+)\s*(?:/\s*GP([AB])(\d))?`)
+var addressPattern = regexp.MustCompile(`^\s*0b([01]+)`)
+
+/*
+    Procedure Name : readZipXML
+
+    Description    : Decodes an XML member of the spreadsheet archive.
+
+    Arguments      : z    - Open spreadsheet archive
+                     name - Member name
+                     v    - Structure to decode into
+
+    Return Value   : Any error finding or decoding the member
+*/
+
+func readZipXML( z *zip.Reader, name string, v interface{} ) error {
+  for _, f := range z.File {
+    if f.Name == name {
+      r, err := f.Open()
+
+      if err != nil {
+        return err
+      }
+
+      defer r.Close()
+
+      return xml.NewDecoder( r ).Decode( v )
+    }
+  }
+
+  return fmt.Errorf("%s not found in spreadsheet", name)
+}
+
+/*
+    Procedure Name : readSheet
+
+    Description    : Reads the rows of a worksheet by name.
+
+    Arguments      : z     - Open spreadsheet archive
+                     sheet - Worksheet name
+
+    Return Value   : Rows of the worksheet
+                     Any error reading the worksheet
+*/
+
+func readSheet( z *zip.Reader, sheet string ) ([]sheetRow, error) {
+  var workbook xlsxWorkbook
+  var rels xlsxRelationships
+  var shared xlsxSharedStrings
+  var data xlsxSheet
+
+  if err := readZipXML( z, "xl/workbook.xml", &workbook ); err != nil {
+    return nil, err
+  }
+
+  if err := readZipXML( z, "xl/_rels/workbook.xml.rels", &rels ); err != nil {
+    return nil, err
+  }
+
+  // Shared strings are missing from a workbook without any text.
+
+  readZipXML( z, "xl/sharedStrings.xml", &shared )
+
+  target := ""
+
+  for _, s := range workbook.Sheets {
+    if s.Name == sheet {
+      for _, r := range rels.Relationships {
+        if r.ID == s.RID {
+          target = r.Target
+        }
+      }
+    }
+  }
+
+  if target == "" {
+    return nil, fmt.Errorf("No %q sheet in spreadsheet", sheet)
+  }
+
+  if strings.HasPrefix( target, "/" ) {
+    target = strings.TrimPrefix( target, "/" )
+  } else {
+    target = path.Join( "xl", target )
+  }
+
+  if err := readZipXML( z, target, &data ); err != nil {
+    return nil, err
+  }
+
+  rows := make([]sheetRow, 0, len(data.Rows))
+
+  for _, r := range data.Rows {
+    row := sheetRow{ Number: r.Number, Cells: make(map[string]string) }
+
+    for _, c := range r.Cells {
+      column := strings.TrimRight( c.Ref, "0123456789" )
+      text := c.V
+
+      switch c.Type {
+        case "s" :
+          i, err := strconv.Atoi( c.V )
+
+          if err != nil || i < 0 || i >= len(shared.Items) {
+            return nil, fmt.Errorf("Bad shared string in cell %s", c.Ref)
+          }
+
+          text = shared.Items[i].String()
+
+        case "inlineStr" :
+          text = c.Is.String()
+      }
+
+      row.Cells[column] = strings.TrimSpace( text )
+    }
+
+    rows = append(rows, row)
+  }
+
+  return rows, nil
+}
+
+/*
+    Procedure Name : ReadSpreadsheet
+
+    Description    : Reads the fixture signals from the bit map sheet of
+                     the test description spreadsheet. Rows with only a
+                     description start a new group of signals. The bit
+                     number in the spreadsheet is used for the signal; a
+                     pin name that does not agree with it is reported as
+                     a warning. A signal listed more than once at the same
+                     place is kept once.
+
+    Arguments      : r    - Spreadsheet contents
+                     size - Size of the spreadsheet in bytes
+
+    Return Value   : The signals in spreadsheet order
+                     Warnings about inconsistent rows
+                     Any error reading the spreadsheet
+*/
+
+func ReadSpreadsheet( r io.ReaderAt, size int64 ) ([]Signal, []string, error) {
+  z, err := zip.NewReader( r, size )
+
+  if err != nil {
+    return nil, nil, err
+  }
+
+  return readBitMap( z )
+}
+
+/*
+    Procedure Name : readBitMap
+
+    Description    : Reads the fixture signals from the bit map sheet of
+                     an open spreadsheet archive.
+
+    Arguments      : z - Open spreadsheet archive
+
+    Return Value   : The signals in spreadsheet order
+                     Warnings about inconsistent rows
+                     Any error reading the spreadsheet
+*/
+
+func readBitMap( z *zip.Reader ) ([]Signal, []string, error) {
+  var signals []Signal
+  var warnings []string
+
+  rows, err := readSheet( z, BIT_MAP_SHEET )
+
+  if err != nil {
+    return nil, nil, err
+  }
+
+  if len(rows) == 0 {
+    return nil, nil, fmt.Errorf("%q sheet is empty", BIT_MAP_SHEET)
+  }
+
+  /*
+      Find the columns from the heading row.
+  */
+
+  columns := make(map[string]string)
+
+  for column, heading := range rows[0].Cells {
+    columns[heading] = column
+  }
+
+  for _, heading := range []string{ colName, colAddress, colBit, colDirection } {
+    if _, ok := columns[heading]; !ok {
+      return nil, nil, fmt.Errorf("%q sheet has no %q column", BIT_MAP_SHEET, heading)
+    }
+  }
+
+  seen := make(map[string]Signal)
+  group := ""
+
+  for _, r := range rows[1:] {
+    row := r.Cells
+    line := r.Number
+    name := row[columns[colName]]
+
+    if name == "" {
+      continue
+    }
+
+    if row[columns[colAddress]] == "" {
+      group = name
+
+      continue
+    }
+
+    s := Signal{ Name: name, Group: group, Description: row[columns[colNotes]] }
+
+    m := addressPattern.FindStringSubmatch( row[columns[colAddress]] )
+
+    if m == nil {
+      return nil, nil, fmt.Errorf("Row %d: bad GPIO address %q", line, row[columns[colAddress]])
+    }
+
+    address, _ := strconv.ParseUint( m[1], 2, 8 )
+
+    if (address &^ 0x07) != MCP23S17_ADDRESS_BASE {
+      return nil, nil, fmt.Errorf("Row %d: %q is not a port extender address", line, row[columns[colAddress]])
+    }
+
+    s.Chip = (int) (address & 0x07)
+
+    m = bitPattern.FindStringSubmatch( row[columns[colBit]] )
+
+    if m == nil {
+      return nil, nil, fmt.Errorf("Row %d: bad expander bit %q", line, row[columns[colBit]])
+    }
+
+    bit, _ := strconv.Atoi( m[1] )
+
+    if bit > 15 {
+      return nil, nil, fmt.Errorf("Row %d: expander bit %d out of range", line, bit)
+    }
+
+    s.Bit = (uint) (bit)
+
+    if m[2] != "" && "GP" + m[2] + m[3] != s.Port() {
+      warnings = append(warnings, fmt.Sprintf("Row %d: %s is bit %d (%s) but the sheet names pin GP%s%s",
+                                              line, name, bit, s.Port(), m[2], m[3]))
+    }
+
+    switch strings.ToLower( row[columns[colDirection]] ) {
+      case "output" :
+        s.Direction = Output
+
+      case "input" :
+        s.Direction = Input
+
+      default :
+        return nil, nil, fmt.Errorf("Row %d: bad bit direction %q", line, row[columns[colDirection]])
+    }
+
+    if column, ok := columns[colPolarity]; ok && strings.Contains( strings.ToLower( row[column] ), "low" ) {
+      s.Polarity = ActiveLow
+    }
+
+    if previous, ok := seen[name]; ok {
+      if previous.Chip != s.Chip || previous.Bit != s.Bit {
+        return nil, nil, fmt.Errorf("Row %d: %s is listed at two different bits", line, name)
+      }
+
+      continue
+    }
+
+    seen[name] = s
+    signals = append(signals, s)
+  }
+
+  return signals, warnings, nil
+}
+
+/*
+    Procedure Name : LoadSpreadsheet
+
+    Description    : Reads the fixture signals from a test description
+                     spreadsheet file.
+
+    Arguments      : file - Spreadsheet file name
+
+    Return Value   : The signals in spreadsheet order
+                     Warnings about inconsistent rows
+                     Any error reading the spreadsheet
+*/
+
+func LoadSpreadsheet( file string ) ([]Signal, []string, error) {
+  z, err := zip.OpenReader( file )
+
+  if err != nil {
+    return nil, nil, err
+  }
+
+  defer z.Close()
+
+  return readBitMap( &z.Reader )
+}
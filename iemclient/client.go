@@ -0,0 +1,669 @@
+/*
+   Package iemclient sends requests to the IEM and waits for its
+   responses. Every query has a deadline, taken from its context and from
+   the timeout of its selector, and a query whose response is lost or
+   corrupted is sent again according to the retry policy, so one dropped
+   byte does not fail a whole test.
+
+   Failures are reported with the sentinel errors below, wrapped in a
+   RequestError that says which request failed, so callers can test
+   them with errors.Is.
+*/
+
+package iemclient
+
+import (
+        "context"
+        "errors"
+        "fmt"
+        "io"
+        "sync"
+        "sync/atomic"
+        "time"
+
+        "github.com/questrail/IEMTestDB/iemproto"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+const DEFAULT_TIMEOUT = 2*time.Second          // Time allowed for a response
+const DEFAULT_RETRIES = 2                      // Times a failed request is sent again
+const DEFAULT_BACKOFF = 50*time.Millisecond    // Pause before a request is sent again
+const CONFIRM_INTERVAL = 100*time.Millisecond  // Time between queries confirming a command
+const FRAME_BUFFER = 16                        // Frames held for the client
+
+// Errors
+
+var ErrNoResponse = errors.New("iemclient: no response")
+var ErrShortFrame = iemproto.ErrShortFrame
+var ErrCRCMismatch = iemproto.ErrCRCMismatch
+var ErrUnexpectedEcho = errors.New("iemclient: unexpected selector echo")
+var ErrNotAccepted = errors.New("iemclient: command not accepted")
+var ErrUnconfirmed = errors.New("iemclient: command sent but cannot be confirmed")
+var ErrUnknownSelector = errors.New("iemclient: unknown selector")
+var ErrUnknownSubselector = errors.New("iemclient: unknown subselector")
+
+/* Failed request */
+
+type RequestError struct {
+  Selector byte                      // Selector of the request
+  Subselector byte                   // Subselector of the request
+  Attempts int                       // Times the request was sent
+  Err error                          // Error from the last attempt
+}
+
+func (e *RequestError) Error() string {
+  return fmt.Sprintf("IEM request %02X/%02X failed after %d attempts: %s", e.Selector, e.Subselector, e.Attempts, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+  return e.Err
+}
+
+/*
+   Confirms that the IEM took a set-state command, usually by querying
+   the state it sets. It returns an error wrapping ErrNotAccepted while
+   the new state is not seen. A command whose state cannot be read back
+   has no confirmation.
+*/
+
+type Confirm func( ctx context.Context ) error
+
+/* Retry policy */
+
+type Policy struct {
+  Timeout time.Duration              // Time allowed for each response
+  Retries int                        // Times a failed request is sent again
+  Backoff time.Duration              // Pause before a request is sent again
+}
+
+/* Link statistics */
+
+type Stats struct {
+  Requests int                       // Requests sent, not counting retries
+  Retries int                        // Requests sent again
+  NoResponse int                     // Responses that never came
+  ShortFrames int                    // Responses cut short
+  CRCMismatches int                  // Responses that failed the CRC check
+  UnexpectedEchoes int               // Responses to some other request
+  Unsolicited int                    // Frames that answered nothing asked for
+  NoiseBytes int                     // Bytes thrown away outside of frames
+}
+
+/* Client */
+
+type Client struct {
+  port transport.Transport           // Link to the IEM
+  mu sync.Mutex                      // Allows one exchange at a time and protects stats
+  stats Stats                        // Link statistics
+  frames chan iemproto.Received      // Frames from the stream receiver
+  unsolicited chan iemproto.Received // Frames nobody asked for
+  partial atomic.Int64               // Bytes of a frame still being received
+  dropped atomic.Int64               // Frames the receiver dropped as nobody was waiting
+  linkErr atomic.Pointer[error]      // Error that ended the link
+
+  Default Policy                     // Policy of selectors not in Policies
+  Policies map[byte]Policy           // Policies by selector
+}
+
+/*
+    Procedure Name : New
+
+    Description    : Creates a client on a link to the IEM with the
+                     default policy for every selector, and starts the
+                     stream receiver that reads the link from now on.
+                     Nothing else may read the link after this.
+
+    Arguments      : port - Link to the IEM
+
+    Return Value   : The client
+*/
+
+func New( port transport.Transport ) *Client {
+  c := &Client{
+    port: port,
+    frames: make(chan iemproto.Received, FRAME_BUFFER),
+    unsolicited: make(chan iemproto.Received, FRAME_BUFFER),
+    Default: Policy{ Timeout: DEFAULT_TIMEOUT, Retries: DEFAULT_RETRIES, Backoff: DEFAULT_BACKOFF },
+    Policies: map[byte]Policy{},
+  }
+
+  go c.receive()
+
+  return c
+}
+
+/*
+    Procedure Name : Policy
+
+    Description    : Returns the policy for a selector.
+
+    Arguments      : selector - Information selector or command
+
+    Return Value   : The policy
+*/
+
+func (c *Client) Policy( selector byte ) Policy {
+  if p, ok := c.Policies[selector]; ok {
+    return p
+  }
+
+  return c.Default
+}
+
+/*
+    Procedure Name : Stats
+
+    Description    : Returns the link statistics since the client was
+                     created. Frames the receiver dropped are counted as
+                     unsolicited.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The statistics
+*/
+
+func (c *Client) Stats() Stats {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  s := c.stats
+
+  s.Unsolicited += (int) (c.dropped.Load())
+
+  return s
+}
+
+/*
+    Procedure Name : Retryable
+
+    Description    : Tells whether a failed exchange is worth sending
+                     again: the response was lost, cut short, corrupted
+                     or meant for another request.
+
+    Arguments      : err - Error from an exchange
+
+    Return Value   : true if the request should be sent again
+*/
+
+func Retryable( err error ) bool {
+  return errors.Is( err, ErrNoResponse ) ||
+         errors.Is( err, ErrShortFrame ) ||
+         errors.Is( err, ErrCRCMismatch ) ||
+         errors.Is( err, ErrUnexpectedEcho ) ||
+         errors.Is( err, iemproto.ErrBadStartByte ) ||
+         errors.Is( err, iemproto.ErrMissingEndByte ) ||
+         errors.Is( err, iemproto.ErrBadStuffing )
+}
+
+/*
+    Procedure Name : Send
+
+    Description    : Sends a frame that the IEM does not answer, such as a
+                     set-state command.
+
+    Arguments      : ctx - Context of the request
+                     f   - Frame to send
+
+    Return Value   : Number of bytes sent
+                     Any error sending them
+*/
+
+func (c *Client) Send( ctx context.Context, f iemproto.Frame ) (int, error) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if err := ctx.Err(); err != nil {
+    return 0, err
+  }
+
+  c.stats.Requests++
+
+  return c.port.Write( iemproto.Encode( f ) )
+}
+
+/*
+    Procedure Name : Command
+
+    Description    : Sends a set-state command and confirms that the IEM
+                     took it. The confirmation is repeated until it sees
+                     the new state or the command's timeout runs out; a
+                     command that was not accepted is sent again as the
+                     command's policy allows. Any acknowledgement the unit
+                     sends is passed over by the confirming query and kept
+                     for Unsolicited. A command without a confirmation is
+                     sent once and fails with ErrUnconfirmed, as nothing
+                     shows whether it took; the caller decides whether
+                     the rest of the test will.
+
+    Arguments      : ctx     - Context of the command
+                     f       - Command frame
+                     confirm - Confirmation of the new state, nil if none
+
+    Return Value   : Any error, as a RequestError
+*/
+
+func (c *Client) Command( ctx context.Context, f iemproto.Frame, confirm Confirm ) error {
+  var err error
+
+  policy := c.Policy( f.Selector )
+  attempts := 0
+
+  if confirm == nil {
+    _, err = c.Send( ctx, f )
+
+    if err == nil {
+      err = ErrUnconfirmed
+    }
+
+    return &RequestError{ f.Selector, f.Subselector, 1, err }
+  }
+
+  for attempts <= policy.Retries {
+    if attempts > 0 {
+      c.mu.Lock()
+      c.stats.Retries++
+      c.mu.Unlock()
+
+      err = pause( ctx, policy.Backoff )
+
+      if err != nil {
+        break
+      }
+    }
+
+    attempts++
+
+    _, err = c.Send( ctx, f )
+
+    if err == nil {
+      err = c.confirm( ctx, confirm, policy.Timeout )
+    }
+
+    if !errors.Is( err, ErrNotAccepted ) || ctx.Err() != nil {
+      break
+    }
+  }
+
+  if err != nil {
+    return &RequestError{ f.Selector, f.Subselector, attempts, err }
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : confirm
+
+    Description    : Repeats a confirmation until it sees the new state or
+                     the time allowed runs out.
+
+    Arguments      : ctx     - Context of the command
+                     confirm - Confirmation of the new state
+                     timeout - Time allowed for the new state to be seen
+
+    Return Value   : nil once the state is seen
+                     The last ErrNotAccepted if it never was, or the
+                     error of a confirming query that failed
+*/
+
+func (c *Client) confirm( ctx context.Context, confirm Confirm, timeout time.Duration ) error {
+  var notAccepted error
+
+  confirmCtx, cancel := context.WithTimeout( ctx, timeout )
+  defer cancel()
+
+  for {
+    err := confirm( confirmCtx )
+
+    if !errors.Is( err, ErrNotAccepted ) {
+      /*
+          A query cut off by the end of the time allowed only means the
+          state was still not seen.
+      */
+
+      if err != nil && notAccepted != nil && confirmCtx.Err() != nil && ctx.Err() == nil {
+        return notAccepted
+      }
+
+      return err
+    }
+
+    notAccepted = err
+
+    if pause( confirmCtx, CONFIRM_INTERVAL ) != nil {
+      return notAccepted
+    }
+  }
+}
+
+/*
+    Procedure Name : Query
+
+    Description    : Sends a request and waits for its response, sending
+                     it again after a lost, short, corrupted or unexpected
+                     response as the selector's policy allows.
+
+    Arguments      : ctx     - Context of the request; its deadline and
+                               cancellation end the query early
+                     request - Request frame
+                     dataLen - Number of data bytes the response must have
+
+    Return Value   : The response
+                     Any error, as a RequestError
+*/
+
+func (c *Client) Query( ctx context.Context, request iemproto.Frame, dataLen int ) (iemproto.Frame, error) {
+  var response iemproto.Frame
+  var err error
+
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  policy := c.Policy( request.Selector )
+  attempts := 0
+
+  c.stats.Requests++
+
+  for attempts <= policy.Retries {
+    if attempts > 0 {
+      c.stats.Retries++
+
+      err = pause( ctx, policy.Backoff )
+
+      if err != nil {
+        break
+      }
+    }
+
+    attempts++
+
+    response, err = c.exchange( ctx, request, dataLen, policy.Timeout )
+
+    c.count( err )
+
+    if err == nil || !Retryable( err ) || ctx.Err() != nil {
+      break
+    }
+  }
+
+  if err != nil {
+    return response, &RequestError{ request.Selector, request.Subselector, attempts, err }
+  }
+
+  return response, nil
+}
+
+/*
+    Procedure Name : exchange
+
+    Description    : Sends a request once and waits for its response.
+                     Frames that answer some other request, such as the
+                     late answer to an earlier one, are passed over and
+                     kept for Unsolicited. The caller must hold the client
+                     lock.
+
+    Arguments      : ctx     - Context of the request
+                     request - Request frame
+                     dataLen - Number of data bytes the response must have
+                     timeout - Time allowed for the response
+
+    Return Value   : The response
+                     ErrNoResponse if nothing arrived, ErrShortFrame if
+                     the response was cut short, ErrUnexpectedEcho if only
+                     other frames arrived, or any framing, CRC or link
+                     error
+*/
+
+func (c *Client) exchange( ctx context.Context, request iemproto.Frame, dataLen int, timeout time.Duration ) (iemproto.Frame, error) {
+  var response iemproto.Frame
+  var other error
+  var cut error
+
+  /*
+      Anything that arrived before the request was sent cannot be its
+      answer.
+  */
+
+  c.drain()
+
+  _, err := c.port.Write( iemproto.Encode( request ) )
+
+  if err != nil {
+    return response, err
+  }
+
+  waitCtx, cancel := context.WithTimeout( ctx, timeout )
+  defer cancel()
+
+  for {
+    select {
+      case rx, ok := <-c.frames :
+        if !ok {
+          return response, c.linkError()
+        }
+
+        c.stats.NoiseBytes += rx.Skipped
+
+        /*
+            A frame cut short by the start of another is usually the
+            remains of an earlier answer; the answer may be right
+            behind it.
+        */
+
+        if errors.Is( rx.Err, ErrShortFrame ) {
+          cut = rx.Err
+
+          continue
+        }
+
+        if rx.Err != nil {
+          return response, rx.Err
+        }
+
+        if rx.Frame.Selector != request.Selector || rx.Frame.Subselector != request.Subselector {
+          other = fmt.Errorf("%w: sent %02X/%02X, got %02X/%02X", ErrUnexpectedEcho,
+                             request.Selector, request.Subselector, rx.Frame.Selector, rx.Frame.Subselector)
+
+          c.keep( rx )
+
+          continue
+        }
+
+        response = rx.Frame
+
+        if len(response.Data) < dataLen {
+          return response, fmt.Errorf("%w: %d data bytes, expected %d", ErrShortFrame, len(response.Data), dataLen)
+        }
+
+        return response, nil
+
+      case <-waitCtx.Done() :
+        switch {
+          case errors.Is( ctx.Err(), context.Canceled ) :
+            return response, ctx.Err()
+
+          case c.partial.Load() > 0 :
+            return response, fmt.Errorf("%w: no end byte after %d bytes", ErrShortFrame, c.partial.Load())
+
+          case cut != nil :
+            return response, cut
+
+          case other != nil :
+            return response, other
+        }
+
+        return response, ErrNoResponse
+    }
+  }
+}
+
+/*
+    Procedure Name : drain
+
+    Description    : Takes every frame already received off the channel
+                     and keeps it for Unsolicited. The caller must hold
+                     the client lock.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (c *Client) drain() {
+  for {
+    select {
+      case rx, ok := <-c.frames :
+        if !ok {
+          return
+        }
+
+        c.stats.NoiseBytes += rx.Skipped
+
+        c.keep( rx )
+
+      default :
+        return
+    }
+  }
+}
+
+/*
+    Procedure Name : keep
+
+    Description    : Counts a frame that answers nothing that was asked
+                     and passes it to Unsolicited. If nobody is reading
+                     Unsolicited and it is full, the frame is dropped.
+                     The caller must hold the client lock.
+
+    Arguments      : rx - Received frame
+
+    Return Value   : This routine has no return value.
+*/
+
+func (c *Client) keep( rx iemproto.Received ) {
+  c.stats.Unsolicited++
+
+  select {
+    case c.unsolicited <- rx :
+    default :
+  }
+}
+
+/*
+    Procedure Name : Unsolicited
+
+    Description    : Returns the channel of frames that arrived without
+                     being asked for, or too late for the request they
+                     answer. Reading it is optional.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The channel
+*/
+
+func (c *Client) Unsolicited() <-chan iemproto.Received {
+  return c.unsolicited
+}
+
+/*
+    Procedure Name : receive
+
+    Description    : Runs the stream receiver on the link until the link
+                     fails or is closed. A frame that arrives while the
+                     frame buffer is full answers nothing being waited
+                     for; it is counted as unsolicited and passed to
+                     Unsolicited if there is room.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (c *Client) receive() {
+  var receiver iemproto.Receiver
+
+  err := receiver.Run( c.port, c.frames, func( n int ) { c.partial.Store( (int64) (n) ) }, func( rx iemproto.Received ) {
+    c.dropped.Add( 1 )
+
+    select {
+      case c.unsolicited <- rx :
+      default :
+    }
+  })
+
+  c.linkErr.Store( &err )
+
+  close( c.frames )
+}
+
+/*
+    Procedure Name : linkError
+
+    Description    : Returns the error that ended the link.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The error, io.ErrClosedPipe if none was recorded
+*/
+
+func (c *Client) linkError() error {
+  if err := c.linkErr.Load(); err != nil && *err != nil {
+    return *err
+  }
+
+  return io.ErrClosedPipe
+}
+
+/*
+    Procedure Name : count
+
+    Description    : Adds a failed exchange to the link statistics. The
+                     caller must hold the client lock.
+
+    Arguments      : err - Error from the exchange
+
+    Return Value   : This routine has no return value.
+*/
+
+func (c *Client) count( err error ) {
+  switch {
+    case err == nil :
+
+    case errors.Is( err, ErrNoResponse ) :
+      c.stats.NoResponse++
+
+    case errors.Is( err, ErrShortFrame ) :
+      c.stats.ShortFrames++
+
+    case errors.Is( err, ErrCRCMismatch ) :
+      c.stats.CRCMismatches++
+
+    case errors.Is( err, ErrUnexpectedEcho ) :
+      c.stats.UnexpectedEchoes++
+  }
+}
+
+/*
+    Procedure Name : pause
+
+    Description    : Waits before a request is sent again.
+
+    Arguments      : ctx - Context of the request
+                     d   - Time to wait
+
+    Return Value   : The context's error if it ends first
+*/
+
+func pause( ctx context.Context, d time.Duration ) error {
+  timer := time.NewTimer( d )
+
+  defer timer.Stop()
+
+  select {
+    case <-timer.C :
+      return nil
+
+    case <-ctx.Done() :
+      return ctx.Err()
+  }
+}
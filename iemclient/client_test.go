@@ -0,0 +1,264 @@
+package iemclient
+
+import (
+        "context"
+        "errors"
+        "testing"
+        "time"
+
+        "github.com/questrail/IEMTestDB/iemproto"
+        "github.com/questrail/IEMTestDB/iemsim"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+const testTimeout = 100*time.Millisecond         // Time allowed for each response in the tests
+const testCommandTimeout = 400*time.Millisecond  // Time allowed for a command to be confirmed in the tests
+
+/*
+    Procedure Name : connect
+
+    Description    : Starts a simulated IEM on an in-memory link and a
+                     client on the other end, with short timeouts so the
+                     faults do not slow the tests down. Both are stopped
+                     when the test ends.
+
+    Arguments      : t - Test state
+
+    Return Value   : The simulator
+                     The client
+*/
+
+func connect( t *testing.T ) (*iemsim.Simulator, *Client) {
+  tester, device := transport.Pipe( 10*time.Millisecond )
+
+  sim := iemsim.New()
+
+  go sim.Serve( device )
+
+  c := New( tester )
+
+  c.Default = Policy{ Timeout: testTimeout, Retries: 2, Backoff: time.Millisecond }
+  c.Policies[iemproto.SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD] = Policy{ Timeout: testCommandTimeout, Retries: 1, Backoff: time.Millisecond }
+
+  t.Cleanup( func() {
+    tester.Close()
+    device.Close()
+  })
+
+  return sim, c
+}
+
+/*
+    Procedure Name : attempts
+
+    Description    : Returns the number of times a failed request was
+                     sent.
+
+    Arguments      : err - Error from Query or Command
+
+    Return Value   : The attempts, 0 if err is not a RequestError
+*/
+
+func attempts( err error ) int {
+  var requestErr *RequestError
+
+  if errors.As( err, &requestErr ) {
+    return requestErr.Attempts
+  }
+
+  return 0
+}
+
+/*
+    Procedure Name : TestQuery
+
+    Description    : Queries the simulator with each fault injected, once
+                     and on every response, and checks that a single bad
+                     response is retried away and a persistent one fails
+                     with its own error after every retry.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestQuery( t *testing.T ) {
+  request := iemproto.Frame{ Selector: iemproto.MON_VOLTAGES, Subselector: iemproto.IEM_CPU_BOARD_03 }
+  dataLen := iemproto.MON_VOLTAGES_RESPONSE_LEN - iemproto.Overhead
+
+  tests := []struct {
+    name string
+    fault iemsim.Fault
+    count int
+    dataLen int
+    want error
+    attempts int
+    stats Stats
+  }{
+    { "good", iemsim.FaultNone, 0, dataLen, nil, 1, Stats{ Requests: 1 } },
+    { "noise", iemsim.FaultNoise, 1, dataLen, nil, 1, Stats{ Requests: 1, NoiseBytes: 13 } },
+    { "no response once", iemsim.FaultNoResponse, 1, dataLen, nil, 2, Stats{ Requests: 1, Retries: 1, NoResponse: 1 } },
+    { "bad CRC once", iemsim.FaultBadCRC, 1, dataLen, nil, 2, Stats{ Requests: 1, Retries: 1, CRCMismatches: 1 } },
+    { "truncated once", iemsim.FaultTruncate, 1, dataLen, nil, 2, Stats{ Requests: 1, Retries: 1, ShortFrames: 1 } },
+    { "no response", iemsim.FaultNoResponse, -1, dataLen, ErrNoResponse, 3, Stats{ Requests: 1, Retries: 2, NoResponse: 3 } },
+    { "bad CRC", iemsim.FaultBadCRC, -1, dataLen, ErrCRCMismatch, 3, Stats{ Requests: 1, Retries: 2, CRCMismatches: 3 } },
+    { "truncated", iemsim.FaultTruncate, -1, dataLen, ErrShortFrame, 3, Stats{ Requests: 1, Retries: 2, ShortFrames: 3 } },
+    { "too few data bytes", iemsim.FaultNone, 0, dataLen + 2, ErrShortFrame, 3, Stats{ Requests: 1, Retries: 2, ShortFrames: 3 } },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      sim, c := connect( t )
+
+      sim.InjectFault( request.Selector, request.Subselector, tt.fault, tt.count )
+
+      response, err := c.Query( context.Background(), request, tt.dataLen )
+
+      switch {
+        case tt.want == nil && err != nil :
+          t.Fatalf("Query: %v", err)
+
+        case tt.want != nil && !errors.Is( err, tt.want ) :
+          t.Fatalf("Query returned %v, want %v", err, tt.want)
+
+        case tt.want != nil && attempts( err ) != tt.attempts :
+          t.Errorf("%d attempts, want %d", attempts( err ), tt.attempts)
+
+        case tt.want == nil && (response.Selector != request.Selector || len(response.Data) != dataLen) :
+          t.Errorf("response %+v", response)
+      }
+
+      if stats := c.Stats(); stats != tt.stats {
+        t.Errorf("stats %+v, want %+v", stats, tt.stats)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestCommand
+
+    Description    : Drives an ABCM magnet valve through the simulator,
+                     confirming it from the status vector as the tester
+                     does, with faults on the command and on the
+                     confirming query.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestCommand( t *testing.T ) {
+  command := iemproto.Frame{ Selector: iemproto.SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD, Subselector: iemproto.ABCM_PROC_A_92, Data: []byte{ 1 } }
+  status := iemproto.Frame{ Selector: iemproto.STATUS_VECTOR, Subselector: iemproto.ABCM_12 }
+
+  commandKey := iemsim.Key{ Selector: command.Selector, Subselector: command.Subselector }
+  statusKey := iemsim.Key{ Selector: status.Selector, Subselector: status.Subselector }
+
+  tests := []struct {
+    name string
+    key iemsim.Key
+    fault iemsim.Fault
+    count int
+    want error
+    attempts int
+  }{
+    { "good", iemsim.Key{}, iemsim.FaultNone, 0, nil, 1 },
+    { "command lost once", commandKey, iemsim.FaultNoResponse, 1, nil, 2 },
+    { "command lost", commandKey, iemsim.FaultNoResponse, -1, ErrNotAccepted, 2 },
+    { "confirmation bad CRC once", statusKey, iemsim.FaultBadCRC, 1, nil, 1 },
+    { "confirmation truncated once", statusKey, iemsim.FaultTruncate, 1, nil, 1 },
+    { "confirmation bad CRC", statusKey, iemsim.FaultBadCRC, -1, ErrCRCMismatch, 1 },
+    { "confirmation truncated", statusKey, iemsim.FaultTruncate, -1, ErrShortFrame, 1 },
+    { "no confirmation", statusKey, iemsim.FaultNoResponse, -1, ErrNoResponse, 1 },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      sim, c := connect( t )
+
+      sim.InjectFault( tt.key.Selector, tt.key.Subselector, tt.fault, tt.count )
+
+      confirm := func( ctx context.Context ) error {
+        response, err := c.Query( ctx, status, 1 )
+
+        if err == nil && (response.Data[0] & iemproto.ABCM_A_MAG_VALVE_DRIVE) == 0 {
+          err = ErrNotAccepted
+        }
+
+        return err
+      }
+
+      err := c.Command( context.Background(), command, confirm )
+
+      switch {
+        case tt.want == nil && err != nil :
+          t.Fatalf("Command: %v", err)
+
+        case tt.want != nil && !errors.Is( err, tt.want ) :
+          t.Fatalf("Command returned %v, want %v", err, tt.want)
+
+        case tt.want != nil && attempts( err ) != tt.attempts :
+          t.Errorf("%d attempts, want %d", attempts( err ), tt.attempts)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestUnconfirmed
+
+    Description    : Checks that a command without a confirmation is sent
+                     once and reported as unconfirmed.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestUnconfirmed( t *testing.T ) {
+  _, c := connect( t )
+
+  err := c.Command( context.Background(), iemproto.Frame{ Selector: iemproto.SET_SPEED_SENSOR_REF_CMD, Data: []byte{ 1 } }, nil )
+
+  if !errors.Is( err, ErrUnconfirmed ) || attempts( err ) != 1 {
+    t.Errorf("Command returned %v after %d attempts, want %v after 1", err, attempts( err ), ErrUnconfirmed)
+  }
+
+  if stats := c.Stats(); stats != (Stats{ Requests: 1 }) {
+    t.Errorf("stats %+v, want one request", stats)
+  }
+}
+
+/*
+    Procedure Name : TestCancel
+
+    Description    : Checks that cancelling the context of a query ends
+                     it at once instead of after its timeout and retries.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestCancel( t *testing.T ) {
+  sim, c := connect( t )
+
+  sim.InjectFault( iemproto.MON_VOLTAGES, iemproto.IEM_CPU_BOARD_03, iemsim.FaultNoResponse, -1 )
+
+  ctx, cancel := context.WithCancel( context.Background() )
+
+  time.AfterFunc( testTimeout/2, cancel )
+
+  start := time.Now()
+
+  _, err := c.Query( ctx, iemproto.Frame{ Selector: iemproto.MON_VOLTAGES, Subselector: iemproto.IEM_CPU_BOARD_03 }, 0 )
+
+  if !errors.Is( err, context.Canceled ) {
+    t.Errorf("Query returned %v, want %v", err, context.Canceled)
+  }
+
+  if elapsed := time.Since( start ); elapsed >= testTimeout {
+    t.Errorf("Query took %v after being cancelled", elapsed)
+  }
+}
@@ -0,0 +1,296 @@
+package iemproto
+
+// CRC16 constants
+
+const zero_bit  int  = 0x1021
+const one_bit   int  = 0x2042
+const two_bit   int  = 0x4084
+const three_bit int  = 0x8108
+const four_bit  int  = 0x1231
+const five_bit  int  = 0x2462
+const six_bit   int  = 0x48c4
+const seven_bit int  = 0x9188
+
+var crc_table = [256]int{                                                                             0x0000, /* 0x00 */
+                                                                                                    zero_bit, /* 0x01 */
+                                                                                          one_bit           , /* 0x02 */
+                                                                                          one_bit ^ zero_bit, /* 0x03 */
+                                                                                two_bit                     , /* 0x04 */
+                                                                                two_bit           ^ zero_bit, /* 0x05 */
+                                                                                two_bit ^ one_bit           , /* 0x06 */
+                                                                                two_bit ^ one_bit ^ zero_bit, /* 0x07 */
+                                                                    three_bit                               , /* 0x08 */
+                                                                    three_bit                     ^ zero_bit, /* 0x09 */
+                                                                    three_bit           ^ one_bit           , /* 0x0A */
+                                                                    three_bit           ^ one_bit ^ zero_bit, /* 0x0B */
+                                                                    three_bit ^ two_bit                     , /* 0x0C */
+                                                                    three_bit ^ two_bit           ^ zero_bit, /* 0x0D */
+                                                                    three_bit ^ two_bit ^ one_bit           , /* 0x0E */
+                                                                    three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x0F */
+                                                         four_bit                                           , /* 0x10 */
+                                                         four_bit ^                                 zero_bit, /* 0x11 */
+                                                         four_bit ^                       one_bit           , /* 0x12 */
+                                                         four_bit ^                       one_bit ^ zero_bit, /* 0x13 */
+                                                         four_bit ^             two_bit                     , /* 0x14 */
+                                                         four_bit ^             two_bit           ^ zero_bit, /* 0x15 */
+                                                         four_bit ^             two_bit ^ one_bit           , /* 0x16 */
+                                                         four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x17 */
+                                                         four_bit ^ three_bit                               , /* 0x18 */
+                                                         four_bit ^ three_bit                     ^ zero_bit, /* 0x19 */
+                                                         four_bit ^ three_bit           ^ one_bit           , /* 0x1A */
+                                                         four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x1B */
+                                                         four_bit ^ three_bit ^ two_bit                     , /* 0x1C */
+                                                         four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x1D */
+                                                         four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x1E */
+                                                         four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x1F */
+                                              five_bit                                                      , /* 0x20 */
+                                              five_bit ^                                            zero_bit, /* 0x21 */
+                                              five_bit ^                                  one_bit           , /* 0x22 */
+                                              five_bit ^                                  one_bit ^ zero_bit, /* 0x23 */
+                                              five_bit ^                        two_bit                     , /* 0x24 */
+                                              five_bit ^                        two_bit           ^ zero_bit, /* 0x25 */
+                                              five_bit ^                        two_bit ^ one_bit           , /* 0x26 */
+                                              five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0x27 */
+                                              five_bit ^            three_bit                               , /* 0x28 */
+                                              five_bit ^            three_bit                     ^ zero_bit, /* 0x29 */
+                                              five_bit ^            three_bit           ^ one_bit           , /* 0x2A */
+                                              five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0x2B */
+                                              five_bit ^            three_bit ^ two_bit                     , /* 0x2C */
+                                              five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0x2D */
+                                              five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0x2E */
+                                              five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x2F */
+                                              five_bit ^ four_bit                                           , /* 0x30 */
+                                              five_bit ^ four_bit ^                                 zero_bit, /* 0x31 */
+                                              five_bit ^ four_bit ^                       one_bit           , /* 0x32 */
+                                              five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0x33 */
+                                              five_bit ^ four_bit ^             two_bit                     , /* 0x34 */
+                                              five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0x35 */
+                                              five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0x36 */
+                                              five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x37 */
+                                              five_bit ^ four_bit ^ three_bit                               , /* 0x38 */
+                                              five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0x39 */
+                                              five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0x3A */
+                                              five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x3B */
+                                              five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0x3C */
+                                              five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x3D */
+                                              five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x3E */
+                                              five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x3F */
+                                    six_bit                                                                 , /* 0x40 */
+                                    six_bit ^                                                       zero_bit, /* 0x41 */
+                                    six_bit ^                                             one_bit           , /* 0x42 */
+                                    six_bit ^                                             one_bit ^ zero_bit, /* 0x43 */
+                                    six_bit ^                                   two_bit                     , /* 0x44 */
+                                    six_bit ^                                   two_bit           ^ zero_bit, /* 0x45 */
+                                    six_bit ^                                   two_bit ^ one_bit           , /* 0x46 */
+                                    six_bit ^                                   two_bit ^ one_bit ^ zero_bit, /* 0x47 */
+                                    six_bit ^                       three_bit                               , /* 0x48 */
+                                    six_bit ^                       three_bit                     ^ zero_bit, /* 0x49 */
+                                    six_bit ^                       three_bit           ^ one_bit           , /* 0x4A */
+                                    six_bit ^                       three_bit           ^ one_bit ^ zero_bit, /* 0x4B */
+                                    six_bit ^                       three_bit ^ two_bit                     , /* 0x4C */
+                                    six_bit ^                       three_bit ^ two_bit           ^ zero_bit, /* 0x4D */
+                                    six_bit ^                       three_bit ^ two_bit ^ one_bit           , /* 0x4E */
+                                    six_bit ^                       three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x4F */
+                                    six_bit ^            four_bit                                           , /* 0x50 */
+                                    six_bit ^            four_bit ^                                 zero_bit, /* 0x51 */
+                                    six_bit ^            four_bit ^                       one_bit           , /* 0x52 */
+                                    six_bit ^            four_bit ^                       one_bit ^ zero_bit, /* 0x53 */
+                                    six_bit ^            four_bit ^             two_bit                     , /* 0x54 */
+                                    six_bit ^            four_bit ^             two_bit           ^ zero_bit, /* 0x55 */
+                                    six_bit ^            four_bit ^             two_bit ^ one_bit           , /* 0x56 */
+                                    six_bit ^            four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x57 */
+                                    six_bit ^            four_bit ^ three_bit                               , /* 0x58 */
+                                    six_bit ^            four_bit ^ three_bit                     ^ zero_bit, /* 0x59 */
+                                    six_bit ^            four_bit ^ three_bit           ^ one_bit           , /* 0x5A */
+                                    six_bit ^            four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x5B */
+                                    six_bit ^            four_bit ^ three_bit ^ two_bit                     , /* 0x5C */
+                                    six_bit ^            four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x5D */
+                                    six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x5E */
+                                    six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x5F */
+                                    six_bit ^ five_bit                                                      , /* 0x60 */
+                                    six_bit ^ five_bit ^                                            zero_bit, /* 0x61 */
+                                    six_bit ^ five_bit ^                                  one_bit           , /* 0x62 */
+                                    six_bit ^ five_bit ^                                  one_bit ^ zero_bit, /* 0x63 */
+                                    six_bit ^ five_bit ^                        two_bit                     , /* 0x64 */
+                                    six_bit ^ five_bit ^                        two_bit           ^ zero_bit, /* 0x65 */
+                                    six_bit ^ five_bit ^                        two_bit ^ one_bit           , /* 0x66 */
+                                    six_bit ^ five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0x67 */
+                                    six_bit ^ five_bit ^            three_bit                               , /* 0x68 */
+                                    six_bit ^ five_bit ^            three_bit                     ^ zero_bit, /* 0x69 */
+                                    six_bit ^ five_bit ^            three_bit           ^ one_bit           , /* 0x6A */
+                                    six_bit ^ five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0x6B */
+                                    six_bit ^ five_bit ^            three_bit ^ two_bit                     , /* 0x6C */
+                                    six_bit ^ five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0x6D */
+                                    six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0x6E */
+                                    six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x6F */
+                                    six_bit ^ five_bit ^ four_bit                                           , /* 0x70 */
+                                    six_bit ^ five_bit ^ four_bit ^                                 zero_bit, /* 0x71 */
+                                    six_bit ^ five_bit ^ four_bit ^                       one_bit           , /* 0x72 */
+                                    six_bit ^ five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0x73 */
+                                    six_bit ^ five_bit ^ four_bit ^             two_bit                     , /* 0x74 */
+                                    six_bit ^ five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0x75 */
+                                    six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0x76 */
+                                    six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x77 */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit                               , /* 0x78 */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0x79 */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0x7A */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x7B */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0x7C */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x7D */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x7E */
+                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x7F */
+                        seven_bit                                                                           , /* 0x80 */
+                        seven_bit ^                                                                 zero_bit, /* 0x81 */
+                        seven_bit ^                                                       one_bit           , /* 0x82 */
+                        seven_bit ^                                                       one_bit ^ zero_bit, /* 0x83 */
+                        seven_bit ^                                             two_bit                     , /* 0x84 */
+                        seven_bit ^                                             two_bit           ^ zero_bit, /* 0x85 */
+                        seven_bit ^                                             two_bit ^ one_bit           , /* 0x86 */
+                        seven_bit ^                                             two_bit ^ one_bit ^ zero_bit, /* 0x87 */
+                        seven_bit ^                                 three_bit                               , /* 0x88 */
+                        seven_bit ^                                 three_bit                     ^ zero_bit, /* 0x89 */
+                        seven_bit ^                                 three_bit           ^ one_bit           , /* 0x8A */
+                        seven_bit ^                                 three_bit           ^ one_bit ^ zero_bit, /* 0x8B */
+                        seven_bit ^                                 three_bit ^ two_bit                     , /* 0x8C */
+                        seven_bit ^                                 three_bit ^ two_bit           ^ zero_bit, /* 0x8D */
+                        seven_bit ^                                 three_bit ^ two_bit ^ one_bit           , /* 0x8E */
+                        seven_bit ^                                 three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x8F */
+                        seven_bit ^                      four_bit                                           , /* 0x90 */
+                        seven_bit ^                      four_bit ^                                 zero_bit, /* 0x91 */
+                        seven_bit ^                      four_bit ^                       one_bit           , /* 0x92 */
+                        seven_bit ^                      four_bit ^                       one_bit ^ zero_bit, /* 0x93 */
+                        seven_bit ^                      four_bit ^             two_bit                     , /* 0x94 */
+                        seven_bit ^                      four_bit ^             two_bit           ^ zero_bit, /* 0x95 */
+                        seven_bit ^                      four_bit ^             two_bit ^ one_bit           , /* 0x96 */
+                        seven_bit ^                      four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x97 */
+                        seven_bit ^                      four_bit ^ three_bit                               , /* 0x98 */
+                        seven_bit ^                      four_bit ^ three_bit                     ^ zero_bit, /* 0x99 */
+                        seven_bit ^                      four_bit ^ three_bit           ^ one_bit           , /* 0x9A */
+                        seven_bit ^                      four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x9B */
+                        seven_bit ^                      four_bit ^ three_bit ^ two_bit                     , /* 0x9C */
+                        seven_bit ^                      four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x9D */
+                        seven_bit ^                      four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x9E */
+                        seven_bit ^                      four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x9F */
+                        seven_bit ^           five_bit                                                      , /* 0xA0 */
+                        seven_bit ^           five_bit ^                                            zero_bit, /* 0xA1 */
+                        seven_bit ^           five_bit ^                                  one_bit           , /* 0xA2 */
+                        seven_bit ^           five_bit ^                                  one_bit ^ zero_bit, /* 0xA3 */
+                        seven_bit ^           five_bit ^                        two_bit                     , /* 0xA4 */
+                        seven_bit ^           five_bit ^                        two_bit           ^ zero_bit, /* 0xA5 */
+                        seven_bit ^           five_bit ^                        two_bit ^ one_bit           , /* 0xA6 */
+                        seven_bit ^           five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0xA7 */
+                        seven_bit ^           five_bit ^            three_bit                               , /* 0xA8 */
+                        seven_bit ^           five_bit ^            three_bit                     ^ zero_bit, /* 0xA9 */
+                        seven_bit ^           five_bit ^            three_bit           ^ one_bit           , /* 0xAA */
+                        seven_bit ^           five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0xAB */
+                        seven_bit ^           five_bit ^            three_bit ^ two_bit                     , /* 0xAC */
+                        seven_bit ^           five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0xAD */
+                        seven_bit ^           five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0xAE */
+                        seven_bit ^           five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xAF */
+                        seven_bit ^           five_bit ^ four_bit                                           , /* 0xB0 */
+                        seven_bit ^           five_bit ^ four_bit ^                                 zero_bit, /* 0xB1 */
+                        seven_bit ^           five_bit ^ four_bit ^                       one_bit           , /* 0xB2 */
+                        seven_bit ^           five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0xB3 */
+                        seven_bit ^           five_bit ^ four_bit ^             two_bit                     , /* 0xB4 */
+                        seven_bit ^           five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0xB5 */
+                        seven_bit ^           five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0xB6 */
+                        seven_bit ^           five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xB7 */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit                               , /* 0xB8 */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0xB9 */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0xBA */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xBB */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0xBC */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xBD */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xBE */
+                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xBF */
+                        seven_bit ^ six_bit                                                                 , /* 0xC0 */
+                        seven_bit ^ six_bit ^                                                       zero_bit, /* 0xC1 */
+                        seven_bit ^ six_bit ^                                             one_bit           , /* 0xC2 */
+                        seven_bit ^ six_bit ^                                             one_bit ^ zero_bit, /* 0xC3 */
+                        seven_bit ^ six_bit ^                                   two_bit                     , /* 0xC4 */
+                        seven_bit ^ six_bit ^                                   two_bit           ^ zero_bit, /* 0xC5 */
+                        seven_bit ^ six_bit ^                                   two_bit ^ one_bit           , /* 0xC6 */
+                        seven_bit ^ six_bit ^                                   two_bit ^ one_bit ^ zero_bit, /* 0xC7 */
+                        seven_bit ^ six_bit ^                       three_bit                               , /* 0xC8 */
+                        seven_bit ^ six_bit ^                       three_bit                     ^ zero_bit, /* 0xC9 */
+                        seven_bit ^ six_bit ^                       three_bit           ^ one_bit           , /* 0xCA */
+                        seven_bit ^ six_bit ^                       three_bit           ^ one_bit ^ zero_bit, /* 0xCB */
+                        seven_bit ^ six_bit ^                       three_bit ^ two_bit                     , /* 0xCC */
+                        seven_bit ^ six_bit ^                       three_bit ^ two_bit           ^ zero_bit, /* 0xCD */
+                        seven_bit ^ six_bit ^                       three_bit ^ two_bit ^ one_bit           , /* 0xCE */
+                        seven_bit ^ six_bit ^                       three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xCF */
+                        seven_bit ^ six_bit ^            four_bit                                           , /* 0xD0 */
+                        seven_bit ^ six_bit ^            four_bit ^                                 zero_bit, /* 0xD1 */
+                        seven_bit ^ six_bit ^            four_bit ^                       one_bit           , /* 0xD2 */
+                        seven_bit ^ six_bit ^            four_bit ^                       one_bit ^ zero_bit, /* 0xD3 */
+                        seven_bit ^ six_bit ^            four_bit ^             two_bit                     , /* 0xD4 */
+                        seven_bit ^ six_bit ^            four_bit ^             two_bit           ^ zero_bit, /* 0xD5 */
+                        seven_bit ^ six_bit ^            four_bit ^             two_bit ^ one_bit           , /* 0xD6 */
+                        seven_bit ^ six_bit ^            four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xD7 */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit                               , /* 0xD8 */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit                     ^ zero_bit, /* 0xD9 */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit           ^ one_bit           , /* 0xDA */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xDB */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit                     , /* 0xDC */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xDD */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xDE */
+                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xDF */
+                        seven_bit ^ six_bit ^ five_bit                                                      , /* 0xE0 */
+                        seven_bit ^ six_bit ^ five_bit ^                                            zero_bit, /* 0xE1 */
+                        seven_bit ^ six_bit ^ five_bit ^                                  one_bit           , /* 0xE2 */
+                        seven_bit ^ six_bit ^ five_bit ^                                  one_bit ^ zero_bit, /* 0xE3 */
+                        seven_bit ^ six_bit ^ five_bit ^                        two_bit                     , /* 0xE4 */
+                        seven_bit ^ six_bit ^ five_bit ^                        two_bit           ^ zero_bit, /* 0xE5 */
+                        seven_bit ^ six_bit ^ five_bit ^                        two_bit ^ one_bit           , /* 0xE6 */
+                        seven_bit ^ six_bit ^ five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0xE7 */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit                               , /* 0xE8 */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit                     ^ zero_bit, /* 0xE9 */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit           ^ one_bit           , /* 0xEA */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0xEB */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit                     , /* 0xEC */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0xED */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0xEE */
+                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xEF */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit                                           , /* 0xF0 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                                 zero_bit, /* 0xF1 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                       one_bit           , /* 0xF2 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0xF3 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit                     , /* 0xF4 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0xF5 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0xF6 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xF7 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit                               , /* 0xF8 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0xF9 */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0xFA */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xFB */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0xFC */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xFD */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xFE */
+                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xFF */ }
+
+
+/*
+   Procedure Name : CRC16
+
+   Description    : Calculates the CRC on IEM messages. The CRC covers
+                    everything between the start byte and the CRC itself
+                    before any byte stuffing is done.
+
+   Arguments      : bytes - Slice containing the message to be CRCed
+
+   Return Value   : Calculated Message CRC
+*/
+
+func CRC16( bytes []byte ) uint16 {
+  crc := 0
+  index := 0
+
+  for i := 0;i < len(bytes);i++ {
+    crc ^= ((int) (bytes[i]) << 8)
+    index = (crc >> 8) & 0xff
+
+    crc = ((crc << 8) ^ crc_table[index]) & 0xffff
+  }
+
+  return (uint16) (crc)
+}
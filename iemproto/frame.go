@@ -0,0 +1,232 @@
+/*
+   Package iemproto implements the framing used on the serial link between
+   the tester and the IEM.
+
+   A frame on the wire looks like
+
+     0xf5 selector subselector data... crc-high crc-low 0xf6
+
+   Any byte between the start and end bytes whose upper nibble is 0xf is
+   sent as 0xf0 followed by the lower nibble of the byte. The CRC covers the
+   selector, subselector and data bytes before stuffing.
+*/
+
+package iemproto
+
+import (
+        "errors"
+        "fmt"
+)
+
+// Framing Constants
+
+const StartByte byte = 0xf5          // First byte of every frame
+const EndByte   byte = 0xf6          // Last byte of every frame
+const StuffByte byte = 0xf0          // Escape byte used for byte stuffing
+
+const HeaderLen = 2                  // Selector and subselector
+const CRCLen    = 2                  // CRC high and low bytes
+
+// Bytes of an unstuffed frame that are not data: start, header, CRC and end
+
+const Overhead  = HeaderLen + CRCLen + 2
+
+// Framing Errors
+
+var ErrBadStartByte   = errors.New("iemproto: bad start byte")
+var ErrMissingEndByte = errors.New("iemproto: missing end byte")
+var ErrBadStuffing    = errors.New("iemproto: bad byte stuffing")
+var ErrShortFrame     = errors.New("iemproto: frame too short")
+var ErrCRCMismatch    = errors.New("iemproto: CRC mismatch")
+
+/* Frame Structure */
+
+type Frame struct {
+  Selector byte                      // Main information selector or command
+  Subselector byte                   // Information subselector or component selector
+  Data []byte                        // Message data following the subselector
+}
+
+/*
+    Procedure Name : Payload
+
+    Description    : Returns the frame bytes covered by the CRC.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Selector, subselector and data bytes
+*/
+
+func (f Frame) Payload() []byte {
+  payload := make([]byte, 0, HeaderLen + len(f.Data))
+
+  payload = append(payload, f.Selector, f.Subselector)
+  payload = append(payload, f.Data...)
+
+  return payload
+}
+
+/*
+    Procedure Name : Stuff
+
+    Description    : Appends a byte to a message doing the byte
+                     stuffing as it goes.
+
+    Arguments      : output - Message being built
+                     b      - Byte to add to the message
+
+    Return Value   : The extended message
+*/
+
+func Stuff( output []byte, b byte ) []byte {
+  if (b & 0xf0) == 0xf0 {
+    return append(output, StuffByte, b & 0x0f)
+  }
+
+  return append(output, b)
+}
+
+/*
+    Procedure Name : Encode
+
+    Description    : Builds the complete wire message for a frame. It
+                     calculates the CRC, does the byte stuffing and
+                     adds the start and end bytes.
+
+    Arguments      : f - Frame to encode
+
+    Return Value   : Bytes ready to be sent to the IEM
+*/
+
+func Encode( f Frame ) []byte {
+  payload := f.Payload()
+  crc := CRC16( payload )
+
+  output := make([]byte, 0, 2*(len(payload) + CRCLen) + 2)
+
+  output = append(output, StartByte)
+
+  for i := 0;i < len(payload);i++ {
+    output = Stuff( output, payload[i] )
+  }
+
+  output = Stuff( output, (byte) (crc >> 8) )
+  output = Stuff( output, (byte) (crc & 0xff) )
+
+  output = append(output, EndByte)
+
+  return output
+}
+
+/*
+    Procedure Name : Unstuff
+
+    Description    : Reverses the byte stuffing on the bytes found
+                     between the start and end bytes of a frame.
+
+    Arguments      : bytes - Stuffed message body
+
+    Return Value   : Unstuffed message body
+                     ErrBadStuffing if the body contains an invalid escape
+*/
+
+func Unstuff( bytes []byte ) ([]byte, error) {
+  output := make([]byte, 0, len(bytes))
+
+  for i := 0;i < len(bytes);i++ {
+    if bytes[i] == StuffByte {
+      if i + 1 >= len(bytes) || (bytes[i + 1] & 0xf0) != 0 {
+        return nil, ErrBadStuffing
+      }
+
+      i += 1
+
+      output = append(output, StuffByte | bytes[i])
+    } else {
+      if (bytes[i] & 0xf0) == 0xf0 {
+        return nil, ErrBadStuffing
+      }
+
+      output = append(output, bytes[i])
+    }
+  }
+
+  return output, nil
+}
+
+/*
+    Procedure Name : Decode
+
+    Description    : Takes a complete wire message from the IEM, checks
+                     the framing, reverses the byte stuffing and verifies
+                     the CRC.
+
+    Arguments      : raw - Bytes received from the IEM, starting with the
+                           start byte and ending with the end byte
+
+    Return Value   : The decoded frame
+                     Any framing or CRC error
+*/
+
+func Decode( raw []byte ) (Frame, error) {
+  var f Frame
+
+  if len(raw) == 0 || raw[0] != StartByte {
+    return f, ErrBadStartByte
+  }
+
+  if len(raw) < 2 || raw[len(raw) - 1] != EndByte {
+    return f, ErrMissingEndByte
+  }
+
+  body, err := Unstuff( raw[1:len(raw) - 1] )
+
+  if err != nil {
+    return f, err
+  }
+
+  if len(body) < HeaderLen + CRCLen {
+    return f, ErrShortFrame
+  }
+
+  n := len(body) - CRCLen
+
+  received := ((uint16) (body[n]) << 8) | (uint16) (body[n + 1])
+  expected := CRC16( body[:n] )
+
+  if received != expected {
+    return f, fmt.Errorf("%w (got %04X expected %04X)", ErrCRCMismatch, received, expected)
+  }
+
+  f.Selector = body[0]
+  f.Subselector = body[1]
+  f.Data = body[HeaderLen:n]
+
+  return f, nil
+}
+
+/*
+    Procedure Name : Unstuffed
+
+    Description    : Returns a frame as it is on the wire with the byte
+                     stuffing reversed: start byte, selector, subselector,
+                     data, CRC and end byte. This is the layout the
+                     response parsers index into.
+
+    Arguments      : f - Frame
+
+    Return Value   : The unstuffed frame bytes
+*/
+
+func Unstuffed( f Frame ) []byte {
+  payload := f.Payload()
+  crc := CRC16( payload )
+
+  output := make([]byte, 0, len(payload) + Overhead)
+
+  output = append(output, StartByte)
+  output = append(output, payload...)
+  output = append(output, (byte) (crc >> 8), (byte) (crc & 0xff), EndByte)
+
+  return output
+}
@@ -0,0 +1,112 @@
+package iemproto
+
+import (
+        "bytes"
+        "errors"
+        "testing"
+)
+
+/*
+    Procedure Name : TestEncodeDecode
+
+    Description    : Checks that every frame comes back from Decode as it
+                     went into Encode, including frames whose header, data
+                     or CRC need byte stuffing, and that no start or end
+                     byte is left inside an encoded frame.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestEncodeDecode( t *testing.T ) {
+  tests := []struct {
+    name string
+    frame Frame
+  }{
+    { "no data", Frame{ Selector: MON_VOLTAGES, Subselector: 0x01 } },
+    { "data", Frame{ Selector: CUR_4_20MA_INPUTS, Subselector: 0x00, Data: []byte{ 0x01, 0x02, 0x03, 0x04 } } },
+    { "stuffed data", Frame{ Selector: 0x03, Subselector: 0x01, Data: []byte{ StartByte, EndByte, StuffByte, 0xff } } },
+    { "stuffed header", Frame{ Selector: 0xf1, Subselector: 0xfe, Data: []byte{ 0x00 } } },
+    { "long stuffed data", Frame{ Selector: 0x32, Subselector: 0x00, Data: bytes.Repeat( []byte{ 0xf3 }, 100 ) } },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      raw := Encode( tt.frame )
+
+      for i := 1;i < len(raw) - 1;i++ {
+        if raw[i] == StartByte || raw[i] == EndByte {
+          t.Fatalf("start or end byte at %d inside % x", i, raw)
+        }
+      }
+
+      f, err := Decode( raw )
+
+      if err != nil {
+        t.Fatalf("Decode(% x): %v", raw, err)
+      }
+
+      if f.Selector != tt.frame.Selector || f.Subselector != tt.frame.Subselector || !bytes.Equal( f.Data, tt.frame.Data ) {
+        t.Errorf("Decode(Encode(%+v)) = %+v", tt.frame, f)
+      }
+
+      body, err := Unstuff( raw[1:len(raw) - 1] )
+
+      if err != nil {
+        t.Fatalf("Unstuff(% x): %v", raw, err)
+      }
+
+      unstuffed := append(append([]byte{ StartByte }, body...), EndByte)
+
+      if !bytes.Equal( unstuffed, Unstuffed( tt.frame ) ) {
+        t.Errorf("Unstuffed(%+v) = % x, want % x", tt.frame, Unstuffed( tt.frame ), unstuffed)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestDecodeErrors
+
+    Description    : Checks that each kind of bad frame is reported with
+                     its own error.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestDecodeErrors( t *testing.T ) {
+  good := Encode( Frame{ Selector: MON_VOLTAGES, Subselector: 0x01 } )
+
+  badCRC := append([]byte(nil), good...)
+  badCRC[1] = HW_VERSION
+
+  tests := []struct {
+    name string
+    raw []byte
+    want error
+  }{
+    { "empty", nil, ErrBadStartByte },
+    { "no start byte", good[1:], ErrBadStartByte },
+    { "start byte only", []byte{ StartByte }, ErrMissingEndByte },
+    { "no end byte", good[:len(good) - 1], ErrMissingEndByte },
+    { "escape at end", []byte{ StartByte, 0x03, 0x01, StuffByte, EndByte }, ErrBadStuffing },
+    { "bad escape", []byte{ StartByte, 0x03, StuffByte, 0x13, 0x01, 0x02, EndByte }, ErrBadStuffing },
+    { "unstuffed byte", []byte{ StartByte, 0x03, 0xf3, 0x01, 0x02, EndByte }, ErrBadStuffing },
+    { "header only", []byte{ StartByte, 0x03, 0x01, EndByte }, ErrShortFrame },
+    { "empty frame", []byte{ StartByte, EndByte }, ErrShortFrame },
+    { "bad CRC", badCRC, ErrCRCMismatch },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      _, err := Decode( tt.raw )
+
+      if !errors.Is( err, tt.want ) {
+        t.Errorf("Decode(% x) = %v, want %v", tt.raw, err, tt.want)
+      }
+    })
+  }
+}
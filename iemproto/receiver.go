@@ -0,0 +1,230 @@
+package iemproto
+
+import (
+        "errors"
+        "fmt"
+        "io"
+)
+
+const MAX_FRAME_LEN = 256          // Longest stuffed frame the IEM sends
+
+/* Receiver States */
+
+const (
+  stateIdle = iota                   // Throwing bytes away until a start byte
+  stateFrame                         // Collecting the bytes of a frame
+  stateEscape                        // The last byte was a stuff byte
+)
+
+/* Frame taken off the link */
+
+type Received struct {
+  Frame Frame                        // Decoded frame, empty if Err is set
+  Raw []byte                         // Bytes from the start byte to the end byte
+  Err error                          // Framing or CRC error
+  Skipped int                        // Noise bytes thrown away before the frame
+}
+
+/* Stream receiver */
+
+type Receiver struct {
+  state int                          // Receiver state
+  raw []byte                         // Frame being collected
+  skipped int                        // Noise bytes thrown away since the last frame
+}
+
+/*
+    Procedure Name : Feed
+
+    Description    : Passes bytes read from the link through the receiver.
+                     Bytes in front of a start byte, such as power-on text
+                     or the tail of a frame that was missed, are thrown
+                     away. A frame may be split over any number of reads,
+                     including between a stuff byte and the byte it
+                     escapes, and one read may hold several frames. A
+                     frame cut short by the start of another is returned
+                     with ErrShortFrame.
+
+    Arguments      : b - Bytes read from the link
+
+    Return Value   : The frames completed by these bytes, in order
+*/
+
+func (r *Receiver) Feed( b []byte ) []Received {
+  var frames []Received
+
+  for i := 0;i < len(b);i++ {
+    c := b[i]
+
+    switch {
+      case c == StartByte :
+        if r.state != stateIdle {
+          frames = append(frames, r.fail( fmt.Errorf("%w: no end byte after %d bytes", ErrShortFrame, len(r.raw)) ))
+        }
+
+        r.raw = append(r.raw[:0], c)
+        r.state = stateFrame
+
+      case r.state == stateIdle :
+        r.skipped++
+
+      case c == EndByte :
+        r.raw = append(r.raw, c)
+
+        if r.state == stateEscape {
+          frames = append(frames, r.fail( ErrBadStuffing ))
+        } else {
+          frames = append(frames, r.complete())
+        }
+
+      case r.state == stateEscape :
+        r.raw = append(r.raw, c)
+
+        if (c & 0xf0) != 0 {
+          frames = append(frames, r.fail( ErrBadStuffing ))
+        } else {
+          r.state = stateFrame
+        }
+
+      default :
+        r.raw = append(r.raw, c)
+
+        if c == StuffByte {
+          r.state = stateEscape
+        }
+
+        if len(r.raw) > MAX_FRAME_LEN {
+          frames = append(frames, r.fail( ErrMissingEndByte ))
+        }
+    }
+  }
+
+  return frames
+}
+
+/*
+    Procedure Name : Pending
+
+    Description    : Returns the number of bytes of a frame that has been
+                     started but not finished.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Bytes collected so far, 0 between frames
+*/
+
+func (r *Receiver) Pending() int {
+  if r.state == stateIdle {
+    return 0
+  }
+
+  return len(r.raw)
+}
+
+/*
+    Procedure Name : complete
+
+    Description    : Decodes the frame that has just been collected and
+                     goes back to waiting for a start byte.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The received frame
+*/
+
+func (r *Receiver) complete() Received {
+  raw := append([]byte(nil), r.raw...)
+
+  f, err := Decode( raw )
+
+  rx := Received{ Frame: f, Raw: raw, Err: err, Skipped: r.skipped }
+
+  r.reset()
+
+  return rx
+}
+
+/*
+    Procedure Name : fail
+
+    Description    : Gives up on the frame being collected and goes back
+                     to waiting for a start byte.
+
+    Arguments      : err - Why the frame was given up
+
+    Return Value   : The received frame, carrying the error
+*/
+
+func (r *Receiver) fail( err error ) Received {
+  rx := Received{ Raw: append([]byte(nil), r.raw...), Err: err, Skipped: r.skipped }
+
+  r.reset()
+
+  return rx
+}
+
+/*
+    Procedure Name : reset
+
+    Description    : Goes back to waiting for a start byte.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (r *Receiver) reset() {
+  r.raw = r.raw[:0]
+  r.skipped = 0
+  r.state = stateIdle
+}
+
+/*
+    Procedure Name : Run
+
+    Description    : Reads the link and sends every frame received on a
+                     channel until the link fails or is closed. A read
+                     that times out (io.EOF) is not a failure, so the link
+                     must report a connection closed by the other end as
+                     some other error, as the transports do with
+                     io.ErrClosedPipe. A frame that arrives while the
+                     channel is full is dropped rather than holding up
+                     the link, as nobody is waiting for it. Run blocks, so
+                     start it with go, and it leaves the channel open so
+                     the caller can record the error before closing it.
+
+    Arguments      : port    - Link to read
+                     frames  - Channel for the received frames
+                     pending - Called after every read with the number of
+                               bytes of an unfinished frame; may be nil
+                     dropped - Called with every frame dropped; may be nil
+
+    Return Value   : The error that ended the link
+*/
+
+func (r *Receiver) Run( port io.Reader, frames chan<- Received, pending func( int ), dropped func( Received ) ) error {
+  buf := make([]byte, 256)
+
+  for {
+    n, err := port.Read( buf )
+
+    for _, rx := range r.Feed( buf[:n] ) {
+      select {
+        case frames <- rx :
+
+        default :
+          if dropped != nil {
+            dropped( rx )
+          }
+      }
+    }
+
+    if pending != nil {
+      pending( r.Pending() )
+    }
+
+    if err != nil && !errors.Is( err, io.EOF ) {
+      return err
+    }
+  }
+}
@@ -0,0 +1,264 @@
+package iemproto
+
+import (
+        "bytes"
+        "errors"
+        "io"
+        "testing"
+)
+
+/* Frame the receiver should return */
+
+type wantFrame struct {
+  selector byte                      // Selector of a good frame
+  err error                          // Error the frame should carry, nil if good
+  skipped int                        // Noise bytes in front of the frame, -1 if not checked
+}
+
+/*
+    Procedure Name : join
+
+    Description    : Joins byte slices into one.
+
+    Arguments      : parts - Slices to join
+
+    Return Value   : The joined bytes
+*/
+
+func join( parts ...[]byte ) []byte {
+  return bytes.Join( parts, nil )
+}
+
+/*
+    Procedure Name : split
+
+    Description    : Cuts bytes into reads of a given size, the last read
+                     taking what is left.
+
+    Arguments      : b    - Bytes to cut
+                     size - Bytes in each read
+
+    Return Value   : The reads
+*/
+
+func split( b []byte, size int ) [][]byte {
+  var reads [][]byte
+
+  for len(b) > size {
+    reads = append(reads, b[:size])
+    b = b[size:]
+  }
+
+  return append(reads, b)
+}
+
+/*
+    Procedure Name : TestReceiverFeed
+
+    Description    : Feeds the receiver noise, truncated frames, bad
+                     stuffing and frames split across reads, and checks
+                     that it gives up on the bad frames and resynchronizes
+                     on the next start byte.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestReceiverFeed( t *testing.T ) {
+  voltages := Encode( Frame{ Selector: MON_VOLTAGES, Subselector: 0x01, Data: []byte{ 0x2e, 0xf0, 0x00, 0xff } } )
+  version := Encode( Frame{ Selector: HW_VERSION, Subselector: 0x00, Data: []byte{ 0x07 } } )
+  noise := []byte("IEM boot\r\n")
+
+  escape := bytes.IndexByte( voltages, StuffByte )
+
+  tests := []struct {
+    name string
+    reads [][]byte
+    want []wantFrame
+  }{
+    { "one frame", [][]byte{ voltages },
+      []wantFrame{ { MON_VOLTAGES, nil, 0 } } },
+
+    { "noise in front", [][]byte{ join( noise, voltages ) },
+      []wantFrame{ { MON_VOLTAGES, nil, len(noise) } } },
+
+    { "noise between frames", [][]byte{ join( voltages, noise, version ) },
+      []wantFrame{ { MON_VOLTAGES, nil, 0 }, { HW_VERSION, nil, len(noise) } } },
+
+    { "byte at a time", split( join( noise, voltages, version ), 1 ),
+      []wantFrame{ { MON_VOLTAGES, nil, len(noise) }, { HW_VERSION, nil, 0 } } },
+
+    { "split after escape", [][]byte{ voltages[:escape + 1], voltages[escape + 1:] },
+      []wantFrame{ { MON_VOLTAGES, nil, 0 } } },
+
+    { "truncated", [][]byte{ voltages[:len(voltages) - 3], version },
+      []wantFrame{ { 0, ErrShortFrame, 0 }, { HW_VERSION, nil, 0 } } },
+
+    { "truncated then noise", [][]byte{ voltages[:4], noise, voltages },
+      []wantFrame{ { 0, ErrShortFrame, 0 }, { MON_VOLTAGES, nil, 0 } } },
+
+    { "tail of a missed frame", [][]byte{ voltages[3:], version },
+      []wantFrame{ { HW_VERSION, nil, len(voltages) - 3 } } },
+
+    { "bad escape", [][]byte{ { StartByte, 0x03, StuffByte, 0x13 }, version },
+      []wantFrame{ { 0, ErrBadStuffing, 0 }, { HW_VERSION, nil, 0 } } },
+
+    { "escape before end", [][]byte{ { StartByte, 0x03, 0x01, StuffByte, EndByte }, version },
+      []wantFrame{ { 0, ErrBadStuffing, 0 }, { HW_VERSION, nil, 0 } } },
+
+    { "bad CRC", [][]byte{ join( version[:len(version) - 2], []byte{ version[len(version) - 2] ^ 0x01, EndByte } ), voltages },
+      []wantFrame{ { 0, ErrCRCMismatch, 0 }, { MON_VOLTAGES, nil, 0 } } },
+
+    { "no end byte", [][]byte{ join( []byte{ StartByte }, make([]byte, MAX_FRAME_LEN) ), version },
+      []wantFrame{ { 0, ErrMissingEndByte, 0 }, { HW_VERSION, nil, -1 } } },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      var r Receiver
+      var got []Received
+
+      for _, b := range tt.reads {
+        got = append(got, r.Feed( b )...)
+      }
+
+      if len(got) != len(tt.want) {
+        t.Fatalf("got %d frames, want %d: %+v", len(got), len(tt.want), got)
+      }
+
+      for i, want := range tt.want {
+        switch {
+          case want.err != nil && !errors.Is( got[i].Err, want.err ) :
+            t.Errorf("frame %d: error %v, want %v", i, got[i].Err, want.err)
+
+          case want.err == nil && got[i].Err != nil :
+            t.Errorf("frame %d: %v", i, got[i].Err)
+
+          case want.err == nil && got[i].Frame.Selector != want.selector :
+            t.Errorf("frame %d: selector %02x, want %02x", i, got[i].Frame.Selector, want.selector)
+        }
+
+        if want.skipped >= 0 && got[i].Skipped != want.skipped {
+          t.Errorf("frame %d: skipped %d, want %d", i, got[i].Skipped, want.skipped)
+        }
+      }
+
+      if r.Pending() != 0 {
+        t.Errorf("%d bytes still pending", r.Pending())
+      }
+    })
+  }
+}
+
+/* Link that plays back reads and then fails */
+
+type scriptedLink struct {
+  reads [][]byte                     // Bytes of each read, nil for a timeout
+  err error                          // Error once the reads run out
+}
+
+func (l *scriptedLink) Read( b []byte ) (int, error) {
+  if len(l.reads) == 0 {
+    return 0, l.err
+  }
+
+  n := copy( b, l.reads[0] )
+
+  l.reads = l.reads[1:]
+
+  if n == 0 {
+    return 0, io.EOF
+  }
+
+  return n, nil
+}
+
+/*
+    Procedure Name : TestReceiverRun
+
+    Description    : Checks that Run carries on through read timeouts,
+                     reports the frame left part way in, and returns the
+                     error that closes the link.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestReceiverRun( t *testing.T ) {
+  version := Encode( Frame{ Selector: HW_VERSION, Subselector: 0x00, Data: []byte{ 0x07 } } )
+
+  link := &scriptedLink{ reads: [][]byte{ version[:3], nil, version[3:], nil, version[:2] }, err: io.ErrClosedPipe }
+
+  var r Receiver
+  var pending []int
+
+  frames := make(chan Received, 4)
+
+  err := r.Run( link, frames, func( n int ) { pending = append(pending, n) }, nil )
+
+  if !errors.Is( err, io.ErrClosedPipe ) {
+    t.Errorf("Run returned %v, want %v", err, io.ErrClosedPipe)
+  }
+
+  if len(frames) != 1 {
+    t.Fatalf("got %d frames, want 1", len(frames))
+  }
+
+  if rx := <-frames; rx.Err != nil || rx.Frame.Selector != HW_VERSION {
+    t.Errorf("got %+v", rx)
+  }
+
+  want := []int{ 3, 3, 0, 0, 2, 2 }
+
+  if len(pending) != len(want) {
+    t.Fatalf("pending %v, want %v", pending, want)
+  }
+
+  for i := 0;i < len(want);i++ {
+    if pending[i] != want[i] {
+      t.Errorf("pending %v, want %v", pending, want)
+
+      break
+    }
+  }
+}
+
+/*
+    Procedure Name : TestReceiverRunFull
+
+    Description    : Checks that Run drops the frames that arrive while
+                     nobody takes them off a full channel, instead of
+                     blocking, and reports each one dropped.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestReceiverRunFull( t *testing.T ) {
+  version := Encode( Frame{ Selector: HW_VERSION, Subselector: 0x00, Data: []byte{ 0x07 } } )
+  voltages := Encode( Frame{ Selector: MON_VOLTAGES, Subselector: 0x01 } )
+
+  link := &scriptedLink{ reads: [][]byte{ join( version, voltages, voltages ) }, err: io.ErrClosedPipe }
+
+  var r Receiver
+  var dropped []Received
+
+  frames := make(chan Received, 1)
+
+  err := r.Run( link, frames, nil, func( rx Received ) { dropped = append(dropped, rx) } )
+
+  if !errors.Is( err, io.ErrClosedPipe ) {
+    t.Errorf("Run returned %v, want %v", err, io.ErrClosedPipe)
+  }
+
+  if rx := <-frames; rx.Frame.Selector != HW_VERSION {
+    t.Errorf("got %+v, want the first frame", rx)
+  }
+
+  if len(dropped) != 2 || dropped[0].Frame.Selector != MON_VOLTAGES {
+    t.Errorf("dropped %+v, want the two later frames", dropped)
+  }
+}
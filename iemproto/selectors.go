@@ -0,0 +1,117 @@
+package iemproto
+
+/* Information Selector Constants */
+
+const SW_VERSION_RESPONSE_LEN = 22
+const SW_VERSION              = 0x01
+  /* Subselector constants */
+
+  const COMM_PROC_01        = 0x01
+  const IO_PROCESSOR_01     = 0x02
+  const ADCM_01             = 0x03
+  const ABCM_PROC_A_01      = 0x04
+  const ABCM_PROC_B_01      = 0x05
+
+const HW_VERSION_RESPONSE_LEN = 7
+const HW_VERSION              = 0x02
+  /* Subselector constants */
+
+  const COMM_PROC_02        = 0x01
+  const IO_PROCESSOR_02     = 0x02
+  const ADCM_02             = 0x03
+  const ABCM_02             = 0x04
+
+const MON_VOLTAGES_RESPONSE_LEN = 16
+const MON_VOLTAGES            = 0x03
+  /* Subselector constants */
+  
+  const IEM_CPU_BOARD_03      = 0x01
+  const ADCM_03               = 0x03
+  const ABCM_03               = 0x04
+
+const RESET_COUNTER_RESPONSE_LEN = 8
+const RESET_COUNTER           = 0x10
+  /* Subselector constants */
+
+  const COMM_PROC_10          = 0x01
+  const ADCM_10               = 0x03
+  const ABCM_10               = 0x04
+
+const STATUS_VECTOR_RESPONSE_LEN = 7
+const STATUS_VECTOR           = 0x12
+  /* Subselector constants */
+
+  const COMM_PROC_AND_IO_PROC_12     = 0x01
+
+    /* Status Bits */
+
+    const FLASH_TEST_PASSED_01    = 0x01
+    const CP_TO_SP_LINK_ACTIVE_01 = 0x02
+     
+  const ADCM_12                      = 0x03
+    /* Status Bits */
+
+    const FLASH_TEST_PASSED_03     = 0x01
+
+  const ABCM_12                      = 0x04
+    /* Status Bits */
+
+    const FLASH_TEST_PASSED_04     = 0x01
+    const DETECTED_74V             = 0x02
+    const ABCM_A_MAG_VALVE_DRIVE   = 0x04
+    const ABCM_B_MAG_VALVE_DRIVE   = 0x08
+    const A_TO_B_COMM_LINK_ACTIVE  = 0x10
+  
+const DIGITAL_INPUTS_RESPONSE_LEN = 13
+const DIGITAL_INPUTS          = 0x31
+
+const ANALOG_INPUTS_RESPONSE_LEN = 22
+const ANALOG_INPUTS           = 0x32
+  /* Subselector constants */
+
+  const ANALOG_16V_INPUTS_32         = 0x01
+  const ANALOG_10V_INPUTS_32         = 0x02
+  const ANALOG_80V_INPUTS_32         = 0x03
+
+const PRESSURE_INPUTS_RESPONSE_LEN = 22
+const PRESSURE_INPUTS         = 0x33
+
+const CUR_4_20MA_INPUTS_RESPONSE_LEN = 22
+const CUR_4_20MA_INPUTS       = 0x34
+
+const SPEED_SENSOR_INPUTS_RESPONSE_LEN = 8
+const SPEED_SENSOR_INPUTS     = 0x35
+
+const NETWORK_INTERFACE_PARAMS_RESPONSE_LEN = 18
+const NETWORK_INTERFACE_PARAMS = 0x40
+
+const AMBIENT_LIGHT_INT_RESPONSE_LEN = 8
+const AMBIENT_LIGHT_INT       = 0x60
+
+const REST_CMD                               = 0x11
+  /* Component selector */
+
+  const COMM_PORC_11                         = 0x01
+  const ADCM_11                              = 0x03
+  const ABCM_11                              = 0x04
+
+const SET_SPEED_SENSOR_REF_CMD               = 0x36
+  /* Speed Sensaor Reference */
+
+  const ZERO_CROSSING_2_5V                   = 0x00
+  const ZERO_CROSSING_0V                     = 0x01
+
+const SET_ADCM_LED_STATE_CMD                 = 0x61
+  /* LED Mask */
+
+  const LED_AT_12                            = 0x01
+  const LED_AT_3                             = 0x02
+  const LED_AT_6                             = 0x04
+  const LED_AT_9                             = 0x08
+
+const SET_ADCM_SONALERT_STATE_CMD            = 0x62
+const SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD     = 0x92
+  /* ABCM Processor Selector */
+
+  const ABCM_PROC_A_92                       = 0x04
+  const ABCM_PROC_B_92                       = 0x05
@@ -0,0 +1,48 @@
+package iemsim
+
+import (
+        "fmt"
+        "os"
+        "syscall"
+        "unsafe"
+)
+
+/*
+    Procedure Name : OpenPty
+
+    Description    : Opens a pseudo terminal so the simulator can stand
+                     in for a serial port. The tester opens the returned
+                     device name as if it were the IEM serial port.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Master side of the pseudo terminal
+                     Name of the slave device, e.g. /dev/pts/3
+                     Any error opening the pseudo terminal
+*/
+
+func OpenPty() (*os.File, string, error) {
+  master, err := os.OpenFile( "/dev/ptmx", os.O_RDWR, 0 )
+
+  if err != nil {
+    return nil, "", err
+  }
+
+  var unlock int32
+
+  if _, _, errno := syscall.Syscall( syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock)) ); errno != 0 {
+    master.Close()
+
+    return nil, "", errno
+  }
+
+  var number uint32
+
+  if _, _, errno := syscall.Syscall( syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number)) ); errno != 0 {
+    master.Close()
+
+    return nil, "", errno
+  }
+
+  return master, fmt.Sprintf("/dev/pts/%d", number), nil
+}
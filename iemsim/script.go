@@ -0,0 +1,135 @@
+package iemsim
+
+import (
+        "encoding/json"
+        "fmt"
+        "os"
+)
+
+/*
+   A script is a JSON file that overrides the default responses and
+   queues faults, for example
+
+     {
+       "responses": [
+         { "selector": 3, "subselector": 1, "words": [ 11000, 3300, 5000, 0, 0 ] },
+         { "selector": 1, "subselector": 1, "string": "CP 2.10" }
+       ],
+       "faults": [
+         { "selector": 52, "subselector": 0, "fault": "bad-crc", "count": 1 }
+       ]
+     }
+*/
+
+/* Script Response Entry */
+
+type ScriptResponse struct {
+  Selector byte `json:"selector"`          // Information selector
+  Subselector byte `json:"subselector"`    // Information subselector
+  Bytes []int `json:"bytes,omitempty"`     // Raw data bytes
+  Words []uint16 `json:"words,omitempty"`  // 16 bit values, high byte first
+  String string `json:"string,omitempty"`  // Zero padded 16 character string
+}
+
+/* Script Fault Entry */
+
+type ScriptFault struct {
+  Selector byte `json:"selector"`          // Information selector
+  Subselector byte `json:"subselector"`    // Information subselector
+  Fault string `json:"fault"`              // none, no-response, bad-crc, truncate or noise
+  Count int `json:"count"`                 // Responses to affect, -1 for all
+}
+
+/* Script */
+
+type Script struct {
+  Responses []ScriptResponse `json:"responses"`
+  Faults []ScriptFault `json:"faults"`
+}
+
+// Fault names used in scripts
+
+var faultNames = map[string]Fault{
+  "none":        FaultNone,
+  "no-response": FaultNoResponse,
+  "bad-crc":     FaultBadCRC,
+  "truncate":    FaultTruncate,
+  "noise":       FaultNoise,
+}
+
+/*
+    Procedure Name : LoadScript
+
+    Description    : Reads a simulator script from a JSON file.
+
+    Arguments      : path - Script file name
+
+    Return Value   : The script
+                     Any error reading or parsing the file
+*/
+
+func LoadScript( path string ) (Script, error) {
+  var script Script
+
+  contents, err := os.ReadFile( path )
+
+  if err != nil {
+    return script, err
+  }
+
+  if err = json.Unmarshal( contents, &script ); err != nil {
+    return script, fmt.Errorf("Bad simulator script %s: %v", path, err)
+  }
+
+  return script, nil
+}
+
+/*
+    Procedure Name : Apply
+
+    Description    : Loads the responses and faults from a script into
+                     the simulator.
+
+    Arguments      : script - Script to apply
+
+    Return Value   : An error if the script names an unknown fault
+*/
+
+func (s *Simulator) Apply( script Script ) error {
+  for _, r := range script.Responses {
+    switch {
+      case r.String != "" :
+        s.SetString( r.Selector, r.Subselector, r.String, 16 )
+
+      case len(r.Words) > 0 :
+        s.SetWords( r.Selector, r.Subselector, r.Words... )
+
+      default :
+        data := make([]byte, len(r.Bytes))
+
+        for i, b := range r.Bytes {
+          data[i] = (byte) (b)
+        }
+
+        s.Set( r.Selector, r.Subselector, data )
+    }
+  }
+
+  for _, f := range script.Faults {
+    fault, ok := faultNames[f.Fault]
+
+    if !ok {
+      return fmt.Errorf("Unknown simulator fault %q", f.Fault)
+    }
+
+    count := f.Count
+
+    if count == 0 {
+      count = 1
+    }
+
+    s.InjectFault( f.Selector, f.Subselector, fault, count )
+  }
+
+  return nil
+}
@@ -0,0 +1,498 @@
+/*
+   Package iemsim simulates an IEM on the far end of the serial link. It
+   answers every information selector the tester uses and accepts the
+   set-state commands, so the test sequences can be run without a unit
+   on the bench.
+
+   Response values can be set from Go or loaded from a JSON script, and
+   faults can be injected into selected responses to exercise the error
+   handling in the tester.
+*/
+
+package iemsim
+
+import (
+        "errors"
+        "io"
+        "sync"
+
+        "github.com/questrail/IEMTestDB/iemproto"
+)
+
+/* Response Key */
+
+type Key struct {
+  Selector byte                      // Information selector
+  Subselector byte                   // Information subselector
+}
+
+/* Fault Types */
+
+type Fault int
+
+const (
+  FaultNone Fault = iota             // Answer normally
+  FaultNoResponse                    // Do not answer at all
+  FaultBadCRC                        // Answer with a corrupted CRC
+  FaultTruncate                      // Answer without the end byte
+  FaultNoise                         // Send power-on style text before the answer
+)
+
+/* Pending Fault */
+
+type pendingFault struct {
+  fault Fault                        // Fault to apply
+  count int                          // Number of responses left to affect, -1 for all
+}
+
+/* Simulator */
+
+type Simulator struct {
+  mu sync.Mutex                      // Protects everything below
+
+  responses map[Key][]byte           // Response data for each selector/subselector
+  handlers map[Key]func() []byte     // Optional computed responses
+  faults map[Key]*pendingFault       // Faults waiting to be applied
+
+  SpeedSensorRef byte                // Last speed sensor reference set
+  LEDMask byte                       // Last ADCM LED mask set
+  LEDBrightness byte                 // Last ADCM LED brightness set
+  SonalertEnable byte                // Last ADCM sonalert enable set
+  SonalertVolume byte                // Last ADCM sonalert volume set
+  Commands []iemproto.Frame          // Every set-state command received
+}
+
+/*
+    Procedure Name : New
+
+    Description    : Creates a simulator that answers with values from
+                     a healthy unit.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The simulator
+*/
+
+func New() *Simulator {
+  s := &Simulator{
+    responses: make(map[Key][]byte),
+    handlers: make(map[Key]func() []byte),
+    faults: make(map[Key]*pendingFault),
+  }
+
+  s.setDefaults()
+
+  return s
+}
+
+/*
+    Procedure Name : setDefaults
+
+    Description    : Loads the response values of a healthy unit.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) setDefaults() {
+  for _, sub := range []byte{ iemproto.COMM_PROC_01, iemproto.IO_PROCESSOR_01, iemproto.ADCM_01,
+                              iemproto.ABCM_PROC_A_01, iemproto.ABCM_PROC_B_01 } {
+    s.SetString( iemproto.SW_VERSION, sub, "SIM 1.00", 16 )
+  }
+
+  for _, sub := range []byte{ iemproto.COMM_PROC_02, iemproto.IO_PROCESSOR_02, iemproto.ADCM_02, iemproto.ABCM_02 } {
+    s.Set( iemproto.HW_VERSION, sub, []byte{ 1 } )
+  }
+
+  s.SetWords( iemproto.MON_VOLTAGES, iemproto.IEM_CPU_BOARD_03, 12000, 3300, 5000, 0, 0 )
+  s.SetWords( iemproto.MON_VOLTAGES, iemproto.ADCM_03, 12000, 4000, 0, 0, 0 )
+  s.SetWords( iemproto.MON_VOLTAGES, iemproto.ABCM_03, 12000, 3300, 0, 0, 0 )
+
+  for _, sub := range []byte{ iemproto.COMM_PROC_10, iemproto.ADCM_10, iemproto.ABCM_10 } {
+    s.SetWords( iemproto.RESET_COUNTER, sub, 0 )
+  }
+
+  s.Set( iemproto.STATUS_VECTOR, iemproto.COMM_PROC_AND_IO_PROC_12,
+         []byte{ iemproto.FLASH_TEST_PASSED_01 | iemproto.CP_TO_SP_LINK_ACTIVE_01 } )
+  s.Set( iemproto.STATUS_VECTOR, iemproto.ADCM_12, []byte{ iemproto.FLASH_TEST_PASSED_03 } )
+  s.Set( iemproto.STATUS_VECTOR, iemproto.ABCM_12,
+         []byte{ iemproto.FLASH_TEST_PASSED_04 | iemproto.DETECTED_74V | iemproto.A_TO_B_COMM_LINK_ACTIVE } )
+
+  s.Set( iemproto.DIGITAL_INPUTS, 0, []byte{ 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00 } )
+
+  for _, sub := range []byte{ iemproto.ANALOG_16V_INPUTS_32, iemproto.ANALOG_10V_INPUTS_32, iemproto.ANALOG_80V_INPUTS_32 } {
+    s.SetWords( iemproto.ANALOG_INPUTS, sub, 0, 0, 0, 0, 0, 0, 0, 0 )
+  }
+
+  s.SetWords( iemproto.PRESSURE_INPUTS, 0, 0, 0, 0, 0, 0, 0, 0, 0 )
+  s.SetWords( iemproto.CUR_4_20MA_INPUTS, 0, 2000, 2000, 2000, 2000, 2000, 2000, 2000, 3600 )
+  s.SetWords( iemproto.SPEED_SENSOR_INPUTS, 0, 100 )
+  s.Set( iemproto.NETWORK_INTERFACE_PARAMS, 0, []byte{ 127, 0, 0, 1, 255, 0, 0, 0, 127, 0, 0, 1 } )
+  s.SetWords( iemproto.AMBIENT_LIGHT_INT, 0, 500 )
+}
+
+/*
+    Procedure Name : Set
+
+    Description    : Sets the data bytes returned for a selector and
+                     subselector.
+
+    Arguments      : selector    - Information selector
+                     subselector - Information subselector
+                     data        - Response data bytes
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) Set( selector byte, subselector byte, data []byte ) {
+  s.mu.Lock()
+
+  defer s.mu.Unlock()
+
+  s.responses[Key{ selector, subselector }] = append([]byte(nil), data...)
+}
+
+/*
+    Procedure Name : SetWords
+
+    Description    : Sets the response for a selector and subselector
+                     from 16 bit values sent high byte first.
+
+    Arguments      : selector    - Information selector
+                     subselector - Information subselector
+                     words       - Response values
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) SetWords( selector byte, subselector byte, words ...uint16 ) {
+  data := make([]byte, 0, 2*len(words))
+
+  for _, w := range words {
+    data = append(data, (byte) (w >> 8), (byte) (w & 0xff))
+  }
+
+  s.Set( selector, subselector, data )
+}
+
+/*
+    Procedure Name : SetString
+
+    Description    : Sets a zero padded string response such as a
+                     software version.
+
+    Arguments      : selector    - Information selector
+                     subselector - Information subselector
+                     text        - String to return
+                     size        - Size of the string field
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) SetString( selector byte, subselector byte, text string, size int ) {
+  data := make([]byte, size)
+
+  copy( data, text )
+
+  s.Set( selector, subselector, data )
+}
+
+/*
+    Procedure Name : Handle
+
+    Description    : Installs a routine that computes the response data
+                     each time a selector and subselector is requested.
+                     This takes priority over values set with Set.
+
+    Arguments      : selector    - Information selector
+                     subselector - Information subselector
+                     handler     - Routine returning the response data
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) Handle( selector byte, subselector byte, handler func() []byte ) {
+  s.mu.Lock()
+
+  defer s.mu.Unlock()
+
+  s.handlers[Key{ selector, subselector }] = handler
+}
+
+/*
+    Procedure Name : InjectFault
+
+    Description    : Applies a fault to the next responses for a selector
+                     and subselector.
+
+    Arguments      : selector    - Information selector
+                     subselector - Information subselector
+                     fault       - Fault to apply
+                     count       - Number of responses to affect, -1 for all
+
+    Return Value   : This routine has no return value.
+*/
+
+func (s *Simulator) InjectFault( selector byte, subselector byte, fault Fault, count int ) {
+  s.mu.Lock()
+
+  defer s.mu.Unlock()
+
+  if fault == FaultNone || count == 0 {
+    delete( s.faults, Key{ selector, subselector } )
+  } else {
+    s.faults[Key{ selector, subselector }] = &pendingFault{ fault: fault, count: count }
+  }
+}
+
+/*
+    Procedure Name : Serve
+
+    Description    : Answers requests arriving on the link until the link
+                     is closed. A read that returns io.EOF is treated as
+                     a read timeout and the simulator keeps waiting.
+
+    Arguments      : link - Link to the tester
+
+    Return Value   : The error that ended the link
+*/
+
+func (s *Simulator) Serve( link io.ReadWriter ) error {
+  var pending []byte
+
+  buf := make([]byte, 256)
+
+  for {
+    n, err := link.Read( buf )
+
+    pending = append(pending, buf[:n]...)
+
+    if err != nil && !errors.Is( err, io.EOF ) {
+      return err
+    }
+
+    /*
+        Pull every complete frame out of the input. Anything in front
+        of a start byte is noise and is thrown away.
+    */
+
+    for {
+      start := -1
+      end := -1
+
+      for i := 0;i < len(pending);i++ {
+        if start < 0 && pending[i] == iemproto.StartByte {
+          start = i
+        } else if start >= 0 && pending[i] == iemproto.EndByte {
+          end = i
+
+          break
+        }
+      }
+
+      if start < 0 {
+        pending = pending[:0]
+
+        break
+      }
+
+      if end < 0 {
+        pending = pending[start:]
+
+        break
+      }
+
+      frame, err := iemproto.Decode( pending[start:end + 1] )
+
+      pending = pending[end + 1:]
+
+      if err == nil {
+        reply := s.Answer( frame )
+
+        if len(reply) > 0 {
+          if _, err := link.Write( reply ); err != nil {
+            return err
+          }
+        }
+      }
+    }
+  }
+}
+
+/*
+    Procedure Name : Answer
+
+    Description    : Builds the wire response for one request frame and
+                     applies any set-state command it carries.
+
+    Arguments      : request - Decoded request from the tester
+
+    Return Value   : Bytes to send back, empty if there is no answer
+*/
+
+func (s *Simulator) Answer( request iemproto.Frame ) []byte {
+  s.mu.Lock()
+
+  defer s.mu.Unlock()
+
+  key := Key{ request.Selector, request.Subselector }
+
+  /*
+      A set-state command with a no-response fault is lost on the way:
+      the unit never takes it.
+  */
+
+  if pending, ok := s.faults[key]; ok && pending.fault == FaultNoResponse && isCommand( request.Selector ) {
+    s.applyFault( key, nil )
+
+    return nil
+  }
+
+  if s.command( request ) {
+    return nil
+  }
+
+  var data []byte
+
+  if handler, ok := s.handlers[key]; ok {
+    data = handler()
+  } else if value, ok := s.responses[key]; ok {
+    data = value
+  } else {
+    return nil
+  }
+
+  reply := iemproto.Encode( iemproto.Frame{ Selector: request.Selector, Subselector: request.Subselector, Data: data } )
+
+  return s.applyFault( key, reply )
+}
+
+/*
+    Procedure Name : isCommand
+
+    Description    : Tells whether a selector is a set-state command.
+
+    Arguments      : selector - Selector of a request
+
+    Return Value   : true for a set-state command
+*/
+
+func isCommand( selector byte ) bool {
+  switch selector {
+    case iemproto.REST_CMD, iemproto.SET_SPEED_SENSOR_REF_CMD, iemproto.SET_ADCM_LED_STATE_CMD,
+         iemproto.SET_ADCM_SONALERT_STATE_CMD, iemproto.SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD :
+      return true
+  }
+
+  return false
+}
+
+/*
+    Procedure Name : command
+
+    Description    : Applies a set-state command to the simulated unit.
+                     The caller must hold the simulator lock.
+
+    Arguments      : request - Decoded request from the tester
+
+    Return Value   : true if the request was a set-state command
+*/
+
+func (s *Simulator) command( request iemproto.Frame ) bool {
+  switch request.Selector {
+    case iemproto.REST_CMD :
+      s.responses[Key{ iemproto.RESET_COUNTER, request.Subselector }] = []byte{ 0, 0 }
+
+    case iemproto.SET_SPEED_SENSOR_REF_CMD :
+      if len(request.Data) > 0 {
+        s.SpeedSensorRef = request.Data[0]
+      }
+
+    case iemproto.SET_ADCM_LED_STATE_CMD :
+      if len(request.Data) > 1 {
+        s.LEDMask = request.Data[0]
+        s.LEDBrightness = request.Data[1]
+      }
+
+    case iemproto.SET_ADCM_SONALERT_STATE_CMD :
+      if len(request.Data) > 1 {
+        s.SonalertEnable = request.Data[0]
+        s.SonalertVolume = request.Data[1]
+      }
+
+    case iemproto.SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD :
+      if len(request.Data) > 0 {
+        key := Key{ iemproto.STATUS_VECTOR, iemproto.ABCM_12 }
+        status := []byte{ 0 }
+        bit := (byte) (iemproto.ABCM_A_MAG_VALVE_DRIVE)
+
+        if len(s.responses[key]) > 0 {
+          status[0] = s.responses[key][0]
+        }
+
+        if request.Subselector == iemproto.ABCM_PROC_B_92 {
+          bit = iemproto.ABCM_B_MAG_VALVE_DRIVE
+        }
+
+        if request.Data[0] != 0 {
+          status[0] |= bit
+        } else {
+          status[0] &^= bit
+        }
+
+        s.responses[key] = status
+      }
+
+    default :
+      return false
+  }
+
+  s.Commands = append(s.Commands, request)
+
+  return true
+}
+
+/*
+    Procedure Name : applyFault
+
+    Description    : Corrupts a response if a fault is pending for it.
+                     The caller must hold the simulator lock.
+
+    Arguments      : key   - Selector and subselector of the response
+                     reply - Encoded response
+
+    Return Value   : The response to send, empty for no response
+*/
+
+func (s *Simulator) applyFault( key Key, reply []byte ) []byte {
+  pending, ok := s.faults[key]
+
+  if !ok {
+    return reply
+  }
+
+  if pending.count > 0 {
+    pending.count -= 1
+
+    if pending.count == 0 {
+      delete( s.faults, key )
+    }
+  }
+
+  switch pending.fault {
+    case FaultNoResponse :
+      return nil
+
+    case FaultBadCRC :
+      reply[len(reply) - 2] ^= 0x01
+
+    case FaultTruncate :
+      reply = reply[:len(reply) - 1]
+
+    case FaultNoise :
+      reply = append([]byte("IEM POST OK\r\n"), reply...)
+  }
+
+  return reply
+}
@@ -2,419 +2,170 @@ package main
 
 import (
 	"github.com/leesper/couchdb-golang"
-        "github.com/luismesas/goPi/MCP23S17"
-        "github.com/luismesas/goPi/spi"
-        "github.com/tarm/serial"
+        "github.com/questrail/IEMTestDB/iemproto"
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/iemclient"
+        "github.com/questrail/IEMTestDB/iemtrace"
+        "github.com/questrail/IEMTestDB/replay"
+        "github.com/questrail/IEMTestDB/results"
+        "github.com/questrail/IEMTestDB/transport"
+        "context"
         "errors"
         "time"
         "bufio"
+        "flag"
+        "io"
 	"fmt"
         "os"
-        "os/exec"
-        "strings"
         "log"
 	"syscall"
-        s "strings"
 )
 
 // Message Constants
 
 const IEM_WITH_ALERTER      = 1
 const IEM_WITHOUT_ALERTER   = 0
+const IEM_ADCM_ONLY         = 2
 
 /* Information Selector Constants */
 
-const SW_VERSION_RESPONSE_LEN = 22
-const SW_VERSION int          = 0x01
+const SW_VERSION_RESPONSE_LEN = iemproto.SW_VERSION_RESPONSE_LEN
+const SW_VERSION int          = iemproto.SW_VERSION
   /* Subselector constants */
 
-  const COMM_PROC_01 int    = 0x01
-  const IO_PROCESSOR_01 int = 0x02
-  const ADCM_01 int         = 0x03
-  const ABCM_PROC_A_01 int  = 0x04
-  const ABCM_PROC_B_01 int  = 0x05
+  const COMM_PROC_01 int    = iemproto.COMM_PROC_01
+  const IO_PROCESSOR_01 int = iemproto.IO_PROCESSOR_01
+  const ADCM_01 int         = iemproto.ADCM_01
+  const ABCM_PROC_A_01 int  = iemproto.ABCM_PROC_A_01
+  const ABCM_PROC_B_01 int  = iemproto.ABCM_PROC_B_01
 
-const HW_VERSION_RESPONSE_LEN = 7
-const HW_VERSION int          = 0x02
+const HW_VERSION_RESPONSE_LEN = iemproto.HW_VERSION_RESPONSE_LEN
+const HW_VERSION int          = iemproto.HW_VERSION
   /* Subselector constants */
 
-  const COMM_PROC_02 int    = 0x01
-  const IO_PROCESSOR_02 int = 0x02
-  const ADCM_02 int         = 0x03
-  const ABCM_02 int         = 0x04
+  const COMM_PROC_02 int    = iemproto.COMM_PROC_02
+  const IO_PROCESSOR_02 int = iemproto.IO_PROCESSOR_02
+  const ADCM_02 int         = iemproto.ADCM_02
+  const ABCM_02 int         = iemproto.ABCM_02
 
-const MON_VOLTAGES_RESPONSE_LEN = 16
-const MON_VOLTAGES int        = 0x03
+const MON_VOLTAGES_RESPONSE_LEN = iemproto.MON_VOLTAGES_RESPONSE_LEN
+const MON_VOLTAGES int        = iemproto.MON_VOLTAGES
   /* Subselector constants */
   
-  const IEM_CPU_BOARD_03 int  = 0x01
-  const ADCM_03 int           = 0x03
-  const ABCM_03 int           = 0x04
+  const IEM_CPU_BOARD_03 int  = iemproto.IEM_CPU_BOARD_03
+  const ADCM_03 int           = iemproto.ADCM_03
+  const ABCM_03 int           = iemproto.ABCM_03
 
-const RESET_COUNTER_RESPONSE_LEN = 8
-const RESET_COUNTER int       = 0x10
+const RESET_COUNTER_RESPONSE_LEN = iemproto.RESET_COUNTER_RESPONSE_LEN
+const RESET_COUNTER int       = iemproto.RESET_COUNTER
   /* Subselector constants */
 
-  const COMM_PROC_10 int      = 0x01
-  const ADCM_10 int           = 0x03
-  const ABCM_10 int           = 0x04
+  const COMM_PROC_10 int      = iemproto.COMM_PROC_10
+  const ADCM_10 int           = iemproto.ADCM_10
+  const ABCM_10 int           = iemproto.ABCM_10
 
-const STATUS_VECTOR_RESPONSE_LEN = 7
-const STATUS_VECTOR int       = 0x12
+const STATUS_VECTOR_RESPONSE_LEN = iemproto.STATUS_VECTOR_RESPONSE_LEN
+const STATUS_VECTOR int       = iemproto.STATUS_VECTOR
   /* Subselector constants */
 
-  const COMM_PROC_AND_IO_PROC_12 int = 0x01
+  const COMM_PROC_AND_IO_PROC_12 int = iemproto.COMM_PROC_AND_IO_PROC_12
 
     /* Status Bits */
 
-    const FLASH_TEST_PASSED_01    = 0x01
-    const CP_TO_SP_LINK_ACTIVE_01 = 0x02
+    const FLASH_TEST_PASSED_01    = iemproto.FLASH_TEST_PASSED_01
+    const CP_TO_SP_LINK_ACTIVE_01 = iemproto.CP_TO_SP_LINK_ACTIVE_01
      
-  const ADCM_12 int                  = 0x03
+  const ADCM_12 int                  = iemproto.ADCM_12
     /* Status Bits */
 
-    const FLASH_TEST_PASSED_03     = 0x01
+    const FLASH_TEST_PASSED_03     = iemproto.FLASH_TEST_PASSED_03
 
-  const ABCM_12 int                  = 0x04
+  const ABCM_12 int                  = iemproto.ABCM_12
     /* Status Bits */
 
-    const FLASH_TEST_PASSED_04     = 0x01
-    const DETECTED_74V             = 0x02
-    const ABCM_A_MAG_VALVE_DRIVE   = 0x04
-    const ABCM_B_MAG_VALVE_DRIVE   = 0x08
-    const A_TO_B_COMM_LINK_ACTIVE  = 0x10
+    const FLASH_TEST_PASSED_04     = iemproto.FLASH_TEST_PASSED_04
+    const DETECTED_74V             = iemproto.DETECTED_74V
+    const ABCM_A_MAG_VALVE_DRIVE   = iemproto.ABCM_A_MAG_VALVE_DRIVE
+    const ABCM_B_MAG_VALVE_DRIVE   = iemproto.ABCM_B_MAG_VALVE_DRIVE
+    const A_TO_B_COMM_LINK_ACTIVE  = iemproto.A_TO_B_COMM_LINK_ACTIVE
   
-const DIGITAL_INPUTS_RESPONSE_LEN = 13
-const DIGITAL_INPUTS int      = 0x31
+const DIGITAL_INPUTS_RESPONSE_LEN = iemproto.DIGITAL_INPUTS_RESPONSE_LEN
+const DIGITAL_INPUTS int      = iemproto.DIGITAL_INPUTS
 
-const ANALOG_INPUTS_RESPONSE_LEN = 22
-const ANALOG_INPUTS int       = 0x32
+const ANALOG_INPUTS_RESPONSE_LEN = iemproto.ANALOG_INPUTS_RESPONSE_LEN
+const ANALOG_INPUTS int       = iemproto.ANALOG_INPUTS
   /* Subselector constants */
 
-  const ANALOG_16V_INPUTS_32 int     = 0x01
-  const ANALOG_10V_INPUTS_32 int     = 0x02
-  const ANALOG_80V_INPUTS_32 int     = 0x03
+  const ANALOG_16V_INPUTS_32 int     = iemproto.ANALOG_16V_INPUTS_32
+  const ANALOG_10V_INPUTS_32 int     = iemproto.ANALOG_10V_INPUTS_32
+  const ANALOG_80V_INPUTS_32 int     = iemproto.ANALOG_80V_INPUTS_32
 
-const PRESSURE_INPUTS_RESPONSE_LEN = 22
-const PRESSURE_INPUTS int     = 0x33
+const PRESSURE_INPUTS_RESPONSE_LEN = iemproto.PRESSURE_INPUTS_RESPONSE_LEN
+const PRESSURE_INPUTS int     = iemproto.PRESSURE_INPUTS
 
-const CUR_4_20MA_INPUTS_RESPONSE_LEN = 22
-const CUR_4_20MA_INPUTS       = 0x34
+const CUR_4_20MA_INPUTS_RESPONSE_LEN = iemproto.CUR_4_20MA_INPUTS_RESPONSE_LEN
+const CUR_4_20MA_INPUTS       = iemproto.CUR_4_20MA_INPUTS
 
-const SPEED_SENSOR_INPUTS_RESPONSE_LEN = 8
-const SPEED_SENSOR_INPUTS int = 0x35
+const SPEED_SENSOR_INPUTS_RESPONSE_LEN = iemproto.SPEED_SENSOR_INPUTS_RESPONSE_LEN
+const SPEED_SENSOR_INPUTS int = iemproto.SPEED_SENSOR_INPUTS
 
-const NETWORK_INTERFACE_PARAMS_RESPONSE_LEN = 18
-const NETWORK_INTERFACE_PARAMS = 0x40
+const NETWORK_INTERFACE_PARAMS_RESPONSE_LEN = iemproto.NETWORK_INTERFACE_PARAMS_RESPONSE_LEN
+const NETWORK_INTERFACE_PARAMS = iemproto.NETWORK_INTERFACE_PARAMS
 
-const AMBIENT_LIGHT_INT_RESPONSE_LEN = 8
-const AMBIENT_LIGHT_INT   int = 0x60
+const AMBIENT_LIGHT_INT_RESPONSE_LEN = iemproto.AMBIENT_LIGHT_INT_RESPONSE_LEN
+const AMBIENT_LIGHT_INT   int = iemproto.AMBIENT_LIGHT_INT
 
-const REST_CMD int                           = 0x11
+const REST_CMD int                           = iemproto.REST_CMD
   /* Component selector */
 
-  const COMM_PORC_11 int                     = 0x01
-  const ADCM_11 int                          = 0x03
-  const ABCM_11 int                          = 0x04
+  const COMM_PORC_11 int                     = iemproto.COMM_PORC_11
+  const ADCM_11 int                          = iemproto.ADCM_11
+  const ABCM_11 int                          = iemproto.ABCM_11
 
-const SET_SPEED_SENSOR_REF_CMD int           = 0x36
+const SET_SPEED_SENSOR_REF_CMD int           = iemproto.SET_SPEED_SENSOR_REF_CMD
   /* Speed Sensaor Reference */
 
-  const ZERO_CROSSING_2_5V int               = 0x00
-  const ZERO_CROSSING_0V int                 = 0x01
+  const ZERO_CROSSING_2_5V int               = iemproto.ZERO_CROSSING_2_5V
+  const ZERO_CROSSING_0V int                 = iemproto.ZERO_CROSSING_0V
 
-const SET_ADCM_LED_STATE_CMD int             = 0x61
+const SET_ADCM_LED_STATE_CMD int             = iemproto.SET_ADCM_LED_STATE_CMD
   /* LED Mask */
 
-  const LED_AT_12 int                        = 0x01
-  const LED_AT_3 int                         = 0x02
-  const LED_AT_6 int                         = 0x04
-  const LED_AT_9 int                         = 0x08
+  const LED_AT_12 int                        = iemproto.LED_AT_12
+  const LED_AT_3 int                         = iemproto.LED_AT_3
+  const LED_AT_6 int                         = iemproto.LED_AT_6
+  const LED_AT_9 int                         = iemproto.LED_AT_9
 
-const SET_ADCM_SONALERT_STATE_CMD            = 0x62
-const SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD int = 0x92
+const SET_ADCM_SONALERT_STATE_CMD            = iemproto.SET_ADCM_SONALERT_STATE_CMD
+const SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD int = iemproto.SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD
   /* ABCM Processor Selector */
 
-  const ABCM_PROC_A_92 int                   = 0x04
-  const ABCM_PROC_B_92 int                   = 0x05
-
-// CRC16 constants
-
-const zero_bit  int  = 0x1021
-const one_bit   int  = 0x2042
-const two_bit   int  = 0x4084
-const three_bit int  = 0x8108
-const four_bit  int  = 0x1231
-const five_bit  int  = 0x2462
-const six_bit   int  = 0x48c4
-const seven_bit int  = 0x9188
-
-var crc_table = [256]int{                                                                             0x0000, /* 0x00 */
-                                                                                                    zero_bit, /* 0x01 */
-                                                                                          one_bit           , /* 0x02 */
-                                                                                          one_bit ^ zero_bit, /* 0x03 */
-                                                                                two_bit                     , /* 0x04 */
-                                                                                two_bit           ^ zero_bit, /* 0x05 */
-                                                                                two_bit ^ one_bit           , /* 0x06 */
-                                                                                two_bit ^ one_bit ^ zero_bit, /* 0x07 */
-                                                                    three_bit                               , /* 0x08 */
-                                                                    three_bit                     ^ zero_bit, /* 0x09 */
-                                                                    three_bit           ^ one_bit           , /* 0x0A */
-                                                                    three_bit           ^ one_bit ^ zero_bit, /* 0x0B */
-                                                                    three_bit ^ two_bit                     , /* 0x0C */
-                                                                    three_bit ^ two_bit           ^ zero_bit, /* 0x0D */
-                                                                    three_bit ^ two_bit ^ one_bit           , /* 0x0E */
-                                                                    three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x0F */
-                                                         four_bit                                           , /* 0x10 */
-                                                         four_bit ^                                 zero_bit, /* 0x11 */
-                                                         four_bit ^                       one_bit           , /* 0x12 */
-                                                         four_bit ^                       one_bit ^ zero_bit, /* 0x13 */
-                                                         four_bit ^             two_bit                     , /* 0x14 */
-                                                         four_bit ^             two_bit           ^ zero_bit, /* 0x15 */
-                                                         four_bit ^             two_bit ^ one_bit           , /* 0x16 */
-                                                         four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x17 */
-                                                         four_bit ^ three_bit                               , /* 0x18 */
-                                                         four_bit ^ three_bit                     ^ zero_bit, /* 0x19 */
-                                                         four_bit ^ three_bit           ^ one_bit           , /* 0x1A */
-                                                         four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x1B */
-                                                         four_bit ^ three_bit ^ two_bit                     , /* 0x1C */
-                                                         four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x1D */
-                                                         four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x1E */
-                                                         four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x1F */
-                                              five_bit                                                      , /* 0x20 */
-                                              five_bit ^                                            zero_bit, /* 0x21 */
-                                              five_bit ^                                  one_bit           , /* 0x22 */
-                                              five_bit ^                                  one_bit ^ zero_bit, /* 0x23 */
-                                              five_bit ^                        two_bit                     , /* 0x24 */
-                                              five_bit ^                        two_bit           ^ zero_bit, /* 0x25 */
-                                              five_bit ^                        two_bit ^ one_bit           , /* 0x26 */
-                                              five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0x27 */
-                                              five_bit ^            three_bit                               , /* 0x28 */
-                                              five_bit ^            three_bit                     ^ zero_bit, /* 0x29 */
-                                              five_bit ^            three_bit           ^ one_bit           , /* 0x2A */
-                                              five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0x2B */
-                                              five_bit ^            three_bit ^ two_bit                     , /* 0x2C */
-                                              five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0x2D */
-                                              five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0x2E */
-                                              five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x2F */
-                                              five_bit ^ four_bit                                           , /* 0x30 */
-                                              five_bit ^ four_bit ^                                 zero_bit, /* 0x31 */
-                                              five_bit ^ four_bit ^                       one_bit           , /* 0x32 */
-                                              five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0x33 */
-                                              five_bit ^ four_bit ^             two_bit                     , /* 0x34 */
-                                              five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0x35 */
-                                              five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0x36 */
-                                              five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x37 */
-                                              five_bit ^ four_bit ^ three_bit                               , /* 0x38 */
-                                              five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0x39 */
-                                              five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0x3A */
-                                              five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x3B */
-                                              five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0x3C */
-                                              five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x3D */
-                                              five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x3E */
-                                              five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x3F */
-                                    six_bit                                                                 , /* 0x40 */
-                                    six_bit ^                                                       zero_bit, /* 0x41 */
-                                    six_bit ^                                             one_bit           , /* 0x42 */
-                                    six_bit ^                                             one_bit ^ zero_bit, /* 0x43 */
-                                    six_bit ^                                   two_bit                     , /* 0x44 */
-                                    six_bit ^                                   two_bit           ^ zero_bit, /* 0x45 */
-                                    six_bit ^                                   two_bit ^ one_bit           , /* 0x46 */
-                                    six_bit ^                                   two_bit ^ one_bit ^ zero_bit, /* 0x47 */
-                                    six_bit ^                       three_bit                               , /* 0x48 */
-                                    six_bit ^                       three_bit                     ^ zero_bit, /* 0x49 */
-                                    six_bit ^                       three_bit           ^ one_bit           , /* 0x4A */
-                                    six_bit ^                       three_bit           ^ one_bit ^ zero_bit, /* 0x4B */
-                                    six_bit ^                       three_bit ^ two_bit                     , /* 0x4C */
-                                    six_bit ^                       three_bit ^ two_bit           ^ zero_bit, /* 0x4D */
-                                    six_bit ^                       three_bit ^ two_bit ^ one_bit           , /* 0x4E */
-                                    six_bit ^                       three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x4F */
-                                    six_bit ^            four_bit                                           , /* 0x50 */
-                                    six_bit ^            four_bit ^                                 zero_bit, /* 0x51 */
-                                    six_bit ^            four_bit ^                       one_bit           , /* 0x52 */
-                                    six_bit ^            four_bit ^                       one_bit ^ zero_bit, /* 0x53 */
-                                    six_bit ^            four_bit ^             two_bit                     , /* 0x54 */
-                                    six_bit ^            four_bit ^             two_bit           ^ zero_bit, /* 0x55 */
-                                    six_bit ^            four_bit ^             two_bit ^ one_bit           , /* 0x56 */
-                                    six_bit ^            four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x57 */
-                                    six_bit ^            four_bit ^ three_bit                               , /* 0x58 */
-                                    six_bit ^            four_bit ^ three_bit                     ^ zero_bit, /* 0x59 */
-                                    six_bit ^            four_bit ^ three_bit           ^ one_bit           , /* 0x5A */
-                                    six_bit ^            four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x5B */
-                                    six_bit ^            four_bit ^ three_bit ^ two_bit                     , /* 0x5C */
-                                    six_bit ^            four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x5D */
-                                    six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x5E */
-                                    six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x5F */
-                                    six_bit ^ five_bit                                                      , /* 0x60 */
-                                    six_bit ^ five_bit ^                                            zero_bit, /* 0x61 */
-                                    six_bit ^ five_bit ^                                  one_bit           , /* 0x62 */
-                                    six_bit ^ five_bit ^                                  one_bit ^ zero_bit, /* 0x63 */
-                                    six_bit ^ five_bit ^                        two_bit                     , /* 0x64 */
-                                    six_bit ^ five_bit ^                        two_bit           ^ zero_bit, /* 0x65 */
-                                    six_bit ^ five_bit ^                        two_bit ^ one_bit           , /* 0x66 */
-                                    six_bit ^ five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0x67 */
-                                    six_bit ^ five_bit ^            three_bit                               , /* 0x68 */
-                                    six_bit ^ five_bit ^            three_bit                     ^ zero_bit, /* 0x69 */
-                                    six_bit ^ five_bit ^            three_bit           ^ one_bit           , /* 0x6A */
-                                    six_bit ^ five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0x6B */
-                                    six_bit ^ five_bit ^            three_bit ^ two_bit                     , /* 0x6C */
-                                    six_bit ^ five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0x6D */
-                                    six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0x6E */
-                                    six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x6F */
-                                    six_bit ^ five_bit ^ four_bit                                           , /* 0x70 */
-                                    six_bit ^ five_bit ^ four_bit ^                                 zero_bit, /* 0x71 */
-                                    six_bit ^ five_bit ^ four_bit ^                       one_bit           , /* 0x72 */
-                                    six_bit ^ five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0x73 */
-                                    six_bit ^ five_bit ^ four_bit ^             two_bit                     , /* 0x74 */
-                                    six_bit ^ five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0x75 */
-                                    six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0x76 */
-                                    six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x77 */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit                               , /* 0x78 */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0x79 */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0x7A */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x7B */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0x7C */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x7D */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x7E */
-                                    six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x7F */
-                        seven_bit                                                                           , /* 0x80 */
-                        seven_bit ^                                                                 zero_bit, /* 0x81 */
-                        seven_bit ^                                                       one_bit           , /* 0x82 */
-                        seven_bit ^                                                       one_bit ^ zero_bit, /* 0x83 */
-                        seven_bit ^                                             two_bit                     , /* 0x84 */
-                        seven_bit ^                                             two_bit           ^ zero_bit, /* 0x85 */
-                        seven_bit ^                                             two_bit ^ one_bit           , /* 0x86 */
-                        seven_bit ^                                             two_bit ^ one_bit ^ zero_bit, /* 0x87 */
-                        seven_bit ^                                 three_bit                               , /* 0x88 */
-                        seven_bit ^                                 three_bit                     ^ zero_bit, /* 0x89 */
-                        seven_bit ^                                 three_bit           ^ one_bit           , /* 0x8A */
-                        seven_bit ^                                 three_bit           ^ one_bit ^ zero_bit, /* 0x8B */
-                        seven_bit ^                                 three_bit ^ two_bit                     , /* 0x8C */
-                        seven_bit ^                                 three_bit ^ two_bit           ^ zero_bit, /* 0x8D */
-                        seven_bit ^                                 three_bit ^ two_bit ^ one_bit           , /* 0x8E */
-                        seven_bit ^                                 three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x8F */
-                        seven_bit ^                      four_bit                                           , /* 0x90 */
-                        seven_bit ^                      four_bit ^                                 zero_bit, /* 0x91 */
-                        seven_bit ^                      four_bit ^                       one_bit           , /* 0x92 */
-                        seven_bit ^                      four_bit ^                       one_bit ^ zero_bit, /* 0x93 */
-                        seven_bit ^                      four_bit ^             two_bit                     , /* 0x94 */
-                        seven_bit ^                      four_bit ^             two_bit           ^ zero_bit, /* 0x95 */
-                        seven_bit ^                      four_bit ^             two_bit ^ one_bit           , /* 0x96 */
-                        seven_bit ^                      four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0x97 */
-                        seven_bit ^                      four_bit ^ three_bit                               , /* 0x98 */
-                        seven_bit ^                      four_bit ^ three_bit                     ^ zero_bit, /* 0x99 */
-                        seven_bit ^                      four_bit ^ three_bit           ^ one_bit           , /* 0x9A */
-                        seven_bit ^                      four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0x9B */
-                        seven_bit ^                      four_bit ^ three_bit ^ two_bit                     , /* 0x9C */
-                        seven_bit ^                      four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0x9D */
-                        seven_bit ^                      four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0x9E */
-                        seven_bit ^                      four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0x9F */
-                        seven_bit ^           five_bit                                                      , /* 0xA0 */
-                        seven_bit ^           five_bit ^                                            zero_bit, /* 0xA1 */
-                        seven_bit ^           five_bit ^                                  one_bit           , /* 0xA2 */
-                        seven_bit ^           five_bit ^                                  one_bit ^ zero_bit, /* 0xA3 */
-                        seven_bit ^           five_bit ^                        two_bit                     , /* 0xA4 */
-                        seven_bit ^           five_bit ^                        two_bit           ^ zero_bit, /* 0xA5 */
-                        seven_bit ^           five_bit ^                        two_bit ^ one_bit           , /* 0xA6 */
-                        seven_bit ^           five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0xA7 */
-                        seven_bit ^           five_bit ^            three_bit                               , /* 0xA8 */
-                        seven_bit ^           five_bit ^            three_bit                     ^ zero_bit, /* 0xA9 */
-                        seven_bit ^           five_bit ^            three_bit           ^ one_bit           , /* 0xAA */
-                        seven_bit ^           five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0xAB */
-                        seven_bit ^           five_bit ^            three_bit ^ two_bit                     , /* 0xAC */
-                        seven_bit ^           five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0xAD */
-                        seven_bit ^           five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0xAE */
-                        seven_bit ^           five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xAF */
-                        seven_bit ^           five_bit ^ four_bit                                           , /* 0xB0 */
-                        seven_bit ^           five_bit ^ four_bit ^                                 zero_bit, /* 0xB1 */
-                        seven_bit ^           five_bit ^ four_bit ^                       one_bit           , /* 0xB2 */
-                        seven_bit ^           five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0xB3 */
-                        seven_bit ^           five_bit ^ four_bit ^             two_bit                     , /* 0xB4 */
-                        seven_bit ^           five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0xB5 */
-                        seven_bit ^           five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0xB6 */
-                        seven_bit ^           five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xB7 */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit                               , /* 0xB8 */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0xB9 */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0xBA */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xBB */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0xBC */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xBD */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xBE */
-                        seven_bit ^           five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xBF */
-                        seven_bit ^ six_bit                                                                 , /* 0xC0 */
-                        seven_bit ^ six_bit ^                                                       zero_bit, /* 0xC1 */
-                        seven_bit ^ six_bit ^                                             one_bit           , /* 0xC2 */
-                        seven_bit ^ six_bit ^                                             one_bit ^ zero_bit, /* 0xC3 */
-                        seven_bit ^ six_bit ^                                   two_bit                     , /* 0xC4 */
-                        seven_bit ^ six_bit ^                                   two_bit           ^ zero_bit, /* 0xC5 */
-                        seven_bit ^ six_bit ^                                   two_bit ^ one_bit           , /* 0xC6 */
-                        seven_bit ^ six_bit ^                                   two_bit ^ one_bit ^ zero_bit, /* 0xC7 */
-                        seven_bit ^ six_bit ^                       three_bit                               , /* 0xC8 */
-                        seven_bit ^ six_bit ^                       three_bit                     ^ zero_bit, /* 0xC9 */
-                        seven_bit ^ six_bit ^                       three_bit           ^ one_bit           , /* 0xCA */
-                        seven_bit ^ six_bit ^                       three_bit           ^ one_bit ^ zero_bit, /* 0xCB */
-                        seven_bit ^ six_bit ^                       three_bit ^ two_bit                     , /* 0xCC */
-                        seven_bit ^ six_bit ^                       three_bit ^ two_bit           ^ zero_bit, /* 0xCD */
-                        seven_bit ^ six_bit ^                       three_bit ^ two_bit ^ one_bit           , /* 0xCE */
-                        seven_bit ^ six_bit ^                       three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xCF */
-                        seven_bit ^ six_bit ^            four_bit                                           , /* 0xD0 */
-                        seven_bit ^ six_bit ^            four_bit ^                                 zero_bit, /* 0xD1 */
-                        seven_bit ^ six_bit ^            four_bit ^                       one_bit           , /* 0xD2 */
-                        seven_bit ^ six_bit ^            four_bit ^                       one_bit ^ zero_bit, /* 0xD3 */
-                        seven_bit ^ six_bit ^            four_bit ^             two_bit                     , /* 0xD4 */
-                        seven_bit ^ six_bit ^            four_bit ^             two_bit           ^ zero_bit, /* 0xD5 */
-                        seven_bit ^ six_bit ^            four_bit ^             two_bit ^ one_bit           , /* 0xD6 */
-                        seven_bit ^ six_bit ^            four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xD7 */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit                               , /* 0xD8 */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit                     ^ zero_bit, /* 0xD9 */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit           ^ one_bit           , /* 0xDA */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xDB */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit                     , /* 0xDC */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xDD */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xDE */
-                        seven_bit ^ six_bit ^            four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xDF */
-                        seven_bit ^ six_bit ^ five_bit                                                      , /* 0xE0 */
-                        seven_bit ^ six_bit ^ five_bit ^                                            zero_bit, /* 0xE1 */
-                        seven_bit ^ six_bit ^ five_bit ^                                  one_bit           , /* 0xE2 */
-                        seven_bit ^ six_bit ^ five_bit ^                                  one_bit ^ zero_bit, /* 0xE3 */
-                        seven_bit ^ six_bit ^ five_bit ^                        two_bit                     , /* 0xE4 */
-                        seven_bit ^ six_bit ^ five_bit ^                        two_bit           ^ zero_bit, /* 0xE5 */
-                        seven_bit ^ six_bit ^ five_bit ^                        two_bit ^ one_bit           , /* 0xE6 */
-                        seven_bit ^ six_bit ^ five_bit ^                        two_bit ^ one_bit ^ zero_bit, /* 0xE7 */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit                               , /* 0xE8 */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit                     ^ zero_bit, /* 0xE9 */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit           ^ one_bit           , /* 0xEA */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit           ^ one_bit ^ zero_bit, /* 0xEB */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit                     , /* 0xEC */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit           ^ zero_bit, /* 0xED */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit           , /* 0xEE */
-                        seven_bit ^ six_bit ^ five_bit ^            three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xEF */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit                                           , /* 0xF0 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                                 zero_bit, /* 0xF1 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                       one_bit           , /* 0xF2 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^                       one_bit ^ zero_bit, /* 0xF3 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit                     , /* 0xF4 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit           ^ zero_bit, /* 0xF5 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit           , /* 0xF6 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^             two_bit ^ one_bit ^ zero_bit, /* 0xF7 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit                               , /* 0xF8 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit                     ^ zero_bit, /* 0xF9 */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit           , /* 0xFA */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit           ^ one_bit ^ zero_bit, /* 0xFB */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit                     , /* 0xFC */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit           ^ zero_bit, /* 0xFD */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit           , /* 0xFE */
-                        seven_bit ^ six_bit ^ five_bit ^ four_bit ^ three_bit ^ two_bit ^ one_bit ^ zero_bit, /* 0xFF */ }
-
+  const ABCM_PROC_A_92 int                   = iemproto.ABCM_PROC_A_92
+  const ABCM_PROC_B_92 int                   = iemproto.ABCM_PROC_B_92
 
 // Global Variables
 
-var Shadows [5]int                         // Shadow resgisters for the port extenders
+var Settings Config                        // Tester configuration
+
+// Subcommands, by name. All but run and replay work on the stored results.
+
+var Subcommands = map[string]func( []string ) error{
+  "run": RunBatch,
+  "report": RunReport,
+  "analyze": RunAnalyze,
+  "spc": RunSPC,
+  "replay": RunReplay,
+}
 
 var TestDB *couchdb.Database               // Pointer to the test database
 
-var SerialPort *serial.Port                // Serial port for talking to the IEM
+var IEMPort transport.Transport           // Link for talking to the IEM
+var IEM *iemclient.Client                  // Requests and responses on IEMPort
+var IEMTrace *iemtrace.Tracer              // Trace of the traffic on IEMPort, nil if not tracing
+var IEMRecorder *replay.Recorder           // Recording of the session, nil if not recording
+var ReplayPlayer *replay.Player            // Recording played back by the replay transport and fixture
 var ConsoleInput *bufio.Reader             // Console Input port
+var ConsoleSource io.Reader = os.Stdin     // Where console input comes from
 
 var AssemblyMajorPartNumber int            // Upper part of the assembly part number
 var AssemblyMinorPartNumber int            // Lower part of the assembly part number
@@ -456,117 +207,6 @@ var ADCMMinorPartNumber int                // Lower part of the ADCM part number
 var ADCMPartNumber string                  // ADCM Part Number string
 var ADCMSerialNumber string                // ADCM Serial Number string
 
-/* Test Result Structures */
-
-// Range type test result
-
-type RangeTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time the test was run
-  Date string `json:"date"`                                 // Date the test was run
-  Value int `json:"value"`                                  // Value generated by the test
-  MaxValue int `json:"maxvalue"`                            // Maximum allowed value
-  MinValue int `json:"minvalue"`                            // Minimum allowed value
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Lower Limit Test Result
-
-type LowerLimitTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time the test was run
-  Date string `json:"date"`                                 // Date the test was run
-  Value int `json:"value"`                                  // Value generated by the test
-  LowerLimit int `json:"lowerlimit"`                        // Minimum allowed value
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Upper Limit Test Result
-
-type UpperLimitTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time the test was run
-  Date string `json:"date"`                                 // Date the test was run
-  Value int `json:"value"`                                  // Value generated by the test
-  UpperLimit int `json:"upperlimit"`                        // Maximum allowed value
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Match Test Result
-
-type MatchTestResult struct {          
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time the test was run
-  Date string `json:"date"`                                 // Date the test was run
-  Received int `json:"received"`                            // Value generated by the test
-  Expected int `json:"expected"`                            // Value expected by the test
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Pass/Fail Test Result 
-
-type PassFailTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time that the test was run
-  Date string `json:"date"`                                 // Date that the test was run
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Hardware Version Number Test Result
-
-type HardwareVersionTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time that the test was run
-  Date string `json:"date"`                                 // Date that the test was run
-  HardwareVersion int `json:"hardwareversion"`              // Harware Version Number of the module
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
-// Software Version Number Test Result
-
-type SoftwareVersionTestResult struct {
-  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
-  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
-  PartNumber string `json:"partnumber"`                     // Module Part Number
-  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
-  TestName string `json:"name"`                             // Name of the test
-  Time string `json:"time"`                                 // Time that the test was run
-  Date string `json:"date"`                                 // Date that the test was run
-  SoftwareVersion string `json:"softwareversion"`           // Software Version Number of the module
-  Pass bool `json:"pass"`                                   // Pass/Fail flag
-  couchdb.Document                                          // Associated Document Information
-}
-
 /*
    Procedure Name : CRC16
 
@@ -579,17 +219,13 @@ type SoftwareVersionTestResult struct {
 */
 
 func CRC16( bytes []int, size int ) int {
-  crc := 0
-  index := 0
+  message := make([]byte, size)
 
-  for i:=0;i < size;i++ {
-    crc ^= (bytes[i] << 8)
-    index = (crc >> 8) & 0xff
-    
-    crc = ((crc << 8) ^ crc_table[index]) & 0xffff
+  for i := 0;i < size;i++ {
+    message[i] = (byte) (bytes[i])
   }
 
-  return crc
+  return (int) (iemproto.CRC16( message ))
 }
 
 /*
@@ -610,58 +246,19 @@ func SendBytes( bytes []int ) (int, error) {
 
   var err error
   RetValue := -1
-  outputIndex := 0
-
-  output := make([]byte, 2*len(bytes)+4, 2*len(bytes) + 4) // Setup output message
-
-  output[outputIndex] = 0xf5 // Add the starting byte
-  outputIndex += 1
-
-  /* Add the CRC and do the byte stuffing */
 
-  for i := 0;i < len(bytes);i++ {
-    if i == (len(bytes) - 1) {
-      if bytes[i] & 0xf000 == 0xf000 {
-        output[outputIndex] = 0xf0
-        outputIndex += 1
-        output[outputIndex] = (byte) (((bytes[i] & 0xf00) >> 8) & 0xff)
-        outputIndex += 1
-      } else {
-        output[outputIndex] = (byte) ((bytes[i] >> 8) & 0xff)
-        outputIndex += 1
-      }
+  /* Split the message into its frame fields. The CRC is recalculated by the encoder. */
 
-      if (bytes[i] & 0xf0) == 0xf0 {
-        output[outputIndex] = 0xf0
-        outputIndex += 1
-        
-        output[outputIndex] = (byte) (bytes[i] & 0xf)
-        outputIndex += 1
-      } else {
-        output[outputIndex] = (byte) (bytes[i] & 0xff)
-        outputIndex += 1
-      }
-      
-      output[outputIndex] = 0xf6
-      outputIndex += 1
-    } else {
-      if (bytes[i] & 0xf0) == 0xf0 {
-        output[outputIndex] = 0xf0
-        outputIndex += 1
+  frame := iemproto.Frame{ Selector: (byte) (bytes[0]), Subselector: (byte) (bytes[1]) }
 
-        output[outputIndex] = (byte) (bytes[i] & 0xf)
-        outputIndex += 1
-      } else {
-        output[outputIndex] = (byte) (bytes[i])
-        outputIndex += 1
-      }
-    }
+  for i := 2;i < len(bytes) - 1;i++ {
+    frame.Data = append(frame.Data, (byte) (bytes[i]))
   }
 
-  /* Send the commpled message to the IEM */
+  /* The client adds the framing bytes, does the byte stuffing and sends the completed message to the IEM */
 
   if RetValue == -1 {
-    RetValue, err = SerialPort.Write( output )
+    RetValue, err = IEM.Send( RunContext(), frame )
   }
 
   /* Return the bytes sent and any errors */
@@ -670,104 +267,42 @@ func SendBytes( bytes []int ) (int, error) {
 }
 
 /*
-    Procedure Name : UnstuffMessage
+    Procedure Name : SendAndReceive
 
-    Description    : This routine takes an imcoming IEM message and
-                     reverses the buff stuffing.
+    Description    : Sends a request message to the IEM and waits for
+                     its response. A response that is lost, cut short,
+                     corrupted or meant for another request is asked for
+                     again as the IEM client's retry policy allows.
 
-    Arguments      : bytes - Slice containg the message to unstuff
-                     responseCount - Starting size of the message
+    Arguments      : message     - Slice that contains the message bytes and CRC
+                     response    - Slice used to contain the response message
+                     responseLen - Expected response message length
 
-    Retrun Value   : Length of message after unstuffing
+    Return Value   : Response Message Length
+                     Any reception error
 */
 
-func UnstuffMessage( bytes []byte, responseCount int ) (int) {
-  stuff := (int) (0)
-  returnCount := (int) (0)
-
-  /*
-    In order to unstuff the message we look for 0xf0 bytes. 
-    When we find one, we combine it with the following byte
-    replacing the two bytes with one. As we go we keep track
-    of the remaining bytes in the message.
-  */
-
-  for i := 0;i < responseCount;i++ {
-    if stuff == 0 {
-      bytes[returnCount] = bytes[i]
-
-      if bytes[i] == 0xf0 {
-        stuff = 1
-      } else {
-        returnCount += 1
-      }
-    } else {
-      stuff = 0
-
-      bytes[returnCount] |= bytes[i]
+func SendAndReceive( message []int, response []byte, responseLen int ) (int, error) {
+  request := iemproto.Frame{ Selector: (byte) (message[0]), Subselector: (byte) (message[1]) }
 
-      returnCount += 1
-    }
+  for i := 2;i < len(message) - 1;i++ {
+    request.Data = append(request.Data, (byte) (message[i]))
   }
 
-  return returnCount
-}
-
-/*
-    Procedure Name : WaitForResponse
-
-    Description    : Waits for the return message which is a respnse
-                     to the last message sent to the IEM.
+  reply, err := IEM.Query( RunContext(), request, responseLen - iemproto.Overhead )
 
-    Arguments      : Slice used to contain the response message
-                     Expected response message length
-
-    Retrun Value   : Response Message Length
-                     Any reception error
-*/
+  if err != nil {
+    log.Printf("%q", err)
 
-func WaitForResponse( bytes []byte, responseCount int ) (int, error) {
-  var byteCount int = 0
-  var returnCount int = 0
-  var err error = nil
+    return 0, err
+  }
 
   /*
-      This Serial port is setup as non-blocking with a five second
-      timeout. The strategy is to bring the message a few bytes at
-      a time until we complete the message. We consider the message
-      finished when we see the end framing byte or the input times
-      out on a read. If we get a timeout, we return an error of no
-      response. If we get a complete message, then we run it through
-      the unstuffing routine before we return it to the caller.
+      Hand the response back as the unstuffed message, start byte to end
+      byte, the way the response routines expect it.
   */
 
-  returnCount, err = SerialPort.Read( bytes )
-
-  byteCount += returnCount
-
-  for byteCount < (responseCount + 10) {
-    returnCount, err = SerialPort.Read( bytes[byteCount:] )
-
-    byteCount += returnCount
-
-    if byteCount >= 1 {
-      if bytes[byteCount - 1] == 0xf6 {
-        break
-      }
-    }
-
-    if byteCount == 0 {
-      err = errors.New("No response")
-
-      break
-    }
-  } 
-
-  if err == nil {
-    byteCount = UnstuffMessage( bytes, byteCount )
-  }
-
-  return byteCount, err
+  return copy( response, iemproto.Unstuffed( reply ) ), nil
 }
 
 /*
@@ -803,8 +338,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, SW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, SW_VERSION_RESPONSE_LEN )
 
         /*
            I/O Processor Software Version request
@@ -814,8 +348,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, SW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, SW_VERSION_RESPONSE_LEN )
 
         /*
            ADCM Software Version request
@@ -825,8 +358,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, SW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, SW_VERSION_RESPONSE_LEN )
 
         /*
            ABCM Processor A Software Version request
@@ -836,8 +368,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, SW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, SW_VERSION_RESPONSE_LEN )
 
         /*
            ABCM Processor B Software Version request
@@ -847,11 +378,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, SW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, SW_VERSION_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -865,8 +395,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, HW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, HW_VERSION_RESPONSE_LEN )
 
         /*
            I/O Processor Hardware Version request
@@ -876,8 +405,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, HW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, HW_VERSION_RESPONSE_LEN )
 
         /*
            ADCM Hardware Version request
@@ -887,8 +415,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, HW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, HW_VERSION_RESPONSE_LEN )
 
         /*
            ABCM Hardware Version request
@@ -898,11 +425,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, HW_VERSION_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, HW_VERSION_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -916,8 +442,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, MON_VOLTAGES_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, MON_VOLTAGES_RESPONSE_LEN )
 
         /*
            ADCM Monitored Voltages request
@@ -927,8 +452,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, MON_VOLTAGES_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, MON_VOLTAGES_RESPONSE_LEN )
 
         /*
            ABCM Monitored Voltages request
@@ -938,11 +462,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, MON_VOLTAGES_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, MON_VOLTAGES_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -956,8 +479,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, RESET_COUNTER_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, RESET_COUNTER_RESPONSE_LEN )
 
         /*
            ADCM Reset Counter Value request
@@ -967,8 +489,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, RESET_COUNTER_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, RESET_COUNTER_RESPONSE_LEN )
 
         /*
            ABCM Reset Counter Value request
@@ -978,11 +499,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, RESET_COUNTER_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, RESET_COUNTER_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -996,8 +516,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, STATUS_VECTOR_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, STATUS_VECTOR_RESPONSE_LEN )
 
         /*
            ADCM Status Vector Value request
@@ -1007,8 +526,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, STATUS_VECTOR_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, STATUS_VECTOR_RESPONSE_LEN )
 
         /*
            ABCM Status Vector Value request
@@ -1018,11 +536,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, STATUS_VECTOR_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, STATUS_VECTOR_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -1034,8 +551,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, DIGITAL_INPUTS_RESPONSE_LEN )
+      returnCount, ResponseErr = SendAndReceive( message, response, DIGITAL_INPUTS_RESPONSE_LEN )
 
     case ANALOG_INPUTS :
       switch subselector {
@@ -1047,8 +563,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, ANALOG_INPUTS_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, ANALOG_INPUTS_RESPONSE_LEN )
 
         /*
            10 Volt Analog Input Values request
@@ -1058,8 +573,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, ANALOG_INPUTS_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, ANALOG_INPUTS_RESPONSE_LEN )
 
         /*
            80 Volt Analog Input Values request
@@ -1069,11 +583,10 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
           message := []int{ selector, subselector, 0 }
           crc := CRC16( message, 2 )
           message[2] = crc
-          SendBytes( message )
-          returnCount, ResponseErr = WaitForResponse( response, ANALOG_INPUTS_RESPONSE_LEN )
+          returnCount, ResponseErr = SendAndReceive( message, response, ANALOG_INPUTS_RESPONSE_LEN )
 
         default :
-          ResponseErr = errors.New("Unknown Subselector")
+          ResponseErr = iemclient.ErrUnknownSubselector
           returnCount = 0
       }
 
@@ -1085,8 +598,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, PRESSURE_INPUTS_RESPONSE_LEN )
+      returnCount, ResponseErr = SendAndReceive( message, response, PRESSURE_INPUTS_RESPONSE_LEN )
 
     /*
        Current 4-20 mA Input Values request
@@ -1096,8 +608,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, CUR_4_20MA_INPUTS_RESPONSE_LEN )
+      returnCount, ResponseErr = SendAndReceive( message, response, CUR_4_20MA_INPUTS_RESPONSE_LEN )
 
     /*
        Speed Sensor Input Values request
@@ -1107,8 +618,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, SPEED_SENSOR_INPUTS_RESPONSE_LEN )
+      returnCount, ResponseErr = SendAndReceive( message, response, SPEED_SENSOR_INPUTS_RESPONSE_LEN )
 
     /*
        Network Parameters request
@@ -1118,8 +628,7 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, NETWORK_INTERFACE_PARAMS_RESPONSE_LEN )
+      returnCount, ResponseErr = SendAndReceive( message, response, NETWORK_INTERFACE_PARAMS_RESPONSE_LEN )
 
     /*
        ADCM Ambient Light Sensor Value request
@@ -1129,26 +638,156 @@ func InformationSelectionCommand( selector int, subselector int, response []byte
       message := []int{ selector, 0, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
-      returnCount, ResponseErr = WaitForResponse( response, 6 )
+      returnCount, ResponseErr = SendAndReceive( message, response, AMBIENT_LIGHT_INT_RESPONSE_LEN )
 
     default :
-      ResponseErr = errors.New("Unknown Selector")
+      ResponseErr = iemclient.ErrUnknownSelector
       returnCount = 0
   }
 
   return returnCount, ResponseErr
 }
 
+/*
+    Procedure Name : SendCommand
+
+    Description    : Sends a set-state command to the IEM and confirms
+                     that the unit took it, so that a lost command is
+                     reported here rather than as a hardware failure
+                     later in the test. A command that cannot be
+                     confirmed is counted as sent and returns
+                     ErrUnconfirmed.
+
+    Arguments      : message - Slice that contains the message bytes and CRC
+                     confirm - Query that shows the new state, nil if the
+                               state cannot be read back
+
+    Return Value   : Flag indicating whether the command was accepted ( 1 accepted, 0 not accepted )
+                     Any error sending or confirming the command
+*/
+
+func SendCommand( message []int, confirm iemclient.Confirm ) (int, error) {
+  command := iemproto.Frame{ Selector: (byte) (message[0]), Subselector: (byte) (message[1]) }
+
+  for i := 2;i < len(message) - 1;i++ {
+    command.Data = append(command.Data, (byte) (message[i]))
+  }
+
+  err := IEM.Command( RunContext(), command, confirm )
+
+  if err != nil {
+    log.Printf("%q", err)
+  }
+
+  switch {
+    case errors.Is( err, iemclient.ErrUnconfirmed ) :
+      return 1, err
+
+    case err != nil :
+      return 0, err
+  }
+
+  return 1, nil
+}
+
+/*
+    Procedure Name : resetCounterCleared
+
+    Description    : Confirms a reset counter command by reading the
+                     counter back.
+
+    Arguments      : selector - Component selector of the reset command
+
+    Return Value   : The confirmation
+*/
+
+func resetCounterCleared( selector int ) iemclient.Confirm {
+  return func( ctx context.Context ) error {
+    /*
+        The component selectors of the reset command are the
+        subselectors of the reset counter query.
+    */
+
+    request := iemproto.Frame{ Selector: iemproto.RESET_COUNTER, Subselector: (byte) (selector) }
+
+    reply, err := IEM.Query( ctx, request, RESET_COUNTER_RESPONSE_LEN - iemproto.Overhead )
+
+    if err == nil && (reply.Data[0] != 0 || reply.Data[1] != 0) {
+      err = fmt.Errorf("%w: reset counter %02X reads %d", iemclient.ErrNotAccepted, selector,
+                       ((int) (reply.Data[0]) << 8) | (int) (reply.Data[1]))
+    }
+
+    return err
+  }
+}
+
+/*
+    Procedure Name : magValveDrive
+
+    Description    : Confirms a mag valve drive command by reading the
+                     drive bit of the processor from the ABCM status
+                     vector.
+
+    Arguments      : processor - ABCM_PROC_A_92 or ABCM_PROC_B_92
+                     enable    - 1 if the drive should be on, 0 if off
+
+    Return Value   : The confirmation
+*/
+
+func magValveDrive( processor int, enable int ) iemclient.Confirm {
+  bit := (byte) (ABCM_A_MAG_VALVE_DRIVE)
+
+  if processor == ABCM_PROC_B_92 {
+    bit = ABCM_B_MAG_VALVE_DRIVE
+  }
+
+  return func( ctx context.Context ) error {
+    request := iemproto.Frame{ Selector: iemproto.STATUS_VECTOR, Subselector: iemproto.ABCM_12 }
+
+    reply, err := IEM.Query( ctx, request, STATUS_VECTOR_RESPONSE_LEN - iemproto.Overhead )
+
+    if err == nil && ((reply.Data[0] & bit) != 0) != (enable != 0) {
+      err = fmt.Errorf("%w: ABCM status %02X, mag valve drive %02X should be %d", iemclient.ErrNotAccepted,
+                       reply.Data[0], processor, enable)
+    }
+
+    return err
+  }
+}
+
+/*
+    Procedure Name : allowUnconfirmed
+
+    Description    : Passes over the error of a command that was sent but
+                     whose state cannot be read back, for callers whose
+                     later checks show whether the command took. The
+                     error is still logged.
+
+    Arguments      : err - Error from one of the command routines
+
+    Return Value   : nil if the command was sent and only cannot be
+                     confirmed, otherwise err
+*/
+
+func allowUnconfirmed( err error ) error {
+  if errors.Is( err, iemclient.ErrUnconfirmed ) {
+    return nil
+  }
+
+  return err
+}
+
 /*
     Procedure Name : ResetCounterCommand
 
     Description    : This routine sends a command to the IEM to clear
-                     the appropriate reset counter.
+                     the appropriate reset counter, and reads the counter
+                     back to check that it was cleared.
 
     Argument       : selector - Determines which reset counter to clear
 
-    Return Value   : Indicator of whether the message was sent ( 1 sent, 0 not sent)
+    Return Value   : Indicator of whether the command was accepted ( 1 accepted, 0 not accepted)
+                     Any error sending or confirming the command
 */
 
 func ResetCounterCommand( selector int ) (int, error) {
@@ -1162,7 +801,7 @@ func ResetCounterCommand( selector int ) (int, error) {
       message := []int{ REST_CMD, selector, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, resetCounterCleared( selector ) )
 
     /* Clear the ADCM Reset Counter */
 
@@ -1171,7 +810,7 @@ func ResetCounterCommand( selector int ) (int, error) {
       message := []int{ REST_CMD, selector, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, resetCounterCleared( selector ) )
 
     /* Clear the ABCM Reset Counter */
 
@@ -1179,7 +818,7 @@ func ResetCounterCommand( selector int ) (int, error) {
       message := []int{ REST_CMD, selector, 0 }
       crc := CRC16( message, 2 )
       message[2] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, resetCounterCleared( selector ) )
 
     default :
       err = errors.New("Unknown Selector")
@@ -1193,11 +832,15 @@ func ResetCounterCommand( selector int ) (int, error) {
     Procedure Name : SetSpeedSensorRefCommand
 
     Description    : This routine sends a message to the IEM to set
-                     the speed sensor reference voltage.
+                     the speed sensor reference voltage. The reference
+                     cannot be read back, so once sent the command fails
+                     with ErrUnconfirmed; the speed sensor checks that
+                     follow show whether it took.
 
     Argument       : reference - 0 for 2.5 volts and 1 for 0 volts.
 
-    Return Value   : Flag indicating whether the message was sent ( 1 sent, 0 not sent)
+    Return Value   : Flag indicating whether the command was accepted ( 1 accepted, 0 not accepted)
+                     Any error sending or confirming the command
 */
 
 func SetSpeedSensorRefCommand( reference int ) (int, error) {
@@ -1213,7 +856,7 @@ func SetSpeedSensorRefCommand( reference int ) (int, error) {
       message := []int{ SET_SPEED_SENSOR_REF_CMD, 0, reference, 0 }
       crc := CRC16( message, 3 )
       message[3] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, nil )
 
     /*
        Set the reference to 0 volts
@@ -1223,7 +866,7 @@ func SetSpeedSensorRefCommand( reference int ) (int, error) {
       message := []int{ SET_SPEED_SENSOR_REF_CMD, 0, reference, 0 }
       crc := CRC16( message, 3 )
       message[3] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, nil )
 
     default :
       err = errors.New("Unknown Reference")
@@ -1237,12 +880,14 @@ func SetSpeedSensorRefCommand( reference int ) (int, error) {
     Procedure Name : SetADCM_LEDSStateCommand
 
     Description    : This routines sends a command to set the state
-                     of the ADCM LEDs.
+                     of the ADCM LEDs. The LEDs cannot be read back, so
+                     once sent the command fails with ErrUnconfirmed.
 
     Arguments      : ledMask    - Determines which LEDs to set
                      brightness - Brightness level of the LEDs
 
-    Return Value   : Flag indicates whether the message was sent (1 sent, 0 not sent)
+    Return Value   : Flag indicates whether the command was accepted (1 accepted, 0 not accepted)
+                     Any error sending or confirming the command
 */
 
 func SetADCM_LEDStateCommand( ledMask int, brightness int ) (int, error) {
@@ -1254,7 +899,7 @@ func SetADCM_LEDStateCommand( ledMask int, brightness int ) (int, error) {
       message := []int{ SET_ADCM_LED_STATE_CMD, 0, ledMask, brightness, 0 }
       crc := CRC16( message, 4 )
       message[4] = crc
-      SendBytes( message )
+      RetValue, err = SendCommand( message, nil )
     } else {
       err = errors.New("Unknown Mask Bits")
       RetValue = 0
@@ -1271,9 +916,11 @@ func SetADCM_LEDStateCommand( ledMask int, brightness int ) (int, error) {
     Procedure Name : SetADCMSonalertStateCommand
 
     Description    : This routine sends a command to the IEM to enable/disable the ADCMs sonalert
-                     and set its volume.
+                     and set its volume. The state cannot be read back, so once sent the command fails
+                     with ErrUnconfirmed; the sonalert voltage itself is checked by the ADCM test.
 
-    Return Value   : Flag indicating whether the message was sent ( 1 sent, 0 not sent )
+    Return Value   : Flag indicating whether the command was accepted ( 1 accepted, 0 not accepted )
+                     Any error sending or confirming the command
 */
 
 func SetADCMSonalertStateCommand( enable int, volume int ) (int, error) {
@@ -1286,13 +933,13 @@ func SetADCMSonalertStateCommand( enable int, volume int ) (int, error) {
         message := []int{ SET_ADCM_SONALERT_STATE_CMD, 0, enable, volume, 0 }
         crc := CRC16( message, 4 )
         message[4] = crc
-        SendBytes( message )
+        RetValue, err = SendCommand( message, nil )
 
       case 1 :
         message := []int{ SET_ADCM_SONALERT_STATE_CMD, 0, enable, volume, 0 }
         crc := CRC16( message, 4 )
         message[4] = crc
-        SendBytes( message )
+        RetValue, err = SendCommand( message, nil )
 
       default :
         err = errors.New("UnKnow Enable Value")
@@ -1311,13 +958,14 @@ func SetADCMSonalertStateCommand( enable int, volume int ) (int, error) {
 
     Description    : This routine sends a command to the IEM to
                      set the Magnetic Valve Drive State for the
-                     requested ADCM processor (A or B).
+                     requested ADCM processor (A or B), and checks the
+                     drive bit in the ABCM status vector afterwards.
 
     Arguments      : processor - Determines which processor is affected
                      enable    - Determines whether the drive is enabled or disabled.
 
-    Return Value   : Flage indicating whether the message was sent ( 1 sent, 0 not sent )
-                     Any error returned from sending the message
+    Return Value   : Flage indicating whether the command was accepted ( 1 accepted, 0 not accepted )
+                     Any error returned from sending or confirming the command
 */
 
 func SetABCMMagValveDriveStateCommand( processor int, enable int ) (int, error) {
@@ -1331,13 +979,13 @@ func SetABCMMagValveDriveStateCommand( processor int, enable int ) (int, error)
           message := []int{ SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD, processor, enable, 0 }
           crc := CRC16( message, 3 )
           message[3] = crc
-          SendBytes( message )
+          RetValue, err = SendCommand( message, magValveDrive( processor, enable ) )
 
         case 1 :
           message := []int{ SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD, processor, enable, 0 }
           crc := CRC16( message, 3 )
           message[3] = crc
-          SendBytes( message )
+          RetValue, err = SendCommand( message, magValveDrive( processor, enable ) )
 
         default :
           err = errors.New("Unknown Enable Value")
@@ -1350,13 +998,13 @@ func SetABCMMagValveDriveStateCommand( processor int, enable int ) (int, error)
           message := []int{ SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD, processor, enable, 0 }
           crc := CRC16( message, 3 )
           message[3] = crc
-          SendBytes( message )
+          RetValue, err = SendCommand( message, magValveDrive( processor, enable ) )
 
         case 1 :
           message := []int{ SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD, processor, enable, 0 }
           crc := CRC16( message, 3 )
           message[3] = crc
-          SendBytes( message )
+          RetValue, err = SendCommand( message, magValveDrive( processor, enable ) )
 
         default :
           err = errors.New("Unknown Enable Value")
@@ -1376,13 +1024,13 @@ func SetABCMMagValveDriveStateCommand( processor int, enable int ) (int, error)
 
     Description    : This routine sets up the initial state of the IEM tester before a test is run.
 
-    Arguments      : mcp - Slice of pointer to control structures for the port extenders.
+    Arguments      : fix - Port extenders of the test fixture.
 
     Return Value   : Flag indicating whether the initialization was successful ( 1 successful, 0 not successful)
                      Any error that occurred during initialization.
 */
 
-func Init( mcp []*MCP23S17.MCP23S17 ) (int, error) {
+func Init( fix fixture.Fixture ) (int, error) {
   var err error = nil
   var err1 error = nil
   RetValue := 1
@@ -1391,30 +1039,11 @@ func Init( mcp []*MCP23S17.MCP23S17 ) (int, error) {
      Set the port extenders to their initial values.
   */
 
-  mcp[0].Write( 0xfc, MCP23S17.OLATA )
-  mcp[0].Write( 0xcf, MCP23S17.OLATB )
-
-  Shadows[0] = 0xcffc
-
-  mcp[1].Write( 0xff, MCP23S17.OLATA )
-  mcp[1].Write( 0xff, MCP23S17.OLATB )
-
-  Shadows[1] = 0xffff
-
-  mcp[2].Write( 0xff, MCP23S17.OLATA )
-  mcp[2].Write( 0xff, MCP23S17.OLATB )
-
-  Shadows[2] = 0xffff
-
-  mcp[3].Write( 0x06, MCP23S17.OLATA )
-  mcp[3].Write( 0xc0, MCP23S17.OLATB )
-
-  Shadows[3] = 0xc006
-
-  mcp[4].Write( 0x00, MCP23S17.OLATA )
-  mcp[4].Write( 0x00, MCP23S17.OLATB )
-
-  Shadows[4] = 0x0000
+  fix.Write( 0, 0xcffc )
+  fix.Write( 1, 0xffff )
+  fix.Write( 2, 0xffff )
+  fix.Write( 3, 0xc006 )
+  fix.Write( 4, 0x0000 )
 
   /*
      Turn off the ADCM Sonalert.
@@ -1422,6 +1051,8 @@ func Init( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
   RetValue, err = SetADCMSonalertStateCommand( 0, 100 )
 
+  err = allowUnconfirmed( err )
+
   if err != nil {
     RetValue = 0
   }
@@ -1432,6 +1063,8 @@ func Init( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
   RetValue, err1 = SetADCM_LEDStateCommand( 0, 20 )
 
+  err1 = allowUnconfirmed( err1 )
+
   if err1 != nil {
     if err == nil {
       err = err1
@@ -1472,15 +1105,17 @@ func Init( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Description    : This routine runs the IEM 4-20 mA test.
 
-    Arguments      : mcp - Slice of pointers to port extender control structures.
+    Arguments      : fix - Port extenders of the test fixture.
 
     Return Value   : Pass/Fail flag
                      Any error that occurred during the test.
 */
 
-func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
+func Test_4_20MA( fix fixture.Fixture ) (int, error) {
   var err error = nil
   var responseCount int
+  var resets ResetCounter
+  var currents CurrentInputs
   RetValue := 1
   Passed := 1
 
@@ -1490,12 +1125,14 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
      Turn on bit 13 of port extender zero. Then ask for the IEM 4-20 mA inputs.
   */
 
-  Shadows[0] |= 0x2000
-  
-  mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
+  fixture.Assert( fix, fixture.SigCurrentSensorLoadEnable )
 
   responseCount, err = InformationSelectionCommand( CUR_4_20MA_INPUTS, 0, response )
 
+  if err == nil {
+    currents, err = ParseCurrentInputs( response, responseCount )
+  }
+
   if err != nil || responseCount != CUR_4_20MA_INPUTS_RESPONSE_LEN {
 
     RetValue = 0
@@ -1515,10 +1152,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     counts := 0
 
-    counts = (int) (response[17])
-    counts = (counts << 8) + (int) (response[18])
+    counts = (int) (currents.Channels[7])
 
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1526,19 +1162,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = IEM4_20mASerialNumber
     Test.TestName = "4-20mA Test 1 Channel 8 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3708
-    Test.MinValue = 3492
+    Test.Value = results.Int( counts )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if counts <= 3492 || counts >= 3708 {
-      fmt.Printf(" Test 1 Channel 8 Failed (Got %d Expected > 3492 and < 3708)\r\n", counts)
+    if !limit.Check( counts ) {
+      fmt.Printf(" Test 1 Channel 8 Failed (Got %d Expected %s)\r\n", counts, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -1547,11 +1188,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
-    Shadows[0] |= 0x4000
-
-    mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
+    fixture.Assert( fix, fixture.SigVoltageSelectForCurrentSensorOutputs )
 
     start := time.Now()
 
@@ -1567,6 +1206,10 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( CUR_4_20MA_INPUTS, 0, response )
 
+    if err == nil {
+      currents, err = ParseCurrentInputs( response, responseCount )
+    }
+
     if err != nil || responseCount != CUR_4_20MA_INPUTS_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -1592,10 +1235,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
   if err == nil {
     counts := 0
 
-    counts = (int) (response[3])
-    counts = (counts << 8) + (int)(response[4])
+    counts = (int) (currents.Channels[0])
 
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1603,19 +1245,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = IEM4_20mASerialNumber
     Test.TestName = "4-20mA Test 2 Channel 1 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2100
-    Test.MinValue = 1900
+    Test.Value = results.Int( counts )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if counts <= 1900 || counts >= 2100 {
-      fmt.Printf(" Test 2 Channel 1 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+    if !limit.Check( counts ) {
+      fmt.Printf(" Test 2 Channel 1 Failed (Got %d Expected %s)\r\n", counts, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -1624,17 +1271,16 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     if err == nil {
       /*
           Check channel two for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[5])
-      counts = (counts << 8) + (int)(response[6])
+      counts = (int) (currents.Channels[1])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1642,19 +1288,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 2 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 2 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 2 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1663,7 +1314,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -1671,10 +1322,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel three for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[7])
-      counts = (counts << 8) + (int)(response[8])
+      counts = (int) (currents.Channels[2])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1682,19 +1332,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 3 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 3 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 3 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1703,7 +1358,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -1711,10 +1366,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel four for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[9])
-      counts = (counts << 8) + (int)(response[10])
+      counts = (int) (currents.Channels[3])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1722,19 +1376,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 4 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 4 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 4 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1743,7 +1402,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -1751,10 +1410,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel five for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[11])
-      counts = (counts << 8) + (int)(response[12])
+      counts = (int) (currents.Channels[4])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1762,19 +1420,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 5 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 5 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 5 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1783,7 +1446,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -1791,10 +1454,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel six for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[13])
-      counts = (counts << 8) + (int)(response[14])
+      counts = (int) (currents.Channels[5])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1802,19 +1464,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 6 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 6 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 6 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1823,7 +1490,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
 
     }
 
@@ -1833,10 +1500,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Then turn off bit 14 of pert extender zero and wait a second. Again ask the IEM for the 4-20 mA inputs.
       */
 
-      counts = (int) (response[15])
-      counts = (counts << 8) + (int)(response[16])
+      counts = (int) (currents.Channels[6])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1844,19 +1510,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 2 Channel 7 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 2100
-      Test.MinValue = 1900
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 1900 || counts >= 2100 {
-        fmt.Printf(" Test 2 Channel 7 Failed (Got %d Expected > 1900 and < 2100)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 2 Channel 7 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1865,12 +1536,10 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
-    Shadows[0] &= ^0x4000
-
-    mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
+    fixture.Deassert( fix, fixture.SigVoltageSelectForCurrentSensorOutputs )
 
     start := time.Now()
 
@@ -1886,6 +1555,10 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( CUR_4_20MA_INPUTS, 0, response )
 
+    if err == nil {
+      currents, err = ParseCurrentInputs( response, responseCount )
+    }
+
     if err != nil || responseCount != CUR_4_20MA_INPUTS_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -1909,10 +1582,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Check channel one for the proper range and set the pass/fail flag. Write the test result to the database.
     */
 
-    counts := (int)(response[3])
-    counts = (counts << 8) + (int) (response[4])
+    counts := (int) (currents.Channels[0])
 
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1920,19 +1592,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = IEM4_20mASerialNumber
     Test.TestName = "4-20mA Test 3 Channel 1 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 4100
-    Test.MinValue = 3800
+    Test.Value = results.Int( counts )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if counts <= 3800 || counts >= 4200 {
-      fmt.Printf(" Test 3 Channel 1 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+    if !limit.Check( counts ) {
+      fmt.Printf(" Test 3 Channel 1 Failed (Got %d Expected %s)\r\n", counts, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -1941,17 +1618,16 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     if err == nil {
       /*
           Check channel two for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[5])
-      counts = (counts << 8) + (int) (response[6])
+      counts = (int) (currents.Channels[1])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1959,19 +1635,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 2 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 2 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 2 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -1980,7 +1661,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -1988,10 +1669,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel three for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[7])
-      counts = (counts << 8) + (int) (response[8])
+      counts = (int) (currents.Channels[2])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -1999,19 +1679,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 3 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 3 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 3 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -2020,7 +1705,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -2028,10 +1713,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel four for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[9])
-      counts = (counts << 8) + (int) (response[10])
+      counts = (int) (currents.Channels[3])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2039,19 +1723,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 4 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 4 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 4 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -2060,7 +1749,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -2068,10 +1757,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel five for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[11])
-      counts = (counts << 8) + (int) (response[12])
+      counts = (int) (currents.Channels[4])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2079,19 +1767,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 5 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 5 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 5 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -2100,7 +1793,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -2108,10 +1801,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel six for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[13])
-      counts = (counts << 8) + (int) (response[14])
+      counts = (int) (currents.Channels[5])
 
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2119,19 +1811,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 6 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 6 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 6 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -2140,7 +1837,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if err == nil {
@@ -2148,10 +1845,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
           Check channel seven for the proper range and set the pass/fail flag. Write the test result to the database.
       */
 
-      counts = (int) (response[15])
-      counts = (counts << 8) + (int) (response[16])
+      counts = (int) (currents.Channels[6])
   
-      Test := RangeTestResult{}
+      Test := NewResult( results.RANGE )
 
       Test.AssemblyPartNumber = AssemblyPartNumber
       Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2159,19 +1855,24 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Test.SerialNumber = IEM4_20mASerialNumber
       Test.TestName = "4-20mA Test 3 Channel 7 Test"
 
+      limit := TestLimit( Test.TestName )
+
       timeDate := time.Now()
       hour, min, sec := timeDate.Clock()
       year, month, day := timeDate.Date()
 
       Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
       Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4200
-      Test.MinValue = 3800
+      Test.Value = results.Int( counts )
+      Test.UpperLimit = limit.Max
+      Test.LowerLimit = limit.Min
+      Test.LimitsVersion = Limits.Version
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
       Test.Pass = true
 
-      if counts <= 3800 || counts >= 4200 {
-        fmt.Printf(" Test 3 Channel 7 Failed (Got %d Expected > 3800 and < 4200)\r\n", counts)
+      if !limit.Check( counts ) {
+        fmt.Printf(" Test 3 Channel 7 Failed (Got %d Expected %s)\r\n", counts, limit)
 
         Test.Pass = false
         RetValue = 0
@@ -2180,7 +1881,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       Test.SetID(couchdb.GenerateUUID())
 
-      err = couchdb.Store( TestDB, &Test )
+      SaveResult( &Test )
     }
 
     if Passed == 1 {
@@ -2198,14 +1899,15 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( RESET_COUNTER, COMM_PROC_10, response )
 
-    if err == nil && responseCount == RESET_COUNTER_RESPONSE_LEN {
+    if err == nil {
+      resets, err = ParseResetCounter( response, responseCount )
+    }
 
-      before := (int) (response[4])
-      before = (before << 8) + (int) (response[5])
+    if err == nil && responseCount == RESET_COUNTER_RESPONSE_LEN {
 
-      Shadows[0] |= 0x2000
+      before := (int) (resets.Count)
 
-      mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
+      fixture.Assert( fix, fixture.SigCurrentSensorLoadEnable )
     
       start := time.Now()
 
@@ -2221,6 +1923,10 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
       responseCount, err = InformationSelectionCommand( RESET_COUNTER, COMM_PROC_10, response )
 
+      if err == nil {
+        resets, err = ParseResetCounter( response, responseCount )
+      }
+
       if err == nil && responseCount == RESET_COUNTER_RESPONSE_LEN {
         /*
            Compare the first value of the reset counter to the second value of the reset. 
@@ -2228,10 +1934,9 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
            Then write the test result to the database.
         */
 
-        after := (int) (response[4])
-        after = (after << 8) + (int) (response[5])
+        after := (int) (resets.Count)
 
-        Test := MatchTestResult{}
+        Test := NewResult( results.MATCH )
 
         Test.AssemblyPartNumber = AssemblyPartNumber
         Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2245,8 +1950,8 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
         Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
         Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-        Test.Received = after
-        Test.Expected = before
+        Test.Value = results.Int( after )
+        Test.Expected = results.Int( before )
         Test.Pass = true
 
         if before != after {
@@ -2259,7 +1964,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
         Test.SetID(couchdb.GenerateUUID())
 
-        err = couchdb.Store( TestDB, &Test )
+        SaveResult( &Test )
       } else {
         RetValue = 0
         Passed = 0
@@ -2287,6 +1992,10 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( CUR_4_20MA_INPUTS, 0, response )
 
+    if err == nil {
+      currents, err = ParseCurrentInputs( response, responseCount )
+    }
+
     if err != nil || responseCount != CUR_4_20MA_INPUTS_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -2302,15 +2011,13 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
   Passed = 1
 
   if err == nil {
-    counts := (int) (response[17])
-
-    counts = (counts << 8) + (int) (response[18])
+    counts := (int) (currents.Channels[7])
 
     /*
         Check channel 8 for the correct range and set the pass/fail flag. Write the test result to the database.
     */
 
-    Test := LowerLimitTestResult{}
+    Test := NewResult( results.LOWER_LIMIT )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -2318,18 +2025,23 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = IEM4_20mASerialNumber
     Test.TestName = "4-20mA Test 4 Reset Counter Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.LowerLimit = 3492
+    Test.Value = results.Int( counts )
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if counts <= 3492 {
-      fmt.Printf(" Test 4 Channel 8 Failed (Got %d Expected > 3492)\r\n", counts)
+    if !limit.Check( counts ) {
+      fmt.Printf(" Test 4 Channel 8 Failed (Got %d Expected %s)\r\n", counts, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -2338,7 +2050,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     if Passed == 1 {
       fmt.Printf(" Test 4 Channel 8 Passed.\r\n")
@@ -2349,7 +2061,7 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Set the tester back to its initial state.
   */
 
-  _, err1 := Init( mcp )
+  _, err1 := Init( fix )
 
   if err == nil {
     err = err1
@@ -2361,63 +2073,93 @@ func Test_4_20MA( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 /*
     Procedure Name : Test_CPUMain
 
-    Description    : This routine runs the IEM Main CPU test.
+    Description    : This routine runs the IEM Main CPU test. The steps of
+                     the test are in the Main CPU sequence file.
 
-    Arguments      : mcp - Slice of pointers to the port extender control structures
+    Arguments      : fix - Port extenders of the test fixture
                      IEMType - 0 for no alerter, 1 for aleter present
 
     Retrun Value   : Flag indicating whether the test passed or failed.
                      Any error that occurred during the test.
 */
 
-func Test_CPUMain( mcp []*MCP23S17.MCP23S17, IEMType int ) (int, error) {
+func Test_CPUMain( fix fixture.Fixture, IEMType int ) (int, error) {
+  return RunSequence( fix, CPUMainSequence, IEMType )
+}
+
+/*
+    Procedure Name : Test_ABCM
+
+    Description    : This routine performs all of the ABCM tests.
+
+    Arguments      : fix - Port extenders of the test fixture
+
+    Return Value   : Pass/Fail flag
+                     Any error encountered during the tests.
+*/
+
+func Test_ABCM( fix fixture.Fixture ) (int, error) {
   var err error = nil
   var responseCount int
+  var software SoftwareVersion
+  var hardware HardwareVersion
+  var voltages MonitoredVoltages
+  var statusVector StatusVector
 
   response := make([] byte, 50)
 
-  group := 1
-  skip := 0
   RetValue := 1
   Passed := 1
-  zeroPressureReading := 0
 
   /*
-      Turn on bit 12 of port extender 0. Then ask the IEM for the monitored voltanges
-      for the CPU board.
+      Wait two seconds and then ask the IEM for ABCM Monitored Voltages.
   */
 
-  Shadows[0] |= 0x1000
+  start := time.Now()
+
+  t := time.Now()
+
+  elapsed := t.Sub(start)
+
+  for elapsed < time.Duration(2)*time.Second {
+    t = time.Now()
+
+    elapsed = t.Sub(start)
+  } 
 
-  mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
+  responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ABCM_03, response )
 
-  responseCount, err = InformationSelectionCommand( MON_VOLTAGES, IEM_CPU_BOARD_03, response )
+  if err == nil {
+    voltages, err = ParseMonitoredVoltages( response, responseCount )
+  }
 
   if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
     RetValue = 0
 
-    if err == nil && responseCount != 0 {
+    if err == nil {
       err = errors.New("Bad Monitored Voltages.")
-    }
 
-    log.Printf("%q", err)
+      log.Printf("%q", err)
+    }
   }
-  
+
   if err == nil {
     /*
-        Check the 12 Volt entry for the proper range and then set the pass/fail flag. Write the test result to the database.
+        Check the 12 Volt entry for the proper range and then set the pass/fail flag. Write the
+        test result to the database.
     */
 
-    counts := (int) (response[3])
-    counts = (counts << 8) + (int) (response[4])
+    voltage := (int) (voltages.Voltages[0])
 
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 1 12 Volt Test"
+    Test.PartNumber = ABCMPartNumber
+    Test.SerialNumber = ABCMSerialNumber
+    Test.TestName = "ABCM Test 1 12 Volt Test"
+
+    limit := TestLimit( Test.TestName )
 
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
@@ -2425,5289 +2167,59 @@ func Test_CPUMain( mcp []*MCP23S17.MCP23S17, IEMType int ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 11400
-    Test.MinValue = 12600
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if counts <= 11400 || counts >= 12600 {
-      fmt.Printf(" Test 1 12 Volts Failed ( Got %d Expected 11400 to 12600)\r\n")
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 1 12 Volts Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
-      Passed = 0
       RetValue = 0
+      Passed = 0
     }
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
+   
+    /*
+        Check the 3.3 Volt entry for the proper range and then set the pass/fail flag. Write the
+        test result to the database.
+    */
 
-    if err == nil {
-      /*
-          Check the 3.3 Volt entry for the proper range and then set the pass/fail flag. Write the test result to the database.
-      */
+    voltage = (int) (voltages.Voltages[1])
 
-      counts = (int) (response[5])
-      counts = (counts << 8) + (int) (response[6])
+    Test = NewResult( results.RANGE )
 
-      Test := RangeTestResult{}
+    Test.AssemblyPartNumber = AssemblyPartNumber
+    Test.AssemblySerialNumber = AssemblySerialNumber
+    Test.PartNumber = ABCMPartNumber
+    Test.SerialNumber = ABCMSerialNumber
+    Test.TestName = "ABCM Test 1 3.3 Volt Test"
 
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 1 3.3 Volt Test"
+    limit = TestLimit( Test.TestName )
 
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
+    timeDate = time.Now()
+    hour, min, sec = timeDate.Clock()
+    year, month, day = timeDate.Date()
 
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 3135
-      Test.MinValue = 3465
-      Test.Pass = true
+    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
+    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
+    Test.Pass = true
 
-      if counts <= 3135 || counts >= 3465 {
-        fmt.Printf(" Test 1 3.3 Volts Failed ( Got %d Expected 3135 to 3465)\r\n")
-
-        Test.Pass = false
-        Passed = 0
-        RetValue = 0
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-    }
- 
-    if err == nil {
-      /*
-          Check the 5 Volt entry for th proper range and then set the pass/fail flag. Write the test result to the database.
-      */
-
-      counts = (int) (response[7])
-      counts = (counts << 8) + (int) (response[8])
-
-      Test := RangeTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 1 5 Volt Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 4750
-      Test.MinValue = 5250
-      Test.Pass = true
-
-      if counts <= 4750 || counts >= 5250 {
-        fmt.Printf(" Test 1 5 Volts Failed ( Got %d Expected 4750 to 5250)\r\n")
-
-        Test.Pass = false
-        Passed = 0
-        RetValue = 0
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 1 Monitored Voltages Passed\r\n")
-    }
-
-    Passed = 1
-
-    /*
-        Turn off the top eight bits of port extender 1. THen wait 200 milliseconds.
-    */
-
-    NextBit := 0x8000
-    ReturnBit := (byte) (0x01)
-
-    Shadows[1] &= 0xff
-
-    mcp[1].Write( (byte) ((Shadows[1] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For bit 15 to bit 8 of port extender 1, turn each bit one at a time. After turning on a bit, wait for 200 milliseconds.
-        Then ask the IEM for digital input values and check that only that bit is turned on in the response and then set the pass/fail
-        flag. Write the test result to the database. 
-    */
-
-    for i:= 0;i < 8;i++ {
-      Shadows[1] |= NextBit
-
-      mcp[1].Write( (byte) ((Shadows[1] >> 8) & 0xff), MCP23S17.OLATB )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil && responseCount != 0 {
-          err = errors.New("Bad Digital Inputs.")
-        }
-
-        log.Printf("%q", err)
-
-        break
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 2 74 Volt Outputs (1 -> 8) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^response[3])
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      if response[3] != ^ReturnBit || err != nil {
-        fmt.Printf(" Test 2 74 Volt Output %d Failed (got %X Expected %X)\r\n", i, response[3], ^ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[1] &= ^NextBit
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-        Turn the top eight bits of port extender 1 back on.
-    */
-
-    Shadows[1] |= 0xff00
-
-    mcp[1].Write( (byte) ((Shadows[1] >> 8) & 0xff), MCP23S17.OLATB )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 2 74 Volt Outputs 1 to 8 Passed\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Turn the lower eight bits of port extender 1 and wait 200 milliseconds.
-    */
-
-    NextBit := 0x80
-    ReturnBit := (byte) (0x01)
-
-    Shadows[1] &= 0xff00
-
-    mcp[1].Write( (byte) (Shadows[1] & 0xff), MCP23S17.OLATA )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For bit 7 through bit zero of port extender 1, turn on each bit one at a time. Ask the
-        IEM for digital input values. Check that only current bit is on in the response and
-        then set the pass/fail flag. Write the test result to the database.
-        
-    */
-
-    for i := 0;i < 8;i++ {
-      Shadows[1] |= NextBit
-
-      mcp[1].Write( (byte) (Shadows[1] & 0xff), MCP23S17.OLATA )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 2 74 Volt Outputs (9 -> 16) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^response[4])
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      if response[4] != ^ReturnBit || err != nil {
-        fmt.Printf(" Test 2 74 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 9, response[4], ^ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[1] &= ^NextBit
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-        Turn the lower eight bits of port extender one back on.
-    */
-
-    Shadows[1] |= 0xff
-
-    mcp[1].Write( (byte) (Shadows[1] & 0xff), MCP23S17.OLATA )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 2 74 Volt Outputs 9 to 16 Passed\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    NextBit := 0x8000
-    ReturnBit := (byte) (0x01)
-
-    /*
-        Turn off the top eight bits of port extender 2 and wait 200 milliseconds.
-    */
-
-    Shadows[2] &= 0xff
-
-    mcp[2].Write( (byte) ((Shadows[2] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For bit 15 to bit 8 of port extender 2, turn on each bit one at a time. Wait 200 milliseconds 
-        and ask the IEM for digital input values. Check that only the correct bit is on the response
-        and then set the pass/fail flag. Write the test result to the data base.
-    */
-
-    for i := 0;i < 8;i++ {
-      Shadows[2] |= NextBit
-
-      mcp[2].Write( (byte) ((Shadows[2] >> 8) & 0xff), MCP23S17.OLATB )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 2 74 Volt Outputs (17 -> 24) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^response[5])
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      if response[5] != ^ReturnBit || err != nil {
-        fmt.Printf(" Test 2 74 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 17, response[5], ^ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0;
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[2] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-       Turn the top eight bits of prot extender 2 back on.
-    */
-
-    Shadows[2] |= 0xff00
-
-    mcp[2].Write( (byte) ((Shadows[2] >> 8) & 0xff), MCP23S17.OLATB )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 2 74 Volt Outputs 17 to 24 Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    NextBit := 0x80
-    ReturnBit := (byte) (0x01)
-
-    /*
-        Turn off the lower eight bits of port extender 2 and wait 200 milliseconds.
-    */
-
-    Shadows[2] &= 0xff00
-
-    mcp[2].Write( (byte) (Shadows[2] & 0xff), MCP23S17.OLATA )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For bit 7 to bit 0 of port extender 2, turn on each bit one at a time. Wait 200 milliseconds and
-        tne ask the IEM for digital input values. Check that only the current bit is on and then set
-        the pass/fail flag. Write the test result to the database.
-    */
-
-    for i := 0;i < 8;i++ {
-      Shadows[2] |= NextBit
-
-      mcp[2].Write( (byte) (Shadows[2] & 0xff), MCP23S17.OLATA )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 2 74 Volt Outputs (25 -> 32) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^response[6])
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      if response[6] != ^ReturnBit || err != nil {
-        fmt.Printf(" Test 2 74 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 25, response[6], ^ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0;
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[2] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-        Turn the lower eight bits of port extender 2 back on.
-    */
-
-    Shadows[2] |= 0xff
-
-    mcp[2].Write( (byte) (Shadows[2] & 0xff), MCP23S17.OLATA )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 2 74 Volt Outputs 25 to 32 Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    NextBit := 0x8000
-    ReturnBit := (byte) (0x02)
-
-    /*
-        Turn off bit 15 and bit 14 of port extender 3 and wait 200 milliseconds.
-    */
-
-    Shadows[3] &= 0x3fff
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For bit 15 to bit 14 of port extender 3, turn on one bit at a time. Wait 200 milliseconds and
-        ask the IEM for digital input values. Check that only the current bit is on and then set the
-        pass/fail flag. Write the test result to the database.
-    */
-
-    for i := 0;i < 2;i++ {
-      Shadows[3] |= NextBit
-
-      mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 2 74 Volt Outputs (33 -> 34) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (response[7])
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      if ((response[7] & ReturnBit) != ReturnBit) || err != nil {
-        fmt.Printf(" Test 2 74 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 33, response[7], ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[3] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit >>= 1
-    }
-
-    /*
-        Turn bit 15 and bit 14 of port extender 3 back on.
-    */
-
-    Shadows[3] |= 0xc000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 2 74 Volt Outputs 33 and 34 Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Turn off Bits 8->11 of port extender 0 and wait 200 milliseconds.
-    */
-
-    NextBit := 0x0800
-    ReturnBit := (byte) (0x01)
-
-    Shadows[0] &= 0xf0ff
-
-    mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For each bit turn on each bit one at a time and ask the IEM for digital inputs. Wait 200 milliseconds,
-        check that current bit is the only bit set and then set the pass/fail flag. Write the test result to the
-        database.
-    */
-
-    for i := 0;i < 4;i++ {
-      Shadows[0] |= NextBit
-
-      mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 3 32 Volt Outputs (1 -> 4) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^(response[9] & 0xf) & 0xf)
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      test := (^(response[9] & 0x0f) & 0x0f)
- 
-      if test != ReturnBit || err != nil {
-        fmt.Printf(" Test 3 32 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 1, test, ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0;
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[0] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-         Turn bits 8->11 of port extender 0 back on.
-    */
-
-    Shadows[0] |= 0x0f00
-
-    mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 3 32 Volt Outputs 1 to 4 Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    NextBit := 0x80
-    ReturnBit := (byte) (0x20)
-
-    /*
-        Turn off bits 6 and 7 of port extender 0 and wait 200 milliseconds.
-    */
-
-    Shadows[0] &= 0xff3f
-
-    mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For each bit turn the bits on one at a time and wait 200 milliseconds. Ask the IEM for digital inputs.
-        Check that current bit is the only bit set and then set the pass/fail flag. Write the test result to the
-        database.
-    */
-
-    for i := 0;i < 2;i++ {
-      Shadows[0] |= NextBit
-
-      mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-      start = time.Now()
-
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 3 32 Volt Outputs (5 -> 6) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (response[9] & 0x30)
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      test := response[9] & 0x30
- 
-      if test != ReturnBit || err != nil {
-        fmt.Printf(" Test 3 32 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 5, test, ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0;
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[0] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit >>= 1
-    }
-
-    /*
-        Turn bits 6 and 7 of port extender 0 back on.
-    */
-
-    Shadows[0] |= 0xc0
-
-    mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 3 32 Volt Outputs 5 and 6 Passed.\r\n")
-    }
-  }
-  
-  Passed = 1
-
-  if err == nil {
-    NextBit := 0x20
-    ReturnBit := (byte) (0x01)
-
-    /*
-        Turn off bits 2 -> 5 of port extender 0 and wait 200 milliseconds.
-    */
-
-    Shadows[0] &= 0xffc3
-
-    mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    /*
-        For each bit turn one bit at a time and wait 200 milliseconds. Ask the IEM for digital inputs.
-        Check that the current bit is the only one on and then set the pass/fail bit. Write the test
-        result to the database.
-    */
-
-    for i := 0;i < 4;i++ {
-      Shadows[0] |= NextBit
-
-      mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-      start := time.Now()
-
-      t := time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      responseCount, err = InformationSelectionCommand( DIGITAL_INPUTS, 0, response )
-
-      if err != nil || responseCount != DIGITAL_INPUTS_RESPONSE_LEN {
-        RetValue = 0
-        Passed = 0
-
-        if err == nil {
-          err = errors.New("Bad Digital Inputs.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      Test := MatchTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 3 32 Volt Outputs (7 -> 10) Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Received = (int) (^response[8] & 0xf)
-      Test.Expected = (int) (ReturnBit)
-      Test.Pass = true
-
-      test := ^response[8] & 0x0f
-
-      if test != ReturnBit || err != nil {
-        fmt.Printf(" Test 3 32 Volt Output %d Failed (Got %X Expected %X)\r\n", i + 7, test, ReturnBit)
-
-        Test.Pass = false
-        RetValue = 0;
-        Passed = 0
-
-        if err != nil {
-          break
-        }
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      Shadows[0] &= ^NextBit
-
-      NextBit >>= 1
-
-      ReturnBit <<= 1
-    }
-
-    /*
-        Turn bits 2 -> 5 of port extender 0 back on.
-    */
-
-    Shadows[0] |= 0x3c
-
-    mcp[0].Write( (byte) (Shadows[0] & 0xff), MCP23S17.OLATA )
-
-    if Passed == 1 {
-      fmt.Printf(" Test 3 32 Volt Outputs 7 to 10 Passed.\r\n")
-    }
-
-    /*
-        Ask the IEM for the 80 Volt Analog Inputs.
-    */
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        For each of the seven inputs check the value against the proper range and set the pass/fail flag.
-        Write the test result to the data base.
-    */
-
-    for i := 0;i < 7;i++ {
-      counts := (int) (response[2*i + 3])
-
-      counts = (counts << 8) + (int) (response[2*i + 4])
-
-      Test := UpperLimitTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 4 80 Volt Analog Inputs (1 -> 7) Low Voltage Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.UpperLimit = 25
-      Test.Value = counts
-      Test.Pass = true
-
-      if counts > 25 {
-        fmt.Printf(" Test 4 80 Volt Analog Input %d Failed (Got %d Expected <= 25)\r\n", i + 1, counts)
-
-        Test.Pass = false
-        RetValue = 0
-        Passed = 0
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-    }
-
-    /*
-        Turn off bits 13 of port extender 3 and turn on bit 12 of port extender 3. Wait 200 milliseconds.
-        Ask the IEM for 80 Volt analog inputs.
-    */
-
-    Shadows[3] &= ^0x3000
-
-    Shadows[3] |= 0x1000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Inputs 1 to 7 Passed low voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and set the pass/fail flag. Write the test result to the 
-        database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 1 Medium Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 1 Failed (Got %d Expected > 441 and < 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    counts = (int) (response[9])
-
-    /*
-        Check input 4 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (counts << 8) + (int) (response[10])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 4 Medium Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 4 Failed (Got %d Expected > 441 and < 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Set bit 13 of port extender 3 and wait 200 milliseconds. Ask the IEM for 80 Volt Analog Inputs.
-    */
-
-    Shadows[3] |= 0x2000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-  
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Inputs 1 and 4 Passed the medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 1 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 1 Failed (Got %d Expected  > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 4 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[9])
-
-    counts = (counts << 8) + (int) (response[10])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 4 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 4 Failed (Got %d Expected  > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bits 12 and 13 of port extender 3 and turn on bit 11 of port extender 3. Wait 200 milliseconds and
-        ask the IEM for 80 Volt Analog Inputs.
-    */
-
-    Shadows[3] &= ^0x3800
-
-    Shadows[3] |= 0x800
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Inputs 1 and 4 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 2 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[5])
-
-    counts = (counts << 8) + (int) (response[6])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 2 Medium Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 2 Failed (Got %d Expected > 441 and < 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 3 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[7])
-
-    counts = (counts << 8) + (int) (response[8])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 3 Medium Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 3 Failed (Got %d Expected > 441 and < 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn on bit 12 of port extender 3 and wait 200 milliseconds. Ask the IEM for 80 Volt Analog Inputs.
-    */
-
-    Shadows[3] |= 0x2000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Inputs 2 and 3 Passed the medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 2 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[5])
-
-    counts = (counts << 8) + (int) (response[6])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 2 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 2 Failed (Got %d Expected > 2214 and < 2705)\r\n", counts)
-
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 3 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[7])
-
-    counts = (counts << 8) + (int) (response[8])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 3 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 3 Failed (Got %d Expected > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bits 11 -> 13 of port extender 3, turn on bit 10 of port extender 3, and wait 200 milliseconds. Ask the IEM for 80 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x3c00
-
-    Shadows[3] |= 0x400
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Inputs 2 and 3 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 6 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[13])
-
-    counts = (counts << 8) + (int) (response[14])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 6 Medium Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 6 Failed (Got %d Expected > 441 and > 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 7 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[15])
-
-    counts = (counts << 8) + (int) (response[16])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 7 Medium Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 7 Failed (Got %d Expected > 441 and > 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn on bit 13 of port extender 3 and wait 200 milliseconds. Ask the IEM for 80 Volt analog
-        inputs.
-    */
-
-    Shadows[3] |= 0x2000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Inputs 6 and 7 Passed the medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 6 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[13])
-
-    counts = (counts << 8) + (int) (response[14])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 6 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 6 Failed (Got %d Expected > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 7 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[15])
-
-    counts = (counts << 8) + (int) (response[16])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 7 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 7 Failed (Got %d Expected > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn bits 10 -> 13 of port extender 3, turn on bit 9 of port extender 3, and wait 200 milliseconds. Ask the IEM for 80 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x3e00
-
-    Shadows[3] |= 0x200
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Inputs 6 and 7 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 5 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[11])
-
-    counts = (counts << 8) + (int) (response[12])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 5 Medium Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 441
-    Test.MinValue = 538
-    Test.Pass = true
-
-    if counts <= 441 || counts >= 538 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 5 Failed (Got %d Expected > 441 and < 538)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn on bit 13 of port extender 3 and wait 200 milliseconds. Ask the IEM for 80 Volt analog
-        inputs.
-    */
-
-    Shadows[3] |= 0x2000
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-    
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_80V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 5 Passed the medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 5 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[11])
-
-    counts = (counts << 8) + (int) (response[12])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 4 80 Volt Analog Input 5 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 2214
-    Test.MinValue = 2705
-    Test.Pass = true
-
-    if counts <= 2214 || counts >= 2705 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 5 Failed (Got %d Expected > 2214 and < 2705)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bits 8 -> 13 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x3f00
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 4 80 Volt Analog Input 5 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        For inputs 1 -> 6, check the input for the proper range, set the pass/fail flag, and write the test
-        result to the database.
-    */
-
-    for i := 0;i < 6;i ++ {
-      counts := (int) (response[2*i + 3])
-
-      counts = (counts << 8) + (int) (response[2*i + 4])
-
-      Test := RangeTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 5 10 Volt Analog Input (1 -> 6) Medium Voltage Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.Value = counts
-      Test.MaxValue = 1995
-      Test.MinValue = 2205
-      Test.Pass = true
-
-      if counts <= 1995 || counts >= 2205 {
-        fmt.Printf(" Test 5 10 Volt Analog Input %d Failed (Got %d Expected > 1995 and < 2205)\r\n", i + 1, counts)
-
-        Test.Pass = false
-        RetValue = 0
-        Passed = 0
-      }
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-    }
-
-    /*
-        Turn on bits 7 and 8 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] |= 0x180
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 1 to 6 Passed the medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 1 Low Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 1 Failed (Got %d Expected > 475 and > 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 2 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[5])
-
-    counts = (counts << 8) + (int) (response[6])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 2 low Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 2 Failed (Got %d Expected > 475 and > 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 8 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x100
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 1 and 2 Passed the low voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Wrtie the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 1 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3040
-    Test.MinValue = 3716
-    Test.Pass = true
-
-    if counts <= 3040 || counts >= 3716 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 1 Failed (Got %d Expected > 3040 and < 3716)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check imput 2 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[5])
-
-    counts = (counts << 8) + (int) (response[6])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 2 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3040
-    Test.MinValue = 3716
-    Test.Pass = true
-
-    if counts <= 3040 || counts >= 3716 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 2 Failed (Got %d Expected > 3040 and < 3716)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 7 of port extender 3, turn on bits 8 and 6 of port extender 3, and wait 200 milliseconds.
-        Ask the IEM for 10 Volt analog inputs.
-    */
-
-    Shadows[3] &= ^0x80
-
-    Shadows[3] |= 0x140
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 1 and 2 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 3 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[7])
-
-    counts = (counts << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 3 Low Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 3 Failed (Got %d Expected > 475 and < 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 4 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[9])
-
-    counts = (counts << 8) + (int) (response[10])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 4 Low Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 4 Failed (Got %d Expected > 475 and < 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 8 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x100
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Print(" Test 5 10 Volt Analog Inputs 3 and 4 Passed the low voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 3 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[7])
-
-    counts = (counts << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 3 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3040
-    Test.MinValue = 3716
-    Test.Pass = true
-
-    if counts <= 3040 || counts >= 3716 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 3 Failed (Got %d Expecteed > 3040 and < 3716)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 4 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[9])
-
-    counts = (counts << 8) + (int) (response[10])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 4 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3325
-    Test.MinValue = 3765
-    Test.Pass = true
-
-    if counts <= 3325 || counts >= 3675 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 4 Failed (Got %d Expecteed > 3325 and < 3675)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 6 of port extender 3, turn on bit 5 of port extender 3, and wait 200 milliseconds. Ask the IEM
-        for 10 Volt analog inputs.
-    */
-
-    Shadows[3] &= ^0x60
-
-    Shadows[3] |= 0x20
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    Shadows[3] |= 0x100
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 3 an 4 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 5 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[11])
-
-    counts = (counts << 8) + (int) (response[12])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 5 Low Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 5 Failed (Got %d Expected > 475 and < 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 6 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[13])
-
-    counts = (counts << 8) + (int) (response[14])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 6 Low Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 475
-    Test.MinValue = 581
-    Test.Pass = true
-
-    if counts <= 475 || counts >= 581 {
-      fmt.Printf(" Test 5 10 Volt Analog Input 6 Failed (Got %d Expected > 475 and < 581)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 8 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x100
-
-    mcp[3].Write( (byte) ((Shadows[3] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_10V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 5 and 6 Passed the low voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 5 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[11])
-
-    counts = (counts << 8) + (int) (response[12])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 5 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3040
-    Test.MinValue = 3716
-    Test.Pass = true
-
-    if counts <= 3040 || counts >= 3716 {
-      fmt.Printf(" Test 5 10 Volt Analog Input Failed 5 (Got %d Expected > 3040 and < 3716)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check input 6 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts = (int) (response[13])
-
-    counts = (counts << 8) + (int) (response[14])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 5 10 Volt Analog Input 6 High Voltage Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3040
-    Test.MinValue = 3716
-    Test.Pass = true
-
-    if counts <= 3040 || counts >= 3716 {
-      fmt.Printf(" Test 5 10 Volt Analog Input Failed 6 (Got %d Expected > 3040 and < 3716)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bits 3 -> 5 of port extender 3 and wait 200 milliseconds. Ask the IEM for 10 Volt
-        analog inputs.
-    */
-
-    Shadows[3] &= ^0x38
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-    
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_16V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 5 10 Volt Analog Inputs 5 and 6 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := UpperLimitTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 6 16 Volt Analog Input 1 Low Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.UpperLimit = 125
-    Test.Pass = true
-
-    if counts > 125 {
-      fmt.Printf(" Test 6 16 Volt Ananlog Input 1 Failed (Got %d Expected < 125)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn on bit 3 of port extender 3 and wait 200 milliseconds. Ask the IEM for 16 Volt
-        analog inputs.
-    */
-
-    Shadows[3] |= 0x08
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-    
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_16V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 6 16 Volt Analog Input 1 Passed the low voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 6 16 Volt Analog Input 1 Medium Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 450
-    Test.MinValue = 550
-    Test.Pass = true
-
-    if counts <= 450 || counts >= 550 {
-      fmt.Printf(" Test 6 16 Volt Analog Input 1 Failed (Got %d Expected > 475 and < 525)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-       Turn on bit 4 of port extender 3 and wait 200 milliseconds. Ask the IEM for 16 Volt
-       analog inputs.
-    */
-
-    Shadows[3] |= 0x10
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-    
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( ANALOG_INPUTS, ANALOG_16V_INPUTS_32, response )
-
-    if err != nil || responseCount != ANALOG_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 6 16 Volt Analog Input 1 Passed medium voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check input 1 for the proper range and then set the pass/fail flag. Write the test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 6 16 Volt Analog Input 1 High Voltage Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3060
-    Test.MinValue = 3740
-    Test.Pass = true
-
-    if counts <= 3060 || counts >= 3740 {
-      fmt.Printf(" Test 6 16 Volt Analog Input 1 Failed (Got %d Expected > 6821 and < 7539)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn off bit 4 of port extender 3 and wait 200 milliseconds.
-    */
-
-    Shadows[3] &= ^0x10
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(200)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    if Passed == 1 {
-      fmt.Printf(" Test 6 16 Volt Analog Input 1 Passed the high voltage test.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Ask the user to set his pressure regulator to proper setting.
-    */
-
-    fmt.Printf("\r\nPlease adjust the pressure regulator to 30 PSI.\r\n")
-
-    fmt.Printf("Hit any key when ready.\r\n")
-
-    char := (byte) (0)
-
-    err1 := (error) (nil)
-
-    char, err1 = ConsoleInput.ReadByte()
-
-    for err1 != nil && char == 0 {
-      char, err1 = ConsoleInput.ReadByte()
-    }
-
-    ConsoleInput.Reset(os.Stdin)
-
-    /*
-        Ask the user if he wants to run the pressure tests.
-    */
-
-    if group == 1 {
-      fmt.Printf("\r\nDo you wish to run Pressure Tests y or n (y default) : ")
-    } else {
-      fmt.Printf("\r\nDo you wish to test PT1 y or n (y default) : ")
-    }
-
-    char = (byte) (0)
-
-    err1 = (error) (nil)
-
-    char, err1 = ConsoleInput.ReadByte()
-
-    for err1 != nil && char == 0 {
-      char, err1 = ConsoleInput.ReadByte()
-    }
-
-    ConsoleInput.Reset(os.Stdin)
-
-    /*
-        If he doesn't want to run the pressure tests, then set the skip flag.
-    */
-
-    if char == 'n' {
-      skip = 1
-    } 
-
-    /*
-        Ask the IEM for an initial pressure reading to elimate and zero pressure
-        offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-       Tell the user to apply pressure to PT1. He's got thirty seconds to do it.
-       If we detect the correct pressure within that 30 seconds, then we move on.
-       If we don't detect the correct pressure within the 30 seconds, then we
-       set the pass/fail flag.
-    */
-
-    fmt.Printf("\r\nApply 30 PSI to IEM pressure input port PT1.\r\n")
-
-    zeroPressureReading = (int) (response[3])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[4])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if i >= 150 || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT1 Failed (Got %d Expected > 589 and < 721)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-        }
-
-        break
-      } else {
-        counts = (int) (response[3])
-
-        counts = (counts << 8) + (int) (response[4])
-
-        if (counts - zeroPressureReading) >= 589 && (counts - zeroPressureReading) <= 721 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT1 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 589
-    Test.MinValue = 721
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT1 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT2 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure inputs to use to eliminate
-        any zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT2.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT2.\r\n")
-
-    zeroPressureReading = (int) (response[5])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[6])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has 30 seconds to apply pressure to PT2. If we detect the
-        correct pressure within the 30 seconds, then we move on. If we don't
-        detect the correct pressure within the 30 second period, then we the 
-        set the pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT2 Failed (Got %d Expected > 589 and < 721)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[5])
-
-        counts = (counts << 8) + (int) (response[6])
-
-        if counts - zeroPressureReading >= 589 && counts - zeroPressureReading <= 721 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT2 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 589
-    Test.MinValue = 721
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT2 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT3 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure readings to use to eliminate any
-        zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT3.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT3.\r\n")
-
-    zeroPressureReading = (int) (response[7])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[8])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has thirty seconds to apply the correct pressure to PT3. If we
-        see the correct pressure on PT3 within the 30 second period, then we move
-        on. If we don't see the correct pressure within the thirty second period
-        then we set the pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT3 Failed (Got %d Expected > 589 and < 721)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[7])
-
-        counts = (counts << 8) + (int) (response[8])
-
-        if counts - zeroPressureReading >= 589 && counts - zeroPressureReading <= 721 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT3 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 589
-    Test.MinValue = 721
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT3 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT4 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure readings that we can use to eliminate any
-        zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT4.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT4.\r\n")
-
-    zeroPressureReading = (int) (response[9])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[10])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has 30 seconds to apply the correct pressure to PT4. If we detect
-        correct pressure before the end of the 30 second period then we move on.
-        If we don't detect the correct pressure, then we set the pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT4 Failed (Got %d Expected > 589 and < 721)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[9])
-
-        counts = (counts << 8) + (int) (response[10])
-
-        if counts - zeroPressureReading >= 589 && counts - zeroPressureReading <= 721 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT4 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 589
-    Test.MinValue = 721
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT4 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT5 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure readings that we can use to eliminate any
-        zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT5.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT5.\r\n")
-
-    zeroPressureReading = (int) (response[11])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[12])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has 30 seconds to apply correct pressure to PT5. If we detect the
-        correct pressure withing the 30 second period, then we move on. If we do
-        not detect the correct pressure within the 30 second period then we set the
-        pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT5 Failed (Got %d Expected > 589 and < 721)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[11])
-
-        counts = (counts << 8) + (int) (response[12])
-
-        if counts - zeroPressureReading >= 442 && counts - zeroPressureReading <= 541 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT5 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 589
-    Test.MinValue = 721
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT5 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT6 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-       Ask the IEM for an initial set of pressure readings that we can use to eliminate any
-       zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT8.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT6.\r\n")
-
-    zeroPressureReading = (int) (response[13])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[14])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has 30 seconds to apply the correct pressure to PT6. If we detect
-        the correct pressure within the 30 second period, then we move on. If we
-        do not detect the correct pressure within the 30 second period, then we 
-        set the pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT6 Failed (Got %d Expected >= 4000)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[13])
-
-        counts = (counts << 8) + (int) (response[14])
-
-        if counts - zeroPressureReading >= 4000 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Wrtie the test result to the database.
-    */
-
-    Test := LowerLimitTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT6 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.LowerLimit = 4000
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT6 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-  
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT7 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure readings that we can user to eliminate any
-        zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply pressure to PT7.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT7.\r\n")
-
-    zeroPressureReading = (int) (response[15])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[16])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := 0
-
-    /*
-        The user has 30 seconds to apply the correct pressure to PT7. If we see 
-        the correct pressure within the 30 second period, then we move on. If we
-        do not see the correct pressure within the 30 second period, the we set
-        the pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Preesure Input PT7 Failed (Got %d Expected >= 4000)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[15])
-
-        counts = (counts << 8) + (int) (response[16])
-
-        if counts - zeroPressureReading >= 4000 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(200)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := LowerLimitTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT7 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.LowerLimit = 4000
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT7 Passed.\r\n")
-    } else {
-      Test.Pass = false
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  Passed = 1
-
-  if group == 0 {
-    skip = 0
-  }
-
-  if err == nil {
-    if group == 0 {
-      ConsoleInput.Reset(os.Stdin)
-
-      fmt.Printf("\r\nDo you wish to test PT8 y or n (y default) : ")
-
-      char := (byte) (0)
-
-      err1 := (error) (nil)
-
-      char, err1 = ConsoleInput.ReadByte()
-
-      for err1 != nil && char == 0 {
-        char, err1 = ConsoleInput.ReadByte()
-      }
-
-      ConsoleInput.Reset(os.Stdin)
-
-      if char == 'n' {
-        skip = 1
-      } 
-    }
-
-    /*
-        Ask the IEM for an initial set of pressure readings that we can use to eliminate any
-        zero pressure offset.
-    */
-
-    responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-    if err != nil || responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Analog Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil && skip == 0 {
-    /*
-        Tell the user to apply the correct pressure to PT8.
-    */
-
-    fmt.Printf("\r\n\nApply 30 PSI to IEM pressure input port PT8.\r\n")
-
-    zeroPressureReading = (int) (response[17])
-
-    zeroPressureReading = (zeroPressureReading << 8) + (int) (response[18])
-
-    start := time.Now()
-
-    t := time.Now()
-
-    i := 0
-
-    counts := (int) (0)
-
-    /*
-        The user has 30 seconds to apply the correct pressure to PT8. If we detect the
-        correct pressure within the 30 second period, then we move on. If we do not 
-        detect the correct pressure within the 30 second period, then we set the 
-        pass/fail flag.
-    */
-
-    for err == nil {
-      responseCount, err = InformationSelectionCommand( PRESSURE_INPUTS, 0, response )
-
-      if (i >= 150) || (err != nil) {
-        RetValue = 0
-        Passed = 0
-
-        fmt.Printf("\r Test 7 Pressure Input PT8 Failed (Got %d Expected >= 4000)\r\n", counts - zeroPressureReading)
-
-        if err == nil && responseCount != PRESSURE_INPUTS_RESPONSE_LEN {
-          err = errors.New("Bad Pressure Inputs")
-
-          log.Printf("%q", err)
-        }
-
-        break
-      } else {
-        counts = (int) (response[17])
-
-        counts = (counts << 8) + (int) (response[18])
-
-        if counts - zeroPressureReading >= 4000 {
-          break
-        }
-      }
-
-      t = time.Now()
-
-      elapsed := t.Sub(start)
-
-      for elapsed < time.Duration(500)*time.Millisecond {
-        t = time.Now()
-
-        elapsed = t.Sub(start)
-      } 
-
-      start = t
-
-      i += 1
-
-      fmt.Printf("\r%2d", (30 - i/5))
-    } 
-
-    /*
-        Write the test result to the database.
-    */
-
-    Test := LowerLimitTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 7 Pressure Input PT8 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.LowerLimit = 4000
-    Test.Pass = true
-
-    if Passed == 1 {
-      fmt.Printf("\r\n Test 7 Pressure Input PT8 Passed.\r\n")
-    } else {
-      Test.Pass = true
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  if err == nil {
-    /*
-        Turn off bit 2 of port extender 3, tell the IEM to set the 
-        zero crossing reference to 0 volts, and wait a second.
-    */
-
-    Shadows[3] &= ^0x04
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    responseCount, err = SetSpeedSensorRefCommand( ZERO_CROSSING_0V )
-
-    if err != nil || responseCount != 1 {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Speed Sensor Reference Error.")
-      }
-    }
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(1)*time.Second {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Ask the IEM for speed sensor inputs.
-    */
-
-    responseCount, err = InformationSelectionCommand( SPEED_SENSOR_INPUTS, 0, response )
-
-    if err != nil || responseCount != SPEED_SENSOR_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Speed Sensor Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-       Check the speed sensor input for the proper range and then set the pass/fail flag. Write the
-       test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 8 Speed Sensor Input for 0 Volt Crossings Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 102
-    Test.MinValue = 98
-    Test.Pass = true
-
-    if counts <= 98 || counts >= 102 {
-      fmt.Printf(" Test 8 Speed Sensor Input for 0 Volt Coroosings Failed (Got %d Expected > 98 and < 102)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-       Turn on bit 2 of port extender 3, tell the IEM to set the zero crossing
-       detector reference to 2.5 Volts, and wait 500 milliseconds.
-    */
-
-    Shadows[3] |= 0x04
-
-    mcp[3].Write( (byte) (Shadows[3] & 0xff), MCP23S17.OLATA )
-
-    responseCount, err = SetSpeedSensorRefCommand( ZERO_CROSSING_2_5V )
-
-    if err != nil || responseCount != 1 {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Speed Sensor Reference Error.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(500)*time.Millisecond {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    if Passed == 1 {
-      fmt.Printf(" Test 8 Speed Sensor Input for 0 Volt Crossings Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Ask the IEM for speed sensor inputs.
-    */
-
-    responseCount, err = InformationSelectionCommand( SPEED_SENSOR_INPUTS, 0, response )
-
-    if err != nil || responseCount != SPEED_SENSOR_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Speed Sensor Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-        Check the speed sensor input for the proper range and then set the pass/fail flag. Write the
-        test result to the database.
-    */
-
-    counts := (int) (response[3])
-
-    counts = (counts << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 8 Speed Sensor Input for 2.5 Volt Crossings Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 102
-    Test.MinValue = 98
-    Test.Pass = true
-
-    if counts <= 98 || counts >= 102 {
-      fmt.Printf(" Test 8 Speed Sensor Input for 2.5 Volt crossings Failed (Got %d Expected > 98 and < 102)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Turn bit 13 of port extender 0, wait a second, and ask the IEM for 4-20 mA
-        inputs.
-    */
-
-    Shadows[0] |= 0x2000
-
-    mcp[0].Write( (byte) ((Shadows[0] >> 8) & 0xff), MCP23S17.OLATB )
-
-    start := time.Now()
-
-    t := time.Now()
-
-    elapsed := t.Sub(start)
-
-    for elapsed < time.Duration(2)*time.Second {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    } 
-
-    responseCount, err = InformationSelectionCommand( CUR_4_20MA_INPUTS, 0, response )
-
-    if err != nil || responseCount != CUR_4_20MA_INPUTS_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad 4-20 Ma Inputs.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 8 Speed Sensor Input for 2.5 Volt crossings Passed.\r\n")
-    }
-  }
-
-  Passed = 1
-
-  if err == nil {
-    /*
-        Check channel 8 for the proper range and then set the pass/fail flag. Write the
-        test result to the database
-    */
-
-    counts := (int) (response[17])
-
-    counts = (counts << 8) + (int) (response[18])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 9 4-20 mA Channel 8 Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = counts
-    Test.MaxValue = 3708
-    Test.MinValue = 3492
-    Test.Pass = true
-
-    if counts <= 3492 || counts >= 3708 {
-      fmt.Printf(" Test 9 4-20 mA Channel 8 Failed (Got %d Expected > 3492 and < 3708)\r\n", counts)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-       Ask the IEM for status vector for the communications and I/O processors.
-    */
-
-    responseCount, err = InformationSelectionCommand( STATUS_VECTOR, COMM_PROC_AND_IO_PROC_12, response )
-
-    if err != nil || responseCount != STATUS_VECTOR_RESPONSE_LEN {
-      RetValue = 0
-      Passed = 0
-
-      if err == nil {
-        err = errors.New("Bad Status Vector.")
-
-        log.Printf("%q", err)
-      }
-    }
-
-    if Passed == 1 {
-      fmt.Printf(" Test 9 Channel 1 Passed.\r\n")
-    }
-  }
-
-  fmt.Printf("\r\n")
-
-  if err == nil {
-    /*
-        Check the post status of the flash test and set the pass/fail flag. Write the
-        test result to the database.
-    */
-
-    Test := PassFailTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 10 Flash Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Pass = true
-
-    if (response[3] & FLASH_TEST_PASSED_01) != FLASH_TEST_PASSED_01 {
-      Test.Pass = false
-      RetValue = 0
-      
-      fmt.Printf("IEM Main CPU Flash Test Failed.\r\n")
-    } else {
-      fmt.Printf("IEM Main CPU Flash Test Passed.\r\n")
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    /*
-        Check the post status of the communications check and then set the pass/fail
-        flag. Write the test result to the database.
-    */
-
-    Test = PassFailTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 13 Communication Check Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Pass = true
-
-    if (response[3] & CP_TO_SP_LINK_ACTIVE_01) != CP_TO_SP_LINK_ACTIVE_01 {
-      Test.Pass = false
-      RetValue = 0
-      
-      fmt.Printf("IEM Main CPU Communication Check Failed.\r\n")
-    } else {
-      
-      fmt.Printf("IEM Main CPU Communication Check Passed.\r\n")
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  if err == nil {
-    /*
-        Ask the IEM for the hardware version of the communications processor.
-    */
-
-    responseCount, err = InformationSelectionCommand( HW_VERSION, COMM_PROC_02, response )
-
-    if err != nil || responseCount != HW_VERSION_RESPONSE_LEN {
-      RetValue = 0
-
-      if err == nil {
-        err = errors.New("Bad Hardware Version.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-        Write the harware version of the communication processor to the database.
-    */
-
-    Test := HardwareVersionTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 11 Communication Processor Hardware Version Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.HardwareVersion = (int) (response[3])
-    Test.Pass = true
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    fmt.Printf("Hardware Version for Communications Processor %d\r\n", response[3])
-
-    /*
-        Ask IEM for the hardware version of the I/O processor.
-    */
-
-    responseCount, err = InformationSelectionCommand( HW_VERSION, IO_PROCESSOR_02, response )
-
-    if err != nil || responseCount != HW_VERSION_RESPONSE_LEN {
-      RetValue = 0
-
-      if err == nil {
-        err = errors.New("Bad Hardware Version.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-   /*
-        Write the hardware version for the I/O processor to the database.
-   */
-
-
-    Test := HardwareVersionTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 11 IO Processor Hardware Version Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.HardwareVersion = (int) (response[3])
-    Test.Pass = true
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    fmt.Printf("Hardware Version for IO Processor %d\r\n", response[3])
-
-    if IEMType == IEM_WITH_ALERTER {
-      /*
-          Ask the IEM for the hardware version of the ABCM.
-      */
-
-      responseCount, err = InformationSelectionCommand( HW_VERSION, ABCM_02, response )
-
-      if err != nil || responseCount != HW_VERSION_RESPONSE_LEN {
-        RetValue = 0
-
-        if err == nil {
-          err = errors.New("Bad Hardware Version.")
-
-          log.Printf("%q", err)
-        }
-      }
-    }
-  }
-
-  if err == nil {
-    if IEMType == IEM_WITH_ALERTER {
-      /*
-          Write the hardware version of the ABCM to the database.
-      */
-
-      Test := HardwareVersionTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 11 ABCM Hardware Version Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.HardwareVersion = (int) (response[3])
-      Test.Pass = true
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      fmt.Printf("Hardware Version for ABCM %d\r\n", response[3])
-
-      /*
-          Ask the IEM for the hardware version of the ADCM.
-      */
-
-      responseCount, err = InformationSelectionCommand( HW_VERSION, ADCM_02, response )
-
-      if err != nil || responseCount != HW_VERSION_RESPONSE_LEN {
-        RetValue = 0
-
-        if err == nil {
-          err = errors.New("Bad Hardware Version.")
-
-          log.Printf("%q", err)
-        }
-      }
-    }
-  }
-
-  if err == nil {
-    if IEMType == IEM_WITH_ALERTER {
-      /*
-          Write the hardware version of the ADCM to the database.
-      */
-
-      Test := HardwareVersionTestResult{}
-
-      Test.AssemblyPartNumber = AssemblyPartNumber
-      Test.AssemblySerialNumber = AssemblySerialNumber
-      Test.PartNumber = MainCircuitPartNumber
-      Test.SerialNumber = MainCircuitSerialNumber
-      Test.TestName = "Main CPU Test 11 ADCM Hardware Version Test"
-
-      timeDate := time.Now()
-      hour, min, sec := timeDate.Clock()
-      year, month, day := timeDate.Date()
-
-      Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-      Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-      Test.HardwareVersion = (int) (response[3])
-      Test.Pass = true
-
-      Test.SetID(couchdb.GenerateUUID())
-
-      err = couchdb.Store( TestDB, &Test )
-
-      fmt.Printf("Hardware Version for ADCM %d\r\n", response[3])
-    }
-
-    /*
-        Ask the IEM for the software version of the communications processor.
-    */
-
-    responseCount, err = InformationSelectionCommand( SW_VERSION, COMM_PROC_01, response )
-
-    if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
-      RetValue = 0
-
-      if err == nil {
-        err = errors.New("Bad Software Version.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-        Write the software version of the communications processor to the database.
-    */
-
-    Test := SoftwareVersionTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 12 Communications Processor Software Version Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-    version := (string) (response[3:3 + index])
-    Test.SoftwareVersion = version
-    Test.Pass = true
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    fmt.Printf("Software Version for Communications Processor %s\r\n", response[3:responseCount - 3])
-
-    /*
-        Ask the IEM for the software version of the I/O processor.
-    */
-
-    responseCount, err = InformationSelectionCommand( SW_VERSION, IO_PROCESSOR_01, response )
-
-    if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
-      RetValue = 0
-
-      if err == nil {
-        err = errors.New("Bad Hardware Version.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-        Write the software version of the I/O processor to the database.
-    */
-
-    Test := SoftwareVersionTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 12 IO Processor Software Version Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-    version := (string) (response[3:3 + index])
-    Test.SoftwareVersion = version
-    Test.Pass = true
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-
-    fmt.Printf("Software Version for IO Processor %s\r\n", response[3:responseCount - 3])
-
-    if IEMType == IEM_WITH_ALERTER {
-      /*
-          Ask the IEM for the software version of the ADCM.
-      */
-
-      responseCount, err = InformationSelectionCommand( SW_VERSION, ADCM_01, response )
-
-      if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
-        RetValue = 0
-
-        if err == nil {
-          err = errors.New("Bad Software Version.")
-
-          log.Printf("%q", err)
-        }
-      }
-
-      if err == nil {
-        /*
-            Write the software version of the ADCM to the database.
-        */
-
-        Test := SoftwareVersionTestResult{}
-
-        Test.AssemblyPartNumber = AssemblyPartNumber
-        Test.AssemblySerialNumber = AssemblySerialNumber
-        Test.PartNumber = MainCircuitPartNumber
-        Test.SerialNumber = MainCircuitSerialNumber
-        Test.TestName = "Main CPU Test 12 ADCM Software Version Test"
-
-        timeDate := time.Now()
-        hour, min, sec := timeDate.Clock()
-        year, month, day := timeDate.Date()
-
-        Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-        Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-        index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-        version := (string) (response[3:3 + index])
-        Test.SoftwareVersion = version
-        Test.Pass = true
-
-        Test.SetID(couchdb.GenerateUUID())
-
-        err = couchdb.Store( TestDB, &Test )
-
-        fmt.Printf("Software Version for ADCM Processor %s\r\n", response[3:responseCount - 3])
-
-        /*
-            Ask the IEm for the softwar version of the ABCM processor A.
-        */
-
-        responseCount, err = InformationSelectionCommand( SW_VERSION, ABCM_PROC_A_01, response )
-
-        if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
-          RetValue = 0
-
-          if err == nil {
-            err = errors.New("Bad Software Version.")
-
-            log.Printf("%q", err)
-          }
-        }
-      }
-
-      if err == nil {
-        /*
-            Write the software version of the ABCM processor A to the database.
-        */
-
-        Test := SoftwareVersionTestResult{}
-
-        Test.AssemblyPartNumber = AssemblyPartNumber
-        Test.AssemblySerialNumber = AssemblySerialNumber
-        Test.PartNumber = MainCircuitPartNumber
-        Test.SerialNumber = MainCircuitSerialNumber
-        Test.TestName = "Main CPU Test 12 ABCM Processor A Software Version Test"
-
-        timeDate := time.Now()
-        hour, min, sec := timeDate.Clock()
-        year, month, day := timeDate.Date()
-
-        Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-        Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-        index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-        version := (string) (response[3:3 + index])
-        Test.SoftwareVersion = version
-        Test.Pass = true
-
-        Test.SetID(couchdb.GenerateUUID())
-
-        err = couchdb.Store( TestDB, &Test )
-
-        fmt.Printf("Software Version for ABCM Processor A %s\r\n", response[3:responseCount - 3])
-
-        /*
-            Ask the IEM for the software version of the ABCM processor B.
-        */
-
-        responseCount, err = InformationSelectionCommand( SW_VERSION, ABCM_PROC_B_01, response )
-
-        if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
-          RetValue = 0
-
-          if err == nil {
-            err = errors.New("Bad Software Version.")
-
-            log.Printf("%q", err)
-          }
-        }
-      }
-
-      if err == nil {
-        /*
-            Write the software version of the ABCM processor B to the database.
-        */
-
-        Test := SoftwareVersionTestResult{}
-
-        Test.AssemblyPartNumber = AssemblyPartNumber
-        Test.AssemblySerialNumber = AssemblySerialNumber
-        Test.PartNumber = MainCircuitPartNumber
-        Test.SerialNumber = MainCircuitSerialNumber
-        Test.TestName = "Main CPU Test 12 ABCM Processor B Software Version Test"
-
-        timeDate := time.Now()
-        hour, min, sec := timeDate.Clock()
-        year, month, day := timeDate.Date()
-
-        Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-        Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-        index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-        version := (string) (response[3:3 + index])
-        Test.SoftwareVersion = version
-        Test.Pass = true
-
-        Test.SetID(couchdb.GenerateUUID())
-
-        err = couchdb.Store( TestDB, &Test )
-
-        fmt.Printf("Software Version for ABCM Processor B %s\r\n", response[3:responseCount - 3])
-      }
-    }
-  }
-
-  
-  if err == nil {
-    /*
-        Ask the IEM for network addresses.
-    */
-
-    responseCount, err = InformationSelectionCommand( NETWORK_INTERFACE_PARAMS, 0, response )
-
-    if err != nil || responseCount != NETWORK_INTERFACE_PARAMS_RESPONSE_LEN {
-      RetValue = 0
-
-      if err == nil {
-        err = errors.New("Bad Network Parameters.")
-
-        log.Printf("%q", err)
-      }
-    }
-  }
-
-  if err == nil {
-    /*
-        Extract the network address and ping that address.
-    */
-
-    address := fmt.Sprintf("%d.%d.%d.%d\r\n", response[3], response[4], response[5], response[6])
-
-    out, _ := exec.Command("ping", address, "-c 3", "-w 3").Output()
-
-    fmt.Printf("%s", out)
-
-    /*
-       Check the results of the ping for all responses received and then set the pass/fail flag.
-       Write the test result to the database.
-    */
-
-    Test := PassFailTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = MainCircuitPartNumber
-    Test.SerialNumber = MainCircuitSerialNumber
-    Test.TestName = "Main CPU Test 15 Network Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Pass = true
-
-    if !(strings.Contains(string(out), "0% packet loss")) {
-      Test.Pass = false
-      RetValue = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-  }
-
-  /*
-       Set the IEm tester back to its initial condition.
-  */
-
-  _, err1 := Init( mcp )
-
-  if err == nil {
-    err = err1
-  }
-
-  return RetValue, err
-}
-
-/*
-    Procedure Name : Test_ABCM
-
-    Description    : This routine performs all of the ABCM tests.
-
-    Arguments      : mcp - Slice of pointers for the port extender structures
-
-    Return Value   : Pass/Fail flag
-                     Any error encountered during the tests.
-*/
-
-func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
-  var err error = nil
-  var responseCount int
-
-  response := make([] byte, 50)
-
-  RetValue := 1
-  Passed := 1
-
-  /*
-      Wait two seconds and then ask the IEM for ABCM Monitored Voltages.
-  */
-
-  start := time.Now()
-
-  t := time.Now()
-
-  elapsed := t.Sub(start)
-
-  for elapsed < time.Duration(2)*time.Second {
-    t = time.Now()
-
-    elapsed = t.Sub(start)
-  } 
-
-  responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ABCM_03, response )
-
-  if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
-    RetValue = 0
-
-    if err == nil {
-      err = errors.New("Bad Monitored Voltages.")
-
-      log.Printf("%q", err)
-    }
-  }
-
-  if err == nil {
-    /*
-        Check the 12 Volt entry for the proper range and then set the pass/fail flag. Write the
-        test result to the database.
-    */
-
-    voltage := (int) (response[3])
-
-    voltage = (voltage << 8) + (int) (response[4])
-
-    Test := RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = ABCMPartNumber
-    Test.SerialNumber = ABCMSerialNumber
-    Test.TestName = "ABCM Test 1 12 Volt Test"
-
-    timeDate := time.Now()
-    hour, min, sec := timeDate.Clock()
-    year, month, day := timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 11400
-    Test.MinValue = 12600
-    Test.Pass = true
-
-    if voltage <= 11400 || voltage >= 12600 {
-      fmt.Printf(" Test 1 12 Volts Failed (Got %d Expected > 11400 and < 12600)\r\n", voltage)
-
-      Test.Pass = false
-      RetValue = 0
-      Passed = 0
-    }
-
-    Test.SetID(couchdb.GenerateUUID())
-
-    err = couchdb.Store( TestDB, &Test )
-   
-    /*
-        Check the 3.3 Volt entry for the proper range and then set the pass/fail flag. Write the
-        test result to the database.
-    */
-
-    voltage = (int) (response[5])
-
-    voltage = (voltage << 8) + (int) (response[6])
-
-    Test = RangeTestResult{}
-
-    Test.AssemblyPartNumber = AssemblyPartNumber
-    Test.AssemblySerialNumber = AssemblySerialNumber
-    Test.PartNumber = ABCMPartNumber
-    Test.SerialNumber = ABCMSerialNumber
-    Test.TestName = "ABCM Test 1 3.3 Volt Test"
-
-    timeDate = time.Now()
-    hour, min, sec = timeDate.Clock()
-    year, month, day = timeDate.Date()
-
-    Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
-    Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 3135
-    Test.MinValue = 3465
-    Test.Pass = true
-
-    if voltage <= 3135 || voltage >= 3465 {
-      fmt.Printf(" Test 1 3.3 Volts Failed (Got %d Expected > 3135 and < 3465)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 1 3.3 Volts Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -7716,7 +2228,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Wait a second and then ask the IEM for the ABCM status vector.
@@ -7736,6 +2248,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( STATUS_VECTOR, ABCM_12, response )
 
+    if err == nil {
+      statusVector, err = ParseStatusVector( response, responseCount )
+    }
+
     if err != nil || responseCount != STATUS_VECTOR_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -7759,7 +2275,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Check the 74 Volts detected status and then set the pass/fail flag. Write the test result to the database.
     */
 
-    Test := PassFailTestResult{}
+    Test := NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -7775,7 +2291,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
     Test.Pass = true
 
-    if (response[3] & DETECTED_74V) != DETECTED_74V {
+    if !statusVector.Detected74V {
       Test.Pass = false
       RetValue = 0
       Passed = 0
@@ -7787,14 +2303,14 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Check the status of the post flash test and then set the pass/fail flag.
         Write the test result to the database.
     */
 
-    Test = PassFailTestResult{}
+    Test = NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -7810,7 +2326,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
     Test.Pass = true
 
-    if (response[3] & FLASH_TEST_PASSED_04) != FLASH_TEST_PASSED_04 {
+    if !statusVector.FlashTestPassed {
       Test.Pass = false
       RetValue = 0
       Passed = 0
@@ -7822,18 +2338,18 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Check the A to B communications check status and then set the pass/fail flag.
         Write the test result to the database.
     */
 
-    Test = PassFailTestResult{}
+    Test = NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -7849,7 +2365,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
     Test.Pass = true
 
-    if (response[3] & A_TO_B_COMM_LINK_ACTIVE) != A_TO_B_COMM_LINK_ACTIVE {
+    if !statusVector.AToBCommLinkActive {
       Test.Pass = false
       RetValue = 0
       Passed = 0
@@ -7861,14 +2377,14 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Check that both the A and B mag valve drives are currently off and the
         set the pass/fail flag. Write the test result to the database.
     */
 
-    Test1 := MatchTestResult{}
+    Test1 := NewResult( results.MATCH )
 
     Test1.AssemblyPartNumber = AssemblyPartNumber
     Test1.AssemblySerialNumber = AssemblySerialNumber
@@ -7882,12 +2398,12 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test1.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test1.Received = (int) (response[3] & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE))
-    Test1.Expected = 0
+    Test1.Value = results.Int( (int) (statusVector.Raw & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE)) )
+    Test1.Expected = results.Int( 0 )
     Test1.Pass = true
 
-    if (response[3] & ABCM_A_MAG_VALVE_DRIVE) == ABCM_A_MAG_VALVE_DRIVE ||
-       (response[3] & ABCM_B_MAG_VALVE_DRIVE) == ABCM_B_MAG_VALVE_DRIVE {
+    if statusVector.ABCMAMagValveDrive ||
+       statusVector.ABCMBMagValveDrive {
 
       Test.Pass = false
       RetValue = 0
@@ -7896,7 +2412,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test1 )
+    SaveResult( &Test1 )
 
     /*
         Ask the IEM for the hardware version of the ABCM.
@@ -7904,6 +2420,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( HW_VERSION, ABCM_02, response )
 
+    if err == nil {
+      hardware, err = ParseHardwareVersion( response, responseCount )
+    }
+
     if err != nil || responseCount != HW_VERSION_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -7925,9 +2445,9 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the ABCM hardware version to the database.
     */
 
-    HardwareVersion := (int) (response[3])
+    HardwareVersion := (int) (hardware.Version)
 
-    Test := HardwareVersionTestResult{}
+    Test := NewResult( results.HARDWARE_VERSION )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -7941,12 +2461,12 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.HardwareVersion = HardwareVersion
+    Test.Value = results.Int( HardwareVersion )
     Test.Pass = true
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     fmt.Printf("ABCM Hardware Version %d\r\n", HardwareVersion )
 
@@ -7956,6 +2476,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( SW_VERSION, ABCM_PROC_A_01, response )
 
+    if err == nil {
+      software, err = ParseSoftwareVersion( response, responseCount )
+    }
+
     if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -7973,7 +2497,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the software version for the ABCM processor A to the database.
     */
 
-    Test := SoftwareVersionTestResult{}
+    Test := NewResult( results.SOFTWARE_VERSION )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -7987,16 +2511,15 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-    version := (string) (response[3:3 + index])
-    Test.SoftwareVersion = version
+    version := software.Version
+    Test.Text = version
     Test.Pass = true
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
-    fmt.Printf("Software Version for ABCM Processor A %s\r\n", response[3:responseCount - 3])
+    fmt.Printf("Software Version for ABCM Processor A %s\r\n", software.Version)
 
     /*
         Ask the IEM for the software version of the ABCM processor B.
@@ -8004,6 +2527,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( SW_VERSION, ABCM_PROC_B_01, response )
 
+    if err == nil {
+      software, err = ParseSoftwareVersion( response, responseCount )
+    }
+
     if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -8021,7 +2548,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the software version of the ABCM processor B to the database.
     */
 
-    Test := SoftwareVersionTestResult{}
+    Test := NewResult( results.SOFTWARE_VERSION )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8035,16 +2562,15 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-    version := (string) (response[3:3 + index])
-    Test.SoftwareVersion = version
+    version := software.Version
+    Test.Text = version
     Test.Pass = true
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
-    fmt.Printf("Software Version for ABCM Processor B %s\r\n", response[3:responseCount - 3] )
+    fmt.Printf("Software Version for ABCM Processor B %s\r\n", software.Version )
 
     /*
         Set the A mag drive signal high and the B mag drive signal low on the ABCM and
@@ -8075,6 +2601,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( STATUS_VECTOR, ABCM_12, response )
 
+    if err == nil {
+      statusVector, err = ParseStatusVector( response, responseCount )
+    }
+
     if err != nil || responseCount != STATUS_VECTOR_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -8095,12 +2625,12 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         is low and set the pass/fail flag. Write the test result to the database.
     */
 
-    status := (int) (response[3])
+    status := (int) (statusVector.Raw)
 
     const ABCM_A_MAG_VALVE_DRIVE   = 0x04
     const ABCM_B_MAG_VALVE_DRIVE   = 0x08
     
-    Test := MatchTestResult{}
+    Test := NewResult( results.MATCH )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8114,8 +2644,8 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Received = (int) (response[3] & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE))
-    Test.Expected = ABCM_A_MAG_VALVE_DRIVE
+    Test.Value = results.Int( (int) (statusVector.Raw & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE)) )
+    Test.Expected = results.Int( ABCM_A_MAG_VALVE_DRIVE )
     Test.Pass = true
 
     if (status & ABCM_A_MAG_VALVE_DRIVE) != ABCM_A_MAG_VALVE_DRIVE ||
@@ -8142,7 +2672,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Set the B mag valve drive signal high and wait five seconds.
@@ -8176,6 +2706,10 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( STATUS_VECTOR, ABCM_12, response )
 
+    if err == nil {
+      statusVector, err = ParseStatusVector( response, responseCount )
+    }
+
     if err != nil || responseCount != STATUS_VECTOR_RESPONSE_LEN {
       RetValue = 0
       Passed = 0
@@ -8195,9 +2729,9 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         database.
     */
 
-    status := (int) (response[3])
+    status := (int) (statusVector.Raw)
 
-    Test := MatchTestResult{}
+    Test := NewResult( results.MATCH )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8211,8 +2745,8 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Received = (int) (response[3] & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE))
-    Test.Expected = ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE
+    Test.Value = results.Int( (int) (statusVector.Raw & (ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE)) )
+    Test.Expected = results.Int( ABCM_A_MAG_VALVE_DRIVE | ABCM_B_MAG_VALVE_DRIVE )
     Test.Pass = true
 
     if (status & ABCM_A_MAG_VALVE_DRIVE) != ABCM_A_MAG_VALVE_DRIVE ||
@@ -8239,11 +2773,11 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
-    gpio := mcp[4].Read( MCP23S17.GPIOA )
+    detected, _ := fixture.Sense( fix, fixture.SigDetectionOfMagValveDriver )
 
-    if (gpio & 1) != 1 {
+    if !detected {
       RetValue = 0
       Passed = 0
     }
@@ -8267,7 +2801,7 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Take the IEM tester back to its initial state.
   */
 
-  _, err1 := Init( mcp )
+  _, err1 := Init( fix )
 
   if err == nil {
     err = err1
@@ -8281,15 +2815,20 @@ func Test_ABCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Description    : This routine performs all of the ADCM tests.
 
-    Arguments      : mcp - Slice of pointers to the port extender control structures.
+    Arguments      : fix - Port extenders of the test fixture.
 
     Return Value   : Pass/Fail flag
                      Any error encountered during the tests.
 */
 
-func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
+func Test_ADCM( fix fixture.Fixture ) (int, error) {
   var err error = nil
   var responseCount int
+  var software SoftwareVersion
+  var hardware HardwareVersion
+  var voltages MonitoredVoltages
+  var statusVector StatusVector
+  var ambient AmbientLight
   var intensity int
 
   response := make([] byte, 50)
@@ -8303,6 +2842,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
   responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ADCM_03, response )
 
+  if err == nil {
+    voltages, err = ParseMonitoredVoltages( response, responseCount )
+  }
+
   if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
     RetValue = 0
       Passed = 0
@@ -8320,11 +2863,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    voltage := (int) (response[3])
-
-    voltage = (voltage << 8) + (int) (response[4])
+    voltage := (int) (voltages.Voltages[0])
 
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8332,19 +2873,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 1 12 Volt Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 12600
-    Test.MinValue = 11400
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 11400 || voltage >= 12600 {
-      fmt.Printf(" Test 1 12 Volts Failed (Got %d Expected > 11400 and < 12600)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 1 12 Volts Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -8353,18 +2899,16 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Check the 4 Volt entry for the proper range and then set the pass/fail flag.
         Write the test result to the database.
     */
 
-    voltage = (int) (response[5])
-
-    voltage = (voltage << 8) + int(response[6])
+    voltage = (int) (voltages.Voltages[1])
 
-    Test = RangeTestResult{}
+    Test = NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8372,19 +2916,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 1 4 Volt Test"
 
+    limit = TestLimit( Test.TestName )
+
     timeDate = time.Now()
     hour, min, sec = timeDate.Clock()
     year, month, day = timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 3800
-    Test.MinValue = 4200
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 3800 || voltage >= 4200 {
-      fmt.Printf(" Test 1 4 Volts Failed (Got %d Expected > 3800 and < 4200)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 1 4 Volts Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       RetValue = 0
       Passed = 0
@@ -8392,7 +2941,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Set the LEDs on the ADCM to a low level and wait two seconds.
@@ -8400,6 +2949,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _,err1 := SetADCM_LEDStateCommand( LED_AT_3 | LED_AT_6 | LED_AT_9 | LED_AT_12, 20 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -8443,7 +2994,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test := PassFailTestResult{}
+    Test := NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8466,7 +3017,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Set the ADCM LEDs to a brighter setting.
@@ -8474,6 +3025,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _,err1 = SetADCM_LEDStateCommand( LED_AT_3 | LED_AT_6 | LED_AT_9 | LED_AT_12, 80 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -8501,7 +3054,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     err1 := (error) (nil)
     
-    err1 = SerialPort.Flush()
+    err1 = IEMPort.Flush()
 
     fmt.Printf("Are all of the LEDs on the ADCM on and brighter (y or n) ? ")
 
@@ -8513,7 +3066,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       char, err1 = ConsoleInput.ReadByte()
     }
 
-    Test := PassFailTestResult{}
+    Test := NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8536,11 +3089,11 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     char, err1 = ConsoleInput.ReadByte()
 
-    err1 = SerialPort.Flush()
+    err1 = IEMPort.Flush()
 
     /*
         Turn off all of the LEDs and wait two seconds.
@@ -8548,6 +3101,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _,err = SetADCM_LEDStateCommand( 0, 50 )
 
+    err = allowUnconfirmed( err )
+
     start := time.Now()
 
     t := time.Now()
@@ -8581,7 +3136,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test := PassFailTestResult{}
+    Test := NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8604,7 +3159,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Turn on the ADCM sonalert at a low level and wait 5 seconds.
@@ -8612,6 +3167,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _, err1 = SetADCMSonalertStateCommand( 1, 25 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -8636,6 +3193,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ADCM_03, response )
 
+    if err == nil {
+      voltages, err = ParseMonitoredVoltages( response, responseCount )
+    }
+
     if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
       RetValue = 0
 
@@ -8655,11 +3216,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    voltage := (int) (response[7])
+    voltage := (int) (voltages.Voltages[2])
 
-    voltage = (voltage << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8667,19 +3226,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 3 Sonalert Setting 25 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 6300
-    Test.MinValue = 5700
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 5700 || voltage >= 6300 {
-      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected > 5700 and < 6300)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -8688,7 +3252,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Ask the user if he can hear the sonalert and the set the pass/fail flag.
@@ -8709,7 +3273,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test1 := PassFailTestResult{}
+    Test1 := NewResult( results.PASS_FAIL )
 
     Test1.AssemblyPartNumber = AssemblyPartNumber
     Test1.AssemblySerialNumber = AssemblySerialNumber
@@ -8733,7 +3297,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test1 )
+    SaveResult( &Test1 )
 
     /*
         Set the ADCM sonalert to a higher level and wait five seconds.
@@ -8741,6 +3305,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _, err1 = SetADCMSonalertStateCommand( 1, 50 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -8769,6 +3335,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ADCM_03, response )
 
+    if err == nil {
+      voltages, err = ParseMonitoredVoltages( response, responseCount )
+    }
+
     if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
       RetValue = 0
 
@@ -8788,11 +3358,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    voltage := (int) (response[7])
+    voltage := (int) (voltages.Voltages[2])
 
-    voltage = (voltage << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8800,19 +3368,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 3 Sonaler Setting 50 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 8400
-    Test.MinValue = 7600
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 7600 || voltage >= 8400 {
-      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected > 7600 and < 8400)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -8821,7 +3394,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Ask the user if he can hear the sonalert and then set the pass/fail flag.
@@ -8842,7 +3415,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test1 := RangeTestResult{}
+    Test1 := NewResult( results.RANGE )
 
     Test1.AssemblyPartNumber = AssemblyPartNumber
     Test1.AssemblySerialNumber = AssemblySerialNumber
@@ -8866,7 +3439,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test1 )
+    SaveResult( &Test1 )
 
     /*
         Set the ADCM sonalert to a yet higher level and wait five seconds.
@@ -8874,6 +3447,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _, err1 = SetADCMSonalertStateCommand( 1, 75 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -8904,6 +3479,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ADCM_03, response )
 
+    if err == nil {
+      voltages, err = ParseMonitoredVoltages( response, responseCount )
+    }
+
     if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
       RetValue = 0
 
@@ -8919,11 +3498,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    voltage := (int) (response[7])
+    voltage := (int) (voltages.Voltages[2])
 
-    voltage = (voltage << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -8931,19 +3508,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 3 Sonaler Setting 75 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 10500
-    Test.MinValue = 9500
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 9500 || voltage >= 10500 {
-      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected > 9500 and < 10500)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -8952,7 +3534,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Ask the user if he can hear the sonalert and then set the pass/flag.
@@ -8973,7 +3555,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test1 := PassFailTestResult{}
+    Test1 := NewResult( results.PASS_FAIL )
 
     Test1.AssemblyPartNumber = AssemblyPartNumber
     Test1.AssemblySerialNumber = AssemblySerialNumber
@@ -8997,7 +3579,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test1 )
+    SaveResult( &Test1 )
 
     /*
         Set the ADCM sonalert to its top level and wait five seconds.
@@ -9005,6 +3587,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _, err1 = SetADCMSonalertStateCommand( 1, 100 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -9033,6 +3617,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( MON_VOLTAGES, ADCM_03, response )
 
+    if err == nil {
+      voltages, err = ParseMonitoredVoltages( response, responseCount )
+    }
+
     if err != nil || responseCount != MON_VOLTAGES_RESPONSE_LEN {
       RetValue = 0
 
@@ -9052,11 +3640,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    voltage := (int) (response[7])
+    voltage := (int) (voltages.Voltages[2])
 
-    voltage = (voltage << 8) + (int) (response[8])
-
-    Test := RangeTestResult{}
+    Test := NewResult( results.RANGE )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9064,19 +3650,24 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 3 Sonaler Setting 100 Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = voltage
-    Test.MaxValue = 12600
-    Test.MinValue = 11400
+    Test.Value = results.Int( voltage )
+    Test.UpperLimit = limit.Max
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if voltage <= 11400 || voltage >= 12600 {
-      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected > 11400 and 12600)\r\n", voltage)
+    if !limit.Check( voltage ) {
+      fmt.Printf(" Test 3 Sonalert Voltage Failed (Got %d Expected %s)\r\n", voltage, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -9085,7 +3676,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Ask the user if he can hear the sonalert and then set the pass/fail flag.
@@ -9106,7 +3697,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     char, err1 = ConsoleInput.ReadByte()
 
-    Test1 := PassFailTestResult{}
+    Test1 := NewResult( results.PASS_FAIL )
 
     Test1.AssemblyPartNumber = AssemblyPartNumber
     Test1.AssemblySerialNumber = AssemblySerialNumber
@@ -9130,7 +3721,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test1.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test1 )
+    SaveResult( &Test1 )
 
     /*
         Turn off the ADCM sonalert and wait five seconds.
@@ -9138,6 +3729,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     _, err1 = SetADCMSonalertStateCommand( 0, 25 )
 
+    err1 = allowUnconfirmed( err1 )
+
     if err == nil {
       err = err1
     }
@@ -9166,6 +3759,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( STATUS_VECTOR, ADCM_12, response )
 
+    if err == nil {
+      statusVector, err = ParseStatusVector( response, responseCount )
+    }
+
     if err != nil || responseCount != STATUS_VECTOR_RESPONSE_LEN {
       RetValue = 0
 
@@ -9183,7 +3780,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    Test := PassFailTestResult{}
+    Test := NewResult( results.PASS_FAIL )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9199,7 +3796,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
     Test.Pass = true
 
-    if (response[3] & FLASH_TEST_PASSED_03) == FLASH_TEST_PASSED_03 {
+    if statusVector.FlashTestPassed {
       fmt.Printf("ADCM Flash Test Passed.\r\n")
     } else {
       Test.Pass = false
@@ -9210,7 +3807,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Ask the IEM for the hardware version of the ADCM.
@@ -9218,6 +3815,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err1 := InformationSelectionCommand( HW_VERSION, ADCM_02, response )
 
+    if err1 == nil {
+      hardware, err1 = ParseHardwareVersion( response, responseCount )
+    }
+
     if err == nil {
       err = err1
     }
@@ -9238,9 +3839,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the ADCM hardware version to the database.
     */
 
-    version := (int) (response[3])
+    version := (int) (hardware.Version)
 
-    Test := HardwareVersionTestResult{}
+    Test := NewResult( results.HARDWARE_VERSION )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9254,12 +3855,12 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.HardwareVersion = version
+    Test.Value = results.Int( version )
     Test.Pass = true
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     fmt.Printf("ADCM Hardware Version %d", version)
 
@@ -9269,6 +3870,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( SW_VERSION, ADCM_01, response )
 
+    if err == nil {
+      software, err = ParseSoftwareVersion( response, responseCount )
+    }
+
     if err != nil || responseCount != SW_VERSION_RESPONSE_LEN {
       RetValue = 0
 
@@ -9285,7 +3890,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the ADCM software version to the database.
     */
 
-    Test := SoftwareVersionTestResult{}
+    Test := NewResult( results.SOFTWARE_VERSION )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9299,21 +3904,20 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    index := s.Index((string) (response[3:responseCount - 3]), (string) (0))
-    version := (string) (response[3:3 + index])
-    Test.SoftwareVersion = version
+    version := software.Version
+    Test.Text = version
     Test.Pass = true
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     /*
         Tell the user to make sure that the ADCMs ambient light sensor is unobstructed.
         Then ask the IEM for the ADCMs ambient light intensity reading.
     */
 
-    fmt.Printf("ADCM Software Version %s\r\n", response[3:responseCount - 3])
+    fmt.Printf("ADCM Software Version %s\r\n", software.Version)
 
     fmt.Printf("\r\n\nPosition ADCM so that it has an unobstructed view of the ambient light in room.\r\n")
 
@@ -9331,6 +3935,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( AMBIENT_LIGHT_INT, 0, response )
 
+    if err == nil {
+      ambient, err = ParseAmbientLight( response, responseCount )
+    }
+
     if err != nil || responseCount != AMBIENT_LIGHT_INT_RESPONSE_LEN {
       RetValue = 0
 
@@ -9350,11 +3958,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
         Write the test result to the database.
     */
 
-    intensity = (int) (response[3])
+    intensity = (int) (ambient.Intensity)
 
-    intensity = (intensity << 8) + (int) (response[4])
-
-    Test := LowerLimitTestResult{}
+    Test := NewResult( results.LOWER_LIMIT )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9362,18 +3968,23 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
     Test.SerialNumber = ADCMSerialNumber
     Test.TestName = "ADCM Test 7 Ambient Light High Level Test"
 
+    limit := TestLimit( Test.TestName )
+
     timeDate := time.Now()
     hour, min, sec := timeDate.Clock()
     year, month, day := timeDate.Date()
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = intensity
-    Test.LowerLimit = 300
+    Test.Value = results.Int( intensity )
+    Test.LowerLimit = limit.Min
+    Test.LimitsVersion = Limits.Version
+    Test.Units = limit.Units
+    Test.Inclusive = results.Bool( limit.Inclusive )
     Test.Pass = true
 
-    if intensity <= 300 {
-      fmt.Printf(" Test 7 Ambient Light Sensor Failed (Got %d Expected > 300\r\n", intensity)
+    if !limit.Check( intensity ) {
+      fmt.Printf(" Test 7 Ambient Light Sensor Failed (Got %d Expected %s)\r\n", intensity, limit)
 
       Test.Pass = false
       RetValue = 0
@@ -9382,7 +3993,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     if Passed == 1 {
       fmt.Printf(" Test 7 Ambient Light Sensor Passed high test with value %d.\r\n", intensity)
@@ -9411,6 +4022,10 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     responseCount, err = InformationSelectionCommand( AMBIENT_LIGHT_INT, 0, response )
 
+    if err == nil {
+      ambient, err = ParseAmbientLight( response, responseCount )
+    }
+
     if err != nil || responseCount != AMBIENT_LIGHT_INT_RESPONSE_LEN {
       RetValue = 0
 
@@ -9432,11 +4047,9 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     previousIntensity := intensity/2
 
-    intensity = (int) (response[3])
-
-    intensity = (intensity << 8) + (int) (response[4])
+    intensity = (int) (ambient.Intensity)
 
-    Test := UpperLimitTestResult{}
+    Test := NewResult( results.UPPER_LIMIT )
 
     Test.AssemblyPartNumber = AssemblyPartNumber
     Test.AssemblySerialNumber = AssemblySerialNumber
@@ -9450,8 +4063,8 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.Time = fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec)
     Test.Date = fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
-    Test.Value = intensity
-    Test.UpperLimit = previousIntensity
+    Test.Value = results.Int( intensity )
+    Test.UpperLimit = results.Int( previousIntensity )
     Test.Pass = true
 
     if intensity >= previousIntensity {
@@ -9464,7 +4077,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
 
     Test.SetID(couchdb.GenerateUUID())
 
-    err = couchdb.Store( TestDB, &Test )
+    SaveResult( &Test )
 
     if Passed == 1 {
       fmt.Printf(" Test 7 Ambient Light Sensor Passed low test with value %d.\r\n", intensity)
@@ -9475,7 +4088,7 @@ func Test_ADCM( mcp []*MCP23S17.MCP23S17 ) (int, error) {
       Put the IEM tester back to its initial state.
   */
 
-  _, err1 := Init( mcp )
+  _, err1 := Init( fix )
 
   if err == nil {
     err = err1
@@ -9592,7 +4205,7 @@ func GetSerialNumber( number []byte ) ([]byte, error) {
       Flush the console input.
   */
 
-  ConsoleInput.Reset(os.Stdin)
+  ConsoleInput.Reset(ConsoleSource)
 
   /*
       Scan until we find the first non newline character and save it.
@@ -9662,7 +4275,7 @@ func GetSerialNumber( number []byte ) ([]byte, error) {
 
   number = number[:i - 1]
 
-  ConsoleInput.Reset(os.Stdin)
+  ConsoleInput.Reset(ConsoleSource)
 
   return number, err
 }
@@ -9917,103 +4530,407 @@ func GetNumberPair( identifier string, majorPartNumbers []int, minorPartNumber i
     }
   }
 
-  return string(number), string(number1), firstPart, secondPart, err
-}
+  return string(number), string(number1), firstPart, secondPart, err
+}
+
+/*
+    Procedure Name : GetAssemblyNumber
+
+    Description    : This routine asks for the top level assembly part number and serial number.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error that occurred during number entry.
+*/
+
+func GetAssemblyNumbers() error {
+  var err error
+
+  /*
+      For top level assembly numbers we only allow three numbers for the upper part of the part number :
+        251470 - IEM with alerter
+        251469 - IEM without alerter
+        250470 - ADCM only
+
+      for top level assembly numbers we allow any number for the lower part of the part number. This is
+      indicated as a zero which is the wild card.
+      
+  */
+
+  assemblyChoices := []int {251470, 251469, 250470}
+
+  AssemblyPartNumber, AssemblySerialNumber, AssemblyMajorPartNumber, AssemblyMinorPartNumber, err =  GetNumberPair( "Assembly", assemblyChoices, 0 )
+  
+  return err
+}
+
+/*
+    Procedure Name : GetNonAlerterIEMNumbers
+
+    Description    : This routine collects all of the part number/serial number pairs for an alerter assembly
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error returned during the collection of the numbers.
+*/
+
+func GetAlerterIEMNumbers() error {
+  var err error
+
+  moduleNumber := []int {227411}
+
+  MainCircuitPartNumber, MainCircuitSerialNumber, MainCircuitMajorPartNumber, MainCircuitMinorPartNumber, err = GetNumberPair( "IEM Main Circuit", moduleNumber, 100)
+  DualPowerSupplyPartNumber, DualPowerSupplySerialNumber, DualPowerSupplyMajorPartNumber, DualPowerSupplyMinorPartNumber, err = GetNumberPair("IEM Dual Power Supply", moduleNumber, 200)
+  CabConnectorCardPartNumber, CabConnectorSerialNumber, CabConnectorMajorPartNumber, CabConnectorMinorPartNumber, err = GetNumberPair("IEM Cab Connector Card", moduleNumber, 300)
+  IOConnectorPartNumber, IOConnectorSerialNumber, IOConnectorMajorPartNumber, IOConnectorMinorPartNumber, err = GetNumberPair("IEM IO Connector Card", moduleNumber, 400)
+  IEM4_20mAPartNumber, IEM4_20mASerialNumber, IEM4_20mAMajorPartNumber, IEM4_20mAMinorPartNumber, err = GetNumberPair("IEM 4-20mA", moduleNumber, 500)
+  ABCMPartNumber, ABCMSerialNumber, ABCMMajorPartNumber, ABCMMinorPartNumber, err = GetNumberPair("IEM Alerter Brake Control Module", moduleNumber, 600)
+
+  moduleNumber[0] = 250470
+
+  ADCMPartNumber, ADCMSerialNumber, ADCMMajorPartNumber, ADCMMinorPartNumber, err = GetNumberPair("IEM Alerter Display Control Module", moduleNumber, 0)
+
+  start := time.Now()
+
+  t := time.Now()
+
+  elapsed := t.Sub(start)
+
+  for elapsed < time.Duration(200)*time.Millisecond {
+    t = time.Now()
+
+    elapsed = t.Sub(start)
+  } 
+
+  ConsoleInput.Reset(ConsoleSource)
+
+  return err
+}
+
+/*
+    Procedure Name : GetNonAlerterIEMNumbers
+
+    Description    : This routine collects all of the part number/serial number pairs associated with an IEM without alerter assembly.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error that occurs during the collection.
+*/
+
+func GetNonAlerterIEMNumbers() error {
+  var err error
+
+  moduleNumber := []int {227411}
+
+  MainCircuitPartNumber, MainCircuitSerialNumber, MainCircuitMajorPartNumber, MainCircuitMinorPartNumber, err = GetNumberPair( "IEM Main Circuit", moduleNumber, 100)
+  DualPowerSupplyPartNumber, DualPowerSupplySerialNumber, DualPowerSupplyMajorPartNumber, DualPowerSupplyMinorPartNumber, err = GetNumberPair("IEM Dual Power Supply", moduleNumber, 200)
+  CabConnectorCardPartNumber, CabConnectorSerialNumber, CabConnectorMajorPartNumber, CabConnectorMinorPartNumber, err = GetNumberPair("IEM Cab Connector Card", moduleNumber, 300)
+  IOConnectorPartNumber, IOConnectorSerialNumber, IOConnectorMajorPartNumber, IOConnectorMinorPartNumber, err = GetNumberPair("IEM IO Connector Card", moduleNumber, 400)
+  IEM4_20mAPartNumber, IEM4_20mASerialNumber, IEM4_20mAMajorPartNumber, IEM4_20mAMinorPartNumber, err = GetNumberPair("IEM 4-20mA", moduleNumber, 500)
+
+  start := time.Now()
+
+  t := time.Now()
+
+  elapsed := t.Sub(start)
+
+  for elapsed < time.Duration(200)*time.Millisecond {
+    t = time.Now()
+
+    elapsed = t.Sub(start)
+  } 
+
+  ConsoleInput.Reset(ConsoleSource)
+
+  return err
+}
+
+/*
+    Procedure Name : LoadSettings
+
+    Description    : Reads the tester configuration, the test limits and
+                     the test sequences. Only a missing configuration file
+                     means the defaults; one that cannot be read or is
+                     malformed stops the tester, rather than testing
+                     with settings nobody chose.
+
+    Arguments      : configFile - Tester configuration file
+
+    Return Value   : Any error loading the configuration, the limits or
+                     the sequences
+*/
+
+func LoadSettings( configFile string ) error {
+  var err error
+
+  Settings, err = LoadConfig( configFile )
+
+  if err != nil {
+    return fmt.Errorf("configuration %s: %w", configFile, err)
+  }
+
+  Limits, err = LoadLimits( Settings.Limits )
+
+  if err != nil {
+    return err
+  }
+
+  fmt.Printf("Using test limits version %s\r\n", Limits.Version)
+
+  /*
+      The sequences are checked before any test is run.
+  */
+
+  err = LoadSequences( Settings.Sequences )
+
+  if err != nil {
+    return err
+  }
+
+  fmt.Printf("Using Main CPU sequence version %s\r\n", CPUMainSequence.Version)
+
+  return nil
+}
+
+/*
+    Procedure Name : PowerUp
+
+    Description    : Opens the link to the IEM and the port extenders on
+                     the test fixture, brings up power to the IEM and
+                     waits for it to be ready to talk to us.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The test fixture, nil if the link, the fixture or
+                     the IEM client could not be opened, in which case
+                     the IEM is powered down again
+                     Any error opening them or initializing the IEM
+*/
+
+func PowerUp() (fixture.Fixture, error) {
+  var err error
+
+  /*
+      Open up the link that goes to the IEM.
+  */
+
+  IEMPort, err = OpenTransport( Settings.Transport )
+
+  if err != nil {
+    return nil, err
+  }
+
+  /*
+      A recording sees the link underneath the trace, so the two agree.
+      Console input is recorded too, for the questions the tests ask.
+  */
+
+  if Settings.Record != "" {
+    IEMRecorder, err = replay.Create( Settings.Record )
+
+    if err != nil {
+      return nil, fmt.Errorf("error starting the session recording: %s", err)
+    }
+
+    log.Printf("Recording the session to %s", IEMRecorder.Name())
+
+    IEMPort = IEMRecorder.Link( IEMPort )
+
+    ConsoleSource = IEMRecorder.Console( ConsoleSource )
+    ConsoleInput = bufio.NewReader( ConsoleSource )
+  }
+
+  if Settings.Trace != "" {
+    IEMTrace, err = iemtrace.Open( Settings.Trace )
+
+    if err != nil {
+      return nil, fmt.Errorf("error opening the protocol trace: %s", err)
+    }
+
+    IEMPort = IEMTrace.Wrap( IEMPort )
+  }
+
+  /*
+      Open the port extenders on the test fixture.
+  */
+
+  fix, err := OpenFixture( Settings.Fixture )
+
+  if fix == nil {
+    return nil, fmt.Errorf("error connecting to the fixture: %s", err)
+  }
+
+  /*
+      A port extender that could not be opened is reported, and the
+      others are still used, as they always have been.
+  */
+
+  if err != nil {
+    log.Printf("%s", err)
+
+    err = nil
+  }
+
+  if IEMRecorder != nil {
+    fix = IEMRecorder.Fixture( fix )
+  }
+
+  /*
+      Initialize the port extenders. Port extender 4 reads the fixture
+      feedback on port A, all other ports are outputs.
+  */
+
+  fix.Configure( 0, 0xcffc, 0x0000 )
+  fix.Configure( 1, 0xffff, 0x0000 )
+  fix.Configure( 2, 0xffff, 0x0000 )
+  fix.Configure( 3, 0xc004, 0x0000 )
+  fix.Configure( 4, 0x0000, 0x00ff )
+
+  /*
+      Bring up powr to the IEM.
+  */
+
+  fixture.Assert( fix, fixture.SigDUTPowerEnable )
+
+  /*
+      Initialize the input buffer for initial data coming from the IEM.
+      Wait some time for the IEM to come up and stop sending us power on
+      self test text.
+  */
+
+  buf1 := make([]byte, 100)
+
+  for i := 0;i < len(buf1);i++ {
+    buf1[i] = '+'
+  }
+
+  start := time.Now()
+
+  t := time.Now()
+
+  elapsed := t.Sub(start)
+
+  for elapsed < time.Duration(1)*time.Second {
+    t = time.Now()
+
+    elapsed = t.Sub(start)
+  } 
+
+  for err == nil {
+    _, err = IEMPort.Read(buf1)
+  }
+
+  /*
+      Send the IEM serial port two carriage return/line feed pairs in order
+      to get the IEM ready to talk to us. Then wait a little and flush the
+      serial port.
+  */
+
+  output := []byte {0x0d,0x0a,0x0d,0x0a}
+
+  _, err = IEMPort.Write( output )
+
+  start = time.Now()
+
+  t = time.Now()
+
+  elapsed = t.Sub(start)
+
+  for elapsed < time.Duration(250)*time.Millisecond {
+    t = time.Now()
+
+    elapsed = t.Sub(start)
+  } 
 
-/*
-    Procedure Name : GetAssemblyNumber
+  err = IEMPort.Flush()
 
-    Description    : This routine asks for the top level assembly part number and serial number.
+  /*
+      From here on the IEM client's receiver is the only reader of the
+      link.
+  */
 
-    Arguments      : This routine has no arguments.
+  IEM, err = OpenClient( Settings.Protocol, IEMPort )
 
-    Return Value   : Any error that occurred during number entry.
-*/
+  if IEM == nil {
+    PowerDown( fix )
 
-func GetAssemblyNumbers() error {
-  var err error
+    IEMPort.Close()
 
-  /*
-      For top level assembly numbers we only allow three numbers for the upper part of the part number :
-        251470 - IEM with alerter
-        251469 - IEM without alerter
-        250470 - ADCM only
+    return nil, err
+  }
 
-      for top level assembly numbers we allow any number for the lower part of the part number. This is
-      indicated as a zero which is the wild card.
-      
-  */
+  go LogUnsolicited( IEM )
 
-  assemblyChoices := []int {251470, 251469, 250470}
+  _, err = Init( fix )
 
-  AssemblyPartNumber, AssemblySerialNumber, AssemblyMajorPartNumber, AssemblyMinorPartNumber, err =  GetNumberPair( "Assembly", assemblyChoices, 0 )
-  
-  return err
+  return fix, err
 }
 
 /*
-    Procedure Name : GetNonAlerterIEMNumbers
+    Procedure Name : LogUnsolicited
 
-    Description    : This routine collects all of the part number/serial number pairs for an alerter assembly
+    Description    : Logs every frame from the IEM that did not answer a
+                     request, such as a late response to a request that
+                     had already given up. It runs for the rest of the
+                     session, so start it with go.
 
-    Arguments      : This routine has no arguments.
+    Arguments      : iem - IEM client
 
-    Return Value   : Any error returned during the collection of the numbers.
+    Return Value   : This routine has no return value.
 */
 
-func GetAlerterIEMNumbers() error {
-  var err error
-
-  moduleNumber := []int {227411}
-
-  MainCircuitPartNumber, MainCircuitSerialNumber, MainCircuitMajorPartNumber, MainCircuitMinorPartNumber, err = GetNumberPair( "IEM Main Circuit", moduleNumber, 100)
-  DualPowerSupplyPartNumber, DualPowerSupplySerialNumber, DualPowerSupplyMajorPartNumber, DualPowerSupplyMinorPartNumber, err = GetNumberPair("IEM Dual Power Supply", moduleNumber, 200)
-  CabConnectorCardPartNumber, CabConnectorSerialNumber, CabConnectorMajorPartNumber, CabConnectorMinorPartNumber, err = GetNumberPair("IEM Cab Connector Card", moduleNumber, 300)
-  IOConnectorPartNumber, IOConnectorSerialNumber, IOConnectorMajorPartNumber, IOConnectorMinorPartNumber, err = GetNumberPair("IEM IO Connector Card", moduleNumber, 400)
-  IEM4_20mAPartNumber, IEM4_20mASerialNumber, IEM4_20mAMajorPartNumber, IEM4_20mAMinorPartNumber, err = GetNumberPair("IEM 4-20mA", moduleNumber, 500)
-  ABCMPartNumber, ABCMSerialNumber, ABCMMajorPartNumber, ABCMMinorPartNumber, err = GetNumberPair("IEM Alerter Brake Control Module", moduleNumber, 600)
-
-  moduleNumber[0] = 250470
+func LogUnsolicited( iem *iemclient.Client ) {
+  for rx := range iem.Unsolicited() {
+    if rx.Err != nil {
+      log.Printf("Unsolicited frame from the IEM: %v % x", rx.Err, rx.Raw)
+    } else {
+      log.Printf("Unsolicited frame from the IEM: selector %d subselector %d", rx.Frame.Selector, rx.Frame.Subselector)
+    }
+  }
+}
 
-  ADCMPartNumber, ADCMSerialNumber, ADCMMajorPartNumber, ADCMMinorPartNumber, err = GetNumberPair("IEM Alerter Display Control Module", moduleNumber, 0)
+/*
+    Procedure Name : AssemblyType
 
-  start := time.Now()
+    Description    : Works out which top level assembly has been entered.
+                     The ADCM only assembly is its own ADCM, so the ADCM
+                     numbers are taken from the assembly numbers.
 
-  t := time.Now()
+    Arguments      : This routine has no arguments.
 
-  elapsed := t.Sub(start)
+    Return Value   : IEM_WITH_ALERTER, IEM_WITHOUT_ALERTER or IEM_ADCM_ONLY
+*/
 
-  for elapsed < time.Duration(200)*time.Millisecond {
-    t = time.Now()
+func AssemblyType() int {
+  switch AssemblyMajorPartNumber {
+    case 251470 :
+      return IEM_WITH_ALERTER
 
-    elapsed = t.Sub(start)
-  } 
+    case 250470 :
+      ADCMMajorPartNumber = 227411
+      ADCMMinorPartNumber = 700
+      ADCMPartNumber = "227411-700"
+      ADCMSerialNumber = AssemblySerialNumber
 
-  ConsoleInput.Reset(os.Stdin)
+      return IEM_ADCM_ONLY
+  }
 
-  return err
+  return IEM_WITHOUT_ALERTER
 }
 
 /*
-    Procedure Name : GetNonAlerterIEMNumbers
+    Procedure Name : WaitForModules
 
-    Description    : This routine collects all of the part number/serial number pairs associated with an IEM without alerter assembly.
+    Description    : Waits for the IEM to collect the proper information
+                     from the ADCM and ABCM after power up.
 
     Arguments      : This routine has no arguments.
 
-    Return Value   : Any error that occurs during the collection.
+    Return Value   : This routine has no return value.
 */
 
-func GetNonAlerterIEMNumbers() error {
-  var err error
-
-  moduleNumber := []int {227411}
-
-  MainCircuitPartNumber, MainCircuitSerialNumber, MainCircuitMajorPartNumber, MainCircuitMinorPartNumber, err = GetNumberPair( "IEM Main Circuit", moduleNumber, 100)
-  DualPowerSupplyPartNumber, DualPowerSupplySerialNumber, DualPowerSupplyMajorPartNumber, DualPowerSupplyMinorPartNumber, err = GetNumberPair("IEM Dual Power Supply", moduleNumber, 200)
-  CabConnectorCardPartNumber, CabConnectorSerialNumber, CabConnectorMajorPartNumber, CabConnectorMinorPartNumber, err = GetNumberPair("IEM Cab Connector Card", moduleNumber, 300)
-  IOConnectorPartNumber, IOConnectorSerialNumber, IOConnectorMajorPartNumber, IOConnectorMinorPartNumber, err = GetNumberPair("IEM IO Connector Card", moduleNumber, 400)
-  IEM4_20mAPartNumber, IEM4_20mASerialNumber, IEM4_20mAMajorPartNumber, IEM4_20mAMinorPartNumber, err = GetNumberPair("IEM 4-20mA", moduleNumber, 500)
+func WaitForModules() {
+  fmt.Printf("\r\n\nInitializing Tester. Please wait.\r\n")
 
   start := time.Now()
 
@@ -10021,235 +4938,208 @@ func GetNonAlerterIEMNumbers() error {
 
   elapsed := t.Sub(start)
 
-  for elapsed < time.Duration(200)*time.Millisecond {
+  for elapsed < time.Duration(15)*time.Second {
     t = time.Now()
 
     elapsed = t.Sub(start)
-  } 
-
-  ConsoleInput.Reset(os.Stdin)
-
-  return err
+  }     
 }
 
 /*
-    Procedure Name : main
+    Procedure Name : RunTest
 
-    Description    : This is the main routine.
+    Description    : Runs one test as a new run, so that its results can
+                     be found together.
 
-    Arguments      : This routine has no arguments
- 
-    Return Value   : This routine has no return value
-*/
+    Arguments      : fix     - Test fixture
+                     test    - Name of the test, as shown on the menu
+                     alerter - Top level assembly, as returned by
+                               AssemblyType
 
-func main() {
-  var err2 error
-  var err1 error
+    Return Value   : 1 if the test passed, 0 if it failed
+                     Any error that stopped the test
+*/
 
-  /*
-     Set the logging flags so that logs include file and line information.
-  */
+func RunTest( fix fixture.Fixture, test string, alerter int ) (int, error) {
+  n := 0
+  err := (error) (nil)
 
-  log.SetFlags(log.LstdFlags | log.Lshortfile)
+  StartRun( test )
 
-  /*
-      Open up stdin as buffered i/o.
-  */
+  switch test {
+    case "4-20 mA Test" :
+      n, err = Test_4_20MA( fix )
 
-  ConsoleInput = bufio.NewReader(os.Stdin)
+    case "CPU Main Test" :
+      n, err = Test_CPUMain( fix, alerter )
 
-  /*
-      Open up the serial port that goes to the IEM.
-  */
+    case "ABCM Test" :
+      n, err = Test_ABCM( fix )
 
-  c := &serial.Config{Name:"/dev/ttyS0", Baud:9600, ReadTimeout: time.Second*5 }
+    case "ADCM Test" :
+      n, err = Test_ADCM( fix )
 
-  SerialPort, err2 = serial.OpenPort(c)
-  
-  if err2 != nil {
-    log.Print("%q", err2)
+    default :
+      err = fmt.Errorf("Unknown test %q", test)
   }
 
-  /*
-      Open up a connection to couchdb.
-  */
+  FinishRun( n, err )
 
-  TestDB,err1 = couchdb.NewDatabase("http://127.0.0.1:5984/testdb")
+  return n, err
+}
 
-  if ( err1 != nil ) {
-    fmt.Println("error opening test database: %s\n", err1)
-  }
+/*
+    Procedure Name : PowerDown
 
-  /*
-      Check that the database is responsive.
-  */
+    Description    : Gives the results of the last test a last chance to
+                     reach the database and turns off power to the IEM.
+                     Any results still waiting are saved the next time
+                     the tester runs.
 
-  err1 = TestDB.Available()
+    Arguments      : fix - Test fixture
 
-  if ( err1 != nil ) {
-    fmt.Println("error opening test database not available: %s\n", err1)
+    Return Value   : This routine has no return value.
+*/
+
+func PowerDown( fix fixture.Fixture ) {
+  if ResultJournal != nil && ResultJournal.Status().Pending > 0 {
+    FlushResults()
   }
 
   /*
-      Open and initialize the control structures for the port extenders.
+      The very last thing that we do is turn off power to the IEM.
   */
 
-  mcp23s17_0 := MCP23S17.NewMCP23S17( 0, spi.DEFAULT_BUS, spi.DEFAULT_CHIP ) 
-  mcp23s17_1 := MCP23S17.NewMCP23S17( 1, spi.DEFAULT_BUS, spi.DEFAULT_CHIP ) 
-  mcp23s17_2 := MCP23S17.NewMCP23S17( 2, spi.DEFAULT_BUS, spi.DEFAULT_CHIP ) 
-  mcp23s17_3 := MCP23S17.NewMCP23S17( 3, spi.DEFAULT_BUS, spi.DEFAULT_CHIP ) 
-  mcp23s17_4 := MCP23S17.NewMCP23S17( 4, spi.DEFAULT_BUS, spi.DEFAULT_CHIP ) 
-
-  mcp23s17 := []*MCP23S17.MCP23S17 {mcp23s17_0, mcp23s17_1, mcp23s17_2, mcp23s17_3, mcp23s17_4}
+  fixture.Deassert( fix, fixture.SigDUTPowerEnable )
 
-  err := mcp23s17[0].Open()
-
-  if ( err != nil ) {
-    fmt.Println("error connecting to chip 0: %s\n", err)
-  }
-
-  err = mcp23s17[1].Open()
-
-  if ( err != nil ) {
-    fmt.Println("error connecting to chip 1: %s\n", err)
+  if IEMTrace != nil {
+    IEMTrace.Close()
   }
 
-  err = mcp23s17[2].Open()
-
-  if ( err != nil ) {
-    fmt.Println("error connecting to chip 2: %s\n", err)
+  if IEMRecorder != nil {
+    IEMRecorder.Close()
   }
+}
 
-  err = mcp23s17[3].Open()
+/*
+    Procedure Name : main
 
-  if ( err != nil ) {
-    fmt.Println("error connecting to chip 3: %s\n", err)
-  }
+    Description    : This is the main routine.
 
-  err = mcp23s17[4].Open()
+    Arguments      : This routine has no arguments
+ 
+    Return Value   : This routine has no return value
+*/
 
-  if ( err != nil ) {
-    fmt.Println("error connecting to chip 4: %s\n", err)
-  }
+func main() {
+  var err1 error
 
   /*
-      Initialize the port extenders.
+     Set the logging flags so that logs include file and line information.
   */
 
-  mcp23s17[0].Write( 0x7e, MCP23S17.IOCON )
-  mcp23s17[0].Write( 0x7e, MCP23S17.IOCON + 1 )
-  mcp23s17[0].Write( 0xfc, MCP23S17.OLATA )
-  mcp23s17[0].Write( 0xcf, MCP23S17.OLATB )
-
-  Shadows[0] = 0xcffc
+  log.SetFlags(log.LstdFlags | log.Lshortfile)
 
-  mcp23s17[0].Write( 0x00, MCP23S17.IODIRA )
-  mcp23s17[0].Write( 0x00, MCP23S17.IODIRB )
+  /*
+      Subcommands run without the menu. One that sets its own exit code,
+      such as run giving the verdict, returns it as an ExitError.
+  */
 
-  mcp23s17[1].Write( 0x7e, MCP23S17.IOCON )
-  mcp23s17[1].Write( 0x7e, MCP23S17.IOCON + 1 )
-  mcp23s17[1].Write( 0xff, MCP23S17.OLATA )
-  mcp23s17[1].Write( 0xff, MCP23S17.OLATB )
+  if len(os.Args) > 1 {
+    if run, ok := Subcommands[os.Args[1]]; ok {
+      err := run( os.Args[2:] )
 
-  Shadows[1] = 0xffff
+      var exit ExitError
 
-  mcp23s17[1].Write( 0x00, MCP23S17.IODIRA )
-  mcp23s17[1].Write( 0x00, MCP23S17.IODIRB )
+      if errors.As( err, &exit ) {
+        os.Exit( exit.Code )
+      }
 
-  mcp23s17[2].Write( 0x7e, MCP23S17.IOCON )
-  mcp23s17[2].Write( 0x7e, MCP23S17.IOCON + 1 )
-  mcp23s17[2].Write( 0xff, MCP23S17.OLATA )
-  mcp23s17[2].Write( 0xff, MCP23S17.OLATB )
+      if err != nil {
+        log.Fatal( err )
+      }
 
-  Shadows[2] = 0xffff
+      return
+    }
+  }
 
-  mcp23s17[2].Write( 0x00, MCP23S17.IODIRA )
-  mcp23s17[2].Write( 0x00, MCP23S17.IODIRB )
+  /*
+      Open up stdin as buffered i/o.
+  */
 
-  mcp23s17[3].Write( 0x7e, MCP23S17.IOCON )
-  mcp23s17[3].Write( 0x7e, MCP23S17.IOCON + 1 )
-  mcp23s17[3].Write( 0x04, MCP23S17.OLATA )
-  mcp23s17[3].Write( 0xc0, MCP23S17.OLATB )
+  ConsoleInput = bufio.NewReader(ConsoleSource)
 
-  Shadows[3] = 0xc004
+  /*
+      Read the tester configuration.
+  */
 
-  mcp23s17[3].Write( 0x00, MCP23S17.IODIRA )
-  mcp23s17[3].Write( 0x00, MCP23S17.IODIRB )
+  configFile := flag.String("config", DEFAULT_CONFIG_FILE, "tester configuration file")
 
-  mcp23s17[4].Write( 0x7e, MCP23S17.IOCON )
-  mcp23s17[4].Write( 0x7e, MCP23S17.IOCON + 1 )
-  mcp23s17[4].Write( 0x00, MCP23S17.OLATA )
-  mcp23s17[4].Write( 0x00, MCP23S17.OLATB )
+  flag.StringVar(&Operator, "operator", "", "name of the operator running the tests")
 
-  Shadows[4] = 0x0000
+  flush := flag.Bool("flush", false, "save the results waiting in the journal and exit")
 
-  mcp23s17[4].Write( 0xff, MCP23S17.IODIRA )
-  mcp23s17[4].Write( 0x00, MCP23S17.IODIRB )
+  flag.Parse()
 
   /*
-      Bring up powr to the IEM.
+      Load the configuration, the test limits and the test sequences.
+      Without the limits or the sequences every test would fail, and a
+      malformed configuration would test with the wrong settings, so
+      there is no point going on.
   */
 
-  Shadows[3] |= 2
+  err1 = LoadSettings( *configFile )
 
-  mcp23s17[3].Write( 0x06, MCP23S17.OLATA )
-
-  n := (int) (0)
-  err3 := (error) (nil)
+  if err1 != nil {
+    log.Fatalf("%q", err1)
+  }
 
   /*
-      Initialize the input buffer for initial data coming from the IEM.
-      Wait some time for the IEM to come up and stop sending us power on
-      self test text.
+      Open the result sinks. The couchdb sink keeps results in a journal
+      until couchdb has them, so the tests can run while the database is
+      down. Without the sinks nothing could be saved at all.
   */
 
-  buf1 := make([]byte, 100)
+  err1 = OpenStorage()
 
-  for i := 0;i < len(buf1);i++ {
-    buf1[i] = '+'
+  if ( err1 != nil ) {
+    log.Fatalf("error opening the result sinks: %s", err1)
   }
 
-  start := time.Now()
-
-  t := time.Now()
-
-  elapsed := t.Sub(start)
+  defer CloseStorage()
 
-  for elapsed < time.Duration(1)*time.Second {
-    t = time.Now()
+  if *flush {
+    err1 = FlushResults()
 
-    elapsed = t.Sub(start)
-  } 
+    if err1 != nil {
+      log.Fatalf("%q", err1)
+    }
 
-  for err3 == nil {
-    n, err3 = SerialPort.Read(buf1)
+    return
   }
 
   /*
-      Send the IEM serial port two carriage return/line feed pairs in order
-      to get the IEM ready to talk to us. Then wait a little and flush the
-      serial port.
+      Bring up the IEM on the test fixture.
   */
 
-  output := []byte {0x0d,0x0a,0x0d,0x0a}
-
-  _, err3 = SerialPort.Write( output )
-
-  start = time.Now()
-
-  t = time.Now()
+  fix, err := PowerUp()
 
-  elapsed = t.Sub(start)
+  if fix == nil {
+    log.Fatalf("%q", err)
+  }
 
-  for elapsed < time.Duration(250)*time.Millisecond {
-    t = time.Now()
+  if err != nil {
+    log.Printf("%q", err)
+  }
 
-    elapsed = t.Sub(start)
-  } 
+  n := (int) (0)
+  err3 := (error) (nil)
 
-  err3 = SerialPort.Flush()
+  /*
+      Find out who is running the tests, for the run records.
+  */
 
-  _, err3 = Init( mcp23s17 )
+  err3 = GetOperator()
 
   /*
       Turn off echo and canonical mode on the console input.
@@ -10265,39 +5155,19 @@ func main() {
 
   err3 = GetAssemblyNumbers()
 
-  alerter := 0
-
   /*
-      Determing which top level assembly that we have and record it for later.
+      Determing which top level assembly that we have and ask for the
+      numbers of its modules.
   */
 
-  if AssemblyMajorPartNumber == 251470 {
-    /*
-        This is the IEM with alerter.
-    */
+  alerter := AssemblyType()
 
+  if alerter == IEM_WITH_ALERTER {
     GetAlerterIEMNumbers()
+  }
 
-    alerter = 1
-  } else {
-    if AssemblyMajorPartNumber == 250470 {
-      /*
-          This is the ADCM only.
-      */
-
-      alerter = 2
-
-      ADCMMajorPartNumber = 227411
-      ADCMMinorPartNumber = 700
-      ADCMPartNumber = "227411-700"
-      ADCMSerialNumber = AssemblySerialNumber
-    } else {
-      /*
-          This is the IEM without alerter
-      */
-
-      GetNonAlerterIEMNumbers()
-    }
+  if alerter == IEM_WITHOUT_ALERTER {
+    GetNonAlerterIEMNumbers()
   }
 
   /*
@@ -10321,24 +5191,7 @@ func main() {
   fmt.Printf("%2.2d/%2.2d/%4d\r\n", month, day, year)
   
   if alerter > 0 {
-    /*
-       Now we wait some extra so that the IEM has collected the proper
-       informtaion from the ADCM and ABCM.
-    */
-
-    fmt.Printf("\r\n\nInitializing Tester. Please wait.\r\n")
-
-    start = time.Now()
-
-    t = time.Now()
-
-    elapsed = t.Sub(start)
-
-    for elapsed < time.Duration(15)*time.Second {
-      t = time.Now()
-
-      elapsed = t.Sub(start)
-    }     
+    WaitForModules()
   }
 
   /*
@@ -10350,7 +5203,11 @@ func main() {
   char1 := (byte) (0)
 
   for char != 'q' {
-    ConsoleInput.Reset(os.Stdin)
+    ConsoleInput.Reset(ConsoleSource)
+
+    fmt.Printf("\r\n\n")
+
+    PrintStorageStatus()
 
     if alerter == 1 {
       fmt.Printf("\r\n\n1) 4-20 mA Test\r\n")
@@ -10361,17 +5218,23 @@ func main() {
 
       fmt.Printf("4) ADCM Test\r\n")
 
+      fmt.Printf("f) Save Waiting Results\r\n")
+
       fmt.Printf("q) Quit\r\n\n\n")
     } else {
       if alerter == 2 {
         fmt.Printf("\r\n\n1) ADCM Test\r\n")
 
+        fmt.Printf("f) Save Waiting Results\r\n")
+
         fmt.Printf("q) Quit\r\n\n\n")
       } else {
         fmt.Printf("\r\n\n1) 4-20 mA Test\r\n")
 
         fmt.Printf("2) CPU Main Test\r\n")
 
+        fmt.Printf("f) Save Waiting Results\r\n")
+
         fmt.Printf("q) Quit\r\n\n\n")
       }
     }
@@ -10408,33 +5271,50 @@ func main() {
        Now we execute the test request.
     */
 
+    test := ""
+
     switch char {
       case '1' :
         if alerter < 2 {
-          n, err3 = Test_4_20MA( mcp23s17 )
+          test = "4-20 mA Test"
         } else {
-          n, err3 = Test_ADCM( mcp23s17 )
+          test = "ADCM Test"
         }
 
       case '2' :
         if alerter < 2 {
-          n, err3 = Test_CPUMain( mcp23s17, alerter )
+          test = "CPU Main Test"
         }
       case '3' :
         if alerter == 1 {
-          n, err3 = Test_ABCM( mcp23s17 )
+          test = "ABCM Test"
         }
       case '4' :
         if alerter == 1 {
-          n, err3 = Test_ADCM( mcp23s17 )
+          test = "ADCM Test"
+        }
+      case 'f' :
+        err1 = FlushResults()
+
+        if err1 != nil {
+          log.Printf("error saving results: %s", err1)
         }
       case 'q' :
-        ConsoleInput.Reset(os.Stdin)
+        ConsoleInput.Reset(ConsoleSource)
       default :
-        ConsoleInput.Reset(os.Stdin)
+        ConsoleInput.Reset(ConsoleSource)
         continue
     }
 
+    /*
+        Every test is run as a new run so that its results can be found
+        together.
+    */
+
+    if test != "" {
+      n, err3 = RunTest( fix, test, alerter )
+    }
+
     /*
         Then we display the pass/fail message for the requested test.
     */
@@ -10453,13 +5333,34 @@ func main() {
         } else {
           log.Printf("%q", result)
         }
+
+        if UnsavedResults > 0 {
+          log.Printf("Results not saved: %d results of this test could not be saved", UnsavedResults)
+        }
+
+        if stats := IEM.Stats(); stats.Retries > 0 {
+          log.Printf("%d requests sent again this session: %d no response, %d short, %d failed the CRC check, %d for another request",
+                     stats.Retries, stats.NoResponse, stats.ShortFrames, stats.CRCMismatches, stats.UnexpectedEchoes)
+        }
+
+        if stats := IEM.Stats(); stats.Unsolicited > 0 || stats.NoiseBytes > 0 {
+          log.Printf("%d frames received that answered nothing asked for, %d noise bytes thrown away this session",
+                     stats.Unsolicited, stats.NoiseBytes)
+        }
       }
     }
-  }
 
-  /*
-      The very last thing that we do is turn off power to the IEM.
-  */
+    /*
+        A test that was interrupted ends the session, after the unit is
+        powered down.
+    */
+
+    if Interrupted {
+      log.Printf("Interrupted: powering down")
+
+      break
+    }
+  }
 
-  mcp23s17[3].Write( 0x04, MCP23S17.OLATA )
+  PowerDown( fix )
 }
@@ -0,0 +1,382 @@
+/*
+   Package iemtrace records the traffic on the link to the IEM, so that a
+   failure on the production floor can be looked at afterwards without a
+   logic analyzer.
+
+   A trace is a text file with one line for every frame sent or received:
+
+     2026-10-18T14:03:22.417215Z tx 03/01 ok  f5 03 01 3c 8a f6
+     2026-10-18T14:03:22.431870Z rx 03/01 ok  f5 03 01 2e f0 00 ... f6
+     2026-10-18T14:03:22.980112Z rx 34/00 bad iemproto: CRC mismatch (got 1234 expected 5678)  f5 ...
+     2026-10-18T14:03:23.102554Z rx noise 13 bytes  0d 0a 49 45 4d ...
+
+   The time is UTC with microseconds, the direction is tx for bytes sent
+   to the IEM and rx for bytes received, then the selector and
+   subselector, the CRC status and the raw bytes as they were on the
+   wire. Bytes that are not part of a frame are written as noise.
+*/
+
+package iemtrace
+
+import (
+        "fmt"
+        "os"
+        "path/filepath"
+        "strings"
+        "sync"
+        "time"
+
+        "github.com/questrail/IEMTestDB/iemproto"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+const TIME_FORMAT = "2006-01-02T15:04:05.000000Z"
+const SUFFIX = ".trace"                    // Suffix of a trace file
+const CONTENT_TYPE = "text/plain"          // MIME type of a trace
+
+/* Directions */
+
+const (
+  TX = "tx"                                // Sent to the IEM
+  RX = "rx"                                // Received from the IEM
+)
+
+/* Tracer */
+
+type Tracer struct {
+  dir string                               // Directory of the trace files
+  mu sync.Mutex                            // Protects everything below
+  session *os.File                         // Traffic outside of a run
+  run *os.File                             // Traffic of the run in progress, nil between runs
+  receiver iemproto.Receiver               // Splits the received bytes into frames
+  pending []byte                           // Noise waiting to be written
+}
+
+/* Link that is traced */
+
+type tracedPort struct {
+  transport.Transport                      // Link to the IEM
+  t *Tracer                                // Where the traffic is written
+}
+
+/*
+    Procedure Name : Open
+
+    Description    : Creates the trace directory if needed and starts a
+                     session trace, named for the time, that takes the
+                     traffic outside of runs, such as at power up.
+
+    Arguments      : dir - Trace directory
+
+    Return Value   : The tracer
+                     Any error creating the directory or the file
+*/
+
+func Open( dir string ) (*Tracer, error) {
+  err := os.MkdirAll( dir, 0755 )
+
+  if err != nil {
+    return nil, err
+  }
+
+  name := "session-" + time.Now().UTC().Format( "20060102T150405Z" ) + SUFFIX
+
+  session, err := os.Create( filepath.Join( dir, name ) )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return &Tracer{ dir: dir, session: session }, nil
+}
+
+/*
+    Procedure Name : Wrap
+
+    Description    : Returns a link that writes everything sent and
+                     received through it to the trace.
+
+    Arguments      : port - Link to the IEM
+
+    Return Value   : The traced link
+*/
+
+func (t *Tracer) Wrap( port transport.Transport ) transport.Transport {
+  return &tracedPort{ Transport: port, t: t }
+}
+
+/*
+    Procedure Name : Begin
+
+    Description    : Starts the trace of a run in its own file. Traffic
+                     goes to it until End.
+
+    Arguments      : id - Run ID, which names the file
+
+    Return Value   : Any error creating the file
+*/
+
+func (t *Tracer) Begin( id string ) error {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  if t.run != nil {
+    t.run.Close()
+
+    t.run = nil
+  }
+
+  f, err := os.Create( filepath.Join( t.dir, id + SUFFIX ) )
+
+  if err == nil {
+    t.run = f
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : End
+
+    Description    : Finishes the trace of the run in progress and goes
+                     back to the session trace. The file is kept.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The trace of the run, nil if no run was being traced
+                     Any error writing or reading the file
+*/
+
+func (t *Tracer) End() ([]byte, error) {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  if t.run == nil {
+    return nil, nil
+  }
+
+  t.noise( time.Now() )
+
+  name := t.run.Name()
+
+  err := t.run.Close()
+
+  t.run = nil
+
+  if err != nil {
+    return nil, err
+  }
+
+  return os.ReadFile( name )
+}
+
+/*
+    Procedure Name : Close
+
+    Description    : Finishes any run trace and closes the session trace.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error closing the files
+*/
+
+func (t *Tracer) Close() error {
+  _, err := t.End()
+
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  if closeErr := t.session.Close(); err == nil {
+    err = closeErr
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : Sent
+
+    Description    : Traces bytes written to the IEM. A write is either a
+                     whole frame or text, such as the carriage returns
+                     sent at power up.
+
+    Arguments      : b - Bytes written
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) Sent( b []byte ) {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  now := time.Now()
+
+  if len(b) == 0 || b[0] != iemproto.StartByte {
+    t.write( now, fmt.Sprintf("%s noise %d bytes  % x", TX, len(b), b) )
+
+    return
+  }
+
+  _, err := iemproto.Decode( b )
+
+  t.frame( now, TX, b, err )
+}
+
+/*
+    Procedure Name : Received
+
+    Description    : Traces bytes read from the IEM. Frames may be split
+                     over several reads, so each frame is written when its
+                     end byte arrives, stamped with the time of that read.
+
+    Arguments      : b - Bytes read
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) Received( b []byte ) {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  now := time.Now()
+
+  for i := 0;i < len(b);i++ {
+    if t.receiver.Pending() == 0 {
+      if b[i] == iemproto.StartByte {
+        t.noise( now )
+      } else {
+        t.pending = append(t.pending, b[i])
+      }
+    }
+
+    for _, rx := range t.receiver.Feed( b[i:i + 1] ) {
+      t.noise( now )
+
+      t.frame( now, RX, rx.Raw, rx.Err )
+    }
+  }
+}
+
+/*
+    Procedure Name : Flushed
+
+    Description    : Traces a flush of the received bytes. A frame that
+                     was part way in is thrown away with it.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) Flushed() {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  now := time.Now()
+
+  t.noise( now )
+
+  t.receiver = iemproto.Receiver{}
+
+  t.write( now, RX + " flush" )
+}
+
+/*
+    Procedure Name : noise
+
+    Description    : Writes the noise collected so far. The caller must
+                     hold the tracer lock.
+
+    Arguments      : now - Time of the line
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) noise( now time.Time ) {
+  if len(t.pending) > 0 {
+    t.write( now, fmt.Sprintf("%s noise %d bytes  % x", RX, len(t.pending), t.pending) )
+  }
+
+  t.pending = t.pending[:0]
+}
+
+/*
+    Procedure Name : frame
+
+    Description    : Writes one frame. The selector and subselector are
+                     shown even when the CRC fails, as long as the frame
+                     got that far. The caller must hold the tracer lock.
+
+    Arguments      : now       - Time of the line
+                     direction - TX or RX
+                     raw       - Frame as it was on the wire
+                     err       - Framing or CRC error, nil if good
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) frame( now time.Time, direction string, raw []byte, err error ) {
+  header := "--/--"
+
+  if len(raw) > 2 {
+    body, stuffErr := iemproto.Unstuff( raw[1:len(raw) - 1] )
+
+    if stuffErr == nil && len(body) >= iemproto.HeaderLen {
+      header = fmt.Sprintf("%02x/%02x", body[0], body[1])
+    }
+  }
+
+  status := "ok"
+
+  if err != nil {
+    status = "bad " + err.Error()
+  }
+
+  t.write( now, fmt.Sprintf("%s %s %s  % x", direction, header, status, raw) )
+}
+
+/*
+    Procedure Name : write
+
+    Description    : Writes a line to the run trace, or to the session
+                     trace between runs. The caller must hold the tracer
+                     lock. A trace that cannot be written is not allowed
+                     to stop a test, so errors are ignored.
+
+    Arguments      : now  - Time of the line
+                     line - Line without the time
+
+    Return Value   : This routine has no return value.
+*/
+
+func (t *Tracer) write( now time.Time, line string ) {
+  f := t.session
+
+  if t.run != nil {
+    f = t.run
+  }
+
+  fmt.Fprintf( f, "%s %s\n", now.UTC().Format( TIME_FORMAT ), strings.TrimRight( line, " " ) )
+}
+
+func (p *tracedPort) Read( b []byte ) (int, error) {
+  n, err := p.Transport.Read( b )
+
+  if n > 0 {
+    p.t.Received( b[:n] )
+  }
+
+  return n, err
+}
+
+func (p *tracedPort) Write( b []byte ) (int, error) {
+  p.t.Sent( b )
+
+  return p.Transport.Write( b )
+}
+
+func (p *tracedPort) Flush() error {
+  p.t.Flushed()
+
+  return p.Transport.Flush()
+}
@@ -0,0 +1,482 @@
+/*
+   Package journal keeps documents on the tester's own disk until they
+   have been saved in the test database. Every document is written to
+   the journal directory first, one file per document, and a background
+   replicator copies the files to the database and removes them. A test
+   therefore never loses a result because the network or the database
+   is down; the results wait in the journal until it comes back.
+*/
+
+package journal
+
+import (
+        "encoding/json"
+        "errors"
+        "fmt"
+        "net/url"
+        "os"
+        "path/filepath"
+        "sort"
+        "strings"
+        "sync"
+        "time"
+)
+
+const DEFAULT_RETRY_INTERVAL = 10 * time.Second
+
+const suffix = ".json"                     // Suffix of a journalled document
+const tempSuffix = ".tmp"                  // Suffix of a document being written
+const badSuffix = ".bad"                   // Suffix of a document set aside as unsaveable
+
+var ErrRejected = errors.New("journal: document set aside")
+
+/* The test database, as far as the journal needs it */
+
+type Database interface {
+  Get( docid string, options url.Values ) (map[string]interface{}, error)
+  Set( docid string, doc map[string]interface{} ) error
+}
+
+/* Replication status */
+
+type Status struct {
+  Pending int                      // Documents waiting to be saved
+  LastError error                  // Error from the last attempt to save, nil if it worked
+  LastSaved time.Time              // When a document was last saved in the database
+  Rejected int                     // Documents set aside because they can never be saved
+}
+
+/* Journal */
+
+type Journal struct {
+  dir string                       // Directory holding the waiting documents
+  mu sync.Mutex                    // Protects the files and status
+  flush sync.Mutex                 // Allows one flush at a time
+  status Status                    // Replication status
+  wake chan struct{}               // Signalled whenever a document is added
+
+  /*
+     Permanent tells an error from the database that will happen every
+     time the document is saved, such as a validation failure, from one
+     that may pass, such as the database being down. If it is nil every
+     database error is taken to be passing.
+  */
+
+  Permanent func( err error ) bool
+}
+
+/*
+    Procedure Name : Open
+
+    Description    : Opens the journal in a directory, creating it if
+                     needed. Documents left from an earlier session are
+                     kept and will be saved by the next flush; partly
+                     written files from a crash are removed.
+
+    Arguments      : dir - Journal directory
+
+    Return Value   : The journal
+                     Any error opening the directory
+*/
+
+func Open( dir string ) (*Journal, error) {
+  err := os.MkdirAll( dir, 0755 )
+
+  if err != nil {
+    return nil, err
+  }
+
+  j := &Journal{ dir: dir, wake: make(chan struct{}, 1) }
+
+  names, err := j.pending()
+
+  if err != nil {
+    return nil, err
+  }
+
+  temps, _ := filepath.Glob( filepath.Join( dir, "*" + tempSuffix ) )
+
+  for _, name := range temps {
+    os.Remove( name )
+  }
+
+  j.status.Pending = len(names)
+
+  bad, _ := filepath.Glob( filepath.Join( dir, "*" + badSuffix ) )
+
+  j.status.Rejected = len(bad)
+
+  return j, nil
+}
+
+/*
+    Procedure Name : pending
+
+    Description    : Lists the documents waiting in the journal, oldest
+                     first.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : File names of the waiting documents
+                     Any error reading the directory
+*/
+
+func (j *Journal) pending() ([]string, error) {
+  entries, err := os.ReadDir( j.dir )
+
+  if err != nil {
+    return nil, err
+  }
+
+  type waiting struct {
+    name string
+    modified time.Time
+  }
+
+  files := []waiting{}
+
+  for _, entry := range entries {
+    if entry.IsDir() || !strings.HasSuffix( entry.Name(), suffix ) {
+      continue
+    }
+
+    info, err := entry.Info()
+
+    if err == nil {
+      files = append(files, waiting{ entry.Name(), info.ModTime() })
+    }
+  }
+
+  sort.SliceStable( files, func( a, b int ) bool { return files[a].modified.Before( files[b].modified ) } )
+
+  names := make([]string, len(files))
+
+  for i := 0;i < len(files);i++ {
+    names[i] = files[i].name
+  }
+
+  return names, nil
+}
+
+/*
+    Procedure Name : Put
+
+    Description    : Writes a document to the journal and wakes the
+                     replicator. The document is on disk when Put
+                     returns. Putting a document with the same ID as one
+                     still waiting replaces it.
+
+    Arguments      : id  - Document ID
+                     doc - Document, anything that encodes as a JSON object
+
+    Return Value   : Any error writing the document
+*/
+
+func (j *Journal) Put( id string, doc interface{} ) error {
+  data, err := json.Marshal( doc )
+
+  if err != nil {
+    return err
+  }
+
+  name := filepath.Join( j.dir, url.PathEscape( id ) + suffix )
+
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  _, statErr := os.Stat( name )
+
+  err = writeFile( name, data )
+
+  if err == nil && os.IsNotExist( statErr ) {
+    j.status.Pending++
+  }
+
+  select {
+    case j.wake <- struct{}{} :
+    default :
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : writeFile
+
+    Description    : Writes a file so that it is either all there or not
+                     there at all, even if power is lost part way.
+
+    Arguments      : name - File name
+                     data - Contents
+
+    Return Value   : Any error writing the file
+*/
+
+func writeFile( name string, data []byte ) error {
+  temp := strings.TrimSuffix( name, suffix ) + tempSuffix
+
+  f, err := os.Create( temp )
+
+  if err != nil {
+    return err
+  }
+
+  _, err = f.Write( data )
+
+  if err == nil {
+    err = f.Sync()
+  }
+
+  if closeErr := f.Close(); err == nil {
+    err = closeErr
+  }
+
+  if err == nil {
+    err = os.Rename( temp, name )
+  }
+
+  if err != nil {
+    os.Remove( temp )
+
+    return err
+  }
+
+  dir, err := os.Open( filepath.Dir( name ) )
+
+  if err == nil {
+    err = dir.Sync()
+
+    dir.Close()
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : save
+
+    Description    : Saves one document in the database. A document that
+                     is already there, such as a run that is being
+                     finished, is updated.
+
+    Arguments      : db  - Test database
+                     id  - Document ID
+                     doc - Document
+
+    Return Value   : Any error saving the document
+*/
+
+func save( db Database, id string, doc map[string]interface{} ) error {
+  delete(doc, "_rev")
+
+  err := db.Set( id, doc )
+
+  if err != nil {
+    current, getErr := db.Get( id, nil )
+
+    if getErr == nil {
+      doc["_rev"] = current["_rev"]
+
+      err = db.Set( id, doc )
+    }
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : Flush
+
+    Description    : Saves every waiting document in the database,
+                     oldest first, and removes it from the journal. A
+                     document that can never be saved, because its file
+                     is corrupt or the database rejects it for good, is
+                     renamed to .bad and left for someone to look at, and
+                     the flush goes on with the rest. Any other error,
+                     such as the database being down, stops the flush
+                     until the next try.
+
+    Arguments      : db - Test database
+
+    Return Value   : Number of documents saved
+                     The first error met, nil if every document was saved
+*/
+
+func (j *Journal) Flush( db Database ) (int, error) {
+  j.flush.Lock()
+  defer j.flush.Unlock()
+
+  j.mu.Lock()
+  names, err := j.pending()
+  j.mu.Unlock()
+
+  first := err
+  saved := 0
+
+  for i := 0;i < len(names) && err == nil;i++ {
+    name := filepath.Join( j.dir, names[i] )
+
+    var data []byte
+    var doc map[string]interface{}
+
+    data, err = os.ReadFile( name )
+
+    if err == nil {
+      if decodeErr := json.Unmarshal( data, &doc ); decodeErr != nil {
+        err = j.reject( name, fmt.Errorf("%s: corrupt journal file: %w", names[i], decodeErr) )
+      }
+    }
+
+    if err == nil {
+      id, _ := url.PathUnescape( strings.TrimSuffix( names[i], suffix ) )
+
+      err = save( db, id, doc )
+
+      if err != nil && j.Permanent != nil && j.Permanent( err ) {
+        err = j.reject( name, fmt.Errorf("%s: rejected by the database: %w", id, err) )
+      } else if err == nil {
+        j.saved( name, data )
+
+        saved++
+
+        continue
+      }
+    }
+
+    if first == nil {
+      first = err
+    }
+
+    /*
+        A rejected document has been set aside, so go on with the next.
+    */
+
+    if errors.Is( err, ErrRejected ) {
+      err = nil
+    }
+  }
+
+  j.mu.Lock()
+  j.status.LastError = first
+  j.mu.Unlock()
+
+  return saved, first
+}
+
+/*
+    Procedure Name : saved
+
+    Description    : Removes a document that has been saved from the
+                     journal, unless it was replaced while it was being
+                     saved; a replaced document is saved again next time.
+
+    Arguments      : name - Journal file of the document
+                     data - Contents that were saved
+
+    Return Value   : This routine has no return value.
+*/
+
+func (j *Journal) saved( name string, data []byte ) {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  current, readErr := os.ReadFile( name )
+
+  if readErr == nil && string(current) == string(data) {
+    os.Remove( name )
+
+    j.status.Pending--
+  }
+
+  j.status.LastSaved = time.Now()
+}
+
+/*
+    Procedure Name : reject
+
+    Description    : Sets a document that can never be saved aside by
+                     renaming it to .bad, so it no longer holds up the
+                     documents after it.
+
+    Arguments      : name - Journal file of the document
+                     err  - Why it can never be saved
+
+    Return Value   : err wrapped in ErrRejected, or the error renaming
+                     the file if that failed
+*/
+
+func (j *Journal) reject( name string, err error ) error {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  renameErr := os.Rename( name, strings.TrimSuffix( name, suffix ) + badSuffix )
+
+  if renameErr != nil {
+    return renameErr
+  }
+
+  j.status.Pending--
+  j.status.Rejected++
+
+  return fmt.Errorf("%w: %w", ErrRejected, err)
+}
+
+/*
+    Procedure Name : Replicate
+
+    Description    : Flushes the journal whenever a document is added, and
+                     retries at an interval while documents are waiting.
+                     It runs until the program ends, so start it with go.
+
+    Arguments      : open     - Returns the test database, or an error if
+                                it cannot be reached
+                     interval - Time between retries
+
+    Return Value   : This routine has no return value.
+*/
+
+func (j *Journal) Replicate( open func() (Database, error), interval time.Duration ) {
+  ticker := time.NewTicker( interval )
+
+  defer ticker.Stop()
+
+  for {
+    select {
+      case <-j.wake :
+      case <-ticker.C :
+    }
+
+    if j.Status().Pending == 0 {
+      continue
+    }
+
+    db, err := open()
+
+    if err == nil {
+      _, err = j.Flush( db )
+    }
+
+    if err != nil {
+      j.mu.Lock()
+      j.status.LastError = err
+      j.mu.Unlock()
+    }
+  }
+}
+
+/*
+    Procedure Name : Status
+
+    Description    : Returns the replication status.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The status
+*/
+
+func (j *Journal) Status() Status {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  return j.status
+}
@@ -0,0 +1,236 @@
+package journal
+
+import (
+        "errors"
+        "fmt"
+        "net/url"
+        "os"
+        "path/filepath"
+        "testing"
+)
+
+var errDown = errors.New("database down")
+var errForbidden = errors.New("forbidden")
+var errConflict = errors.New("conflict")
+var errNotFound = errors.New("not found")
+
+/* Database kept in memory, with the revision checks of CouchDB */
+
+type fakeDatabase struct {
+  docs map[string]map[string]interface{} // Saved documents by ID
+  revs map[string]int                    // Revision of each saved document
+  fail map[string]error                  // Error saving each document, if any
+}
+
+func (db *fakeDatabase) Get( docid string, options url.Values ) (map[string]interface{}, error) {
+  doc, ok := db.docs[docid]
+
+  if !ok {
+    return nil, errNotFound
+  }
+
+  return doc, nil
+}
+
+func (db *fakeDatabase) Set( docid string, doc map[string]interface{} ) error {
+  if err := db.fail[docid]; err != nil {
+    return err
+  }
+
+  if rev, ok := db.revs[docid]; ok && doc["_rev"] != fmt.Sprint( rev ) {
+    return errConflict
+  }
+
+  db.revs[docid]++
+
+  saved := map[string]interface{}{ "_rev": fmt.Sprint( db.revs[docid] ) }
+
+  for k, v := range doc {
+    if k != "_rev" {
+      saved[k] = v
+    }
+  }
+
+  db.docs[docid] = saved
+
+  return nil
+}
+
+/*
+    Procedure Name : TestFlush
+
+    Description    : Puts documents in a journal and flushes them to a
+                     fake database that fails in various ways, checking
+                     that a passing failure stops the flush with the
+                     documents kept, and a document that can never be
+                     saved is set aside without holding up the rest.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestFlush( t *testing.T ) {
+  tests := []struct {
+    name string
+    corrupt string                   // Document whose journal file is overwritten with garbage
+    stored []string                  // Documents already in the database
+    fail map[string]error            // Errors saving documents
+    permanent bool                   // Whether forbidden is taken as permanent
+    saved int
+    want error
+    pending []string
+    rejected []string
+  }{
+    { name: "all saved", saved: 3 },
+
+    { name: "update", stored: []string{ "b" }, saved: 3 },
+
+    { name: "database down", fail: map[string]error{ "a": errDown, "b": errDown, "c": errDown },
+      want: errDown, pending: []string{ "a", "b", "c" } },
+
+    { name: "down part way", fail: map[string]error{ "b": errDown }, saved: 1,
+      want: errDown, pending: []string{ "b", "c" } },
+
+    { name: "rejected", fail: map[string]error{ "b": errForbidden }, permanent: true, saved: 2,
+      want: ErrRejected, rejected: []string{ "b" } },
+
+    { name: "all rejected", fail: map[string]error{ "a": errForbidden, "b": errForbidden, "c": errForbidden }, permanent: true,
+      want: ErrRejected, rejected: []string{ "a", "b", "c" } },
+
+    { name: "rejected without Permanent", fail: map[string]error{ "b": errForbidden }, saved: 1,
+      want: errForbidden, pending: []string{ "b", "c" } },
+
+    { name: "corrupt file", corrupt: "a", saved: 2, want: ErrRejected, rejected: []string{ "a" } },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      dir := t.TempDir()
+
+      j, err := Open( dir )
+
+      if err != nil {
+        t.Fatal( err )
+      }
+
+      if tt.permanent {
+        j.Permanent = func( err error ) bool { return errors.Is( err, errForbidden ) }
+      }
+
+      db := &fakeDatabase{ docs: map[string]map[string]interface{}{}, revs: map[string]int{}, fail: tt.fail }
+
+      for _, id := range tt.stored {
+        db.Set( id, map[string]interface{}{ "value": -1 } )
+      }
+
+      for i, id := range []string{ "a", "b", "c" } {
+        if err = j.Put( id, map[string]interface{}{ "value": i } ); err != nil {
+          t.Fatal( err )
+        }
+      }
+
+      if tt.corrupt != "" {
+        os.WriteFile( filepath.Join( dir, tt.corrupt + suffix ), []byte("{\"value\": "), 0644 )
+      }
+
+      saved, err := j.Flush( db )
+
+      if saved != tt.saved {
+        t.Errorf("saved %d, want %d", saved, tt.saved)
+      }
+
+      if (tt.want == nil && err != nil) || (tt.want != nil && !errors.Is( err, tt.want )) {
+        t.Errorf("Flush returned %v, want %v", err, tt.want)
+      }
+
+      status := j.Status()
+
+      if status.Pending != len(tt.pending) || status.Rejected != len(tt.rejected) {
+        t.Errorf("%d pending and %d rejected, want %d and %d", status.Pending, status.Rejected, len(tt.pending), len(tt.rejected))
+      }
+
+      for _, id := range tt.pending {
+        if _, err := os.Stat( filepath.Join( dir, id + suffix ) ); err != nil {
+          t.Errorf("%s not kept: %v", id, err)
+        }
+      }
+
+      for _, id := range tt.rejected {
+        if _, err := os.Stat( filepath.Join( dir, id + badSuffix ) ); err != nil {
+          t.Errorf("%s not set aside: %v", id, err)
+        }
+      }
+
+      journalled := 0
+
+      for _, doc := range db.docs {
+        if doc["value"] != -1 {
+          journalled++
+        }
+      }
+
+      if journalled != tt.saved {
+        t.Errorf("%d journalled documents in the database, want %d", journalled, tt.saved)
+      }
+
+      /*
+          Once the database is back every waiting document is saved, and
+          the documents set aside stay aside.
+      */
+
+      db.fail = nil
+
+      if saved, err = j.Flush( db ); saved != len(tt.pending) || err != nil {
+        t.Errorf("second flush saved %d (%v), want %d", saved, err, len(tt.pending))
+      }
+
+      if status = j.Status(); status.Pending != 0 || status.Rejected != len(tt.rejected) {
+        t.Errorf("after second flush %d pending and %d rejected, want 0 and %d", status.Pending, status.Rejected, len(tt.rejected))
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestOpen
+
+    Description    : Checks that a journal opened on the files of an
+                     earlier session counts the documents waiting and set
+                     aside, removes partly written files, and does not
+                     count a document put again twice.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestOpen( t *testing.T ) {
+  dir := t.TempDir()
+
+  for _, name := range []string{ "a" + suffix, "b" + suffix, "c" + badSuffix, "d" + tempSuffix } {
+    os.WriteFile( filepath.Join( dir, name ), []byte("{}"), 0644 )
+  }
+
+  j, err := Open( dir )
+
+  if err != nil {
+    t.Fatal( err )
+  }
+
+  if status := j.Status(); status.Pending != 2 || status.Rejected != 1 {
+    t.Errorf("%d pending and %d rejected, want 2 and 1", status.Pending, status.Rejected)
+  }
+
+  if _, err := os.Stat( filepath.Join( dir, "d" + tempSuffix ) ); !os.IsNotExist( err ) {
+    t.Errorf("partly written file kept")
+  }
+
+  j.Put( "a", map[string]interface{}{ "value": 1 } )
+  j.Put( "e/1", map[string]interface{}{ "value": 2 } )
+  j.Put( "e/1", map[string]interface{}{ "value": 3 } )
+
+  if status := j.Status(); status.Pending != 3 {
+    t.Errorf("%d pending, want 3", status.Pending)
+  }
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+        "encoding/json"
+        "fmt"
+        "log"
+        "os"
+)
+
+const DEFAULT_LIMITS_FILE = "limits.json"
+
+/* Test Limit */
+
+type Limit struct {
+  Min *int `json:"min,omitempty"`             // Lowest passing value, none if missing
+  Max *int `json:"max,omitempty"`             // Highest passing value, none if missing
+  Inclusive bool `json:"inclusive,omitempty"` // true if a value equal to a limit passes
+  Units string `json:"units,omitempty"`         // Units of the value, recorded with each result
+  Note string `json:"note,omitempty"`           // Remark for whoever edits the file, not used by the tester
+  missing bool                               // No limit was found for the test
+}
+
+/* Limit Set */
+
+type LimitSet struct {
+  Version string `json:"version"`                           // Version of the limits, recorded with each result
+  Limits map[string]Limit `json:"limits"`                   // Limits keyed by test name
+  Assemblies map[string]map[string]Limit `json:"assemblies"` // Overrides keyed by assembly part number, then test name
+}
+
+var Limits LimitSet                        // Limits in use for this session
+
+/*
+    Procedure Name : LoadLimits
+
+    Description    : Reads the test limits file. Unlike the configuration
+                     file the limits file must exist, there are no built in
+                     limits.
+
+    Arguments      : path - Name of the limits file
+
+    Return Value   : The limits
+                     Any error reading or decoding the file
+*/
+
+func LoadLimits( path string ) (LimitSet, error) {
+  var limits LimitSet
+
+  data, err := os.ReadFile( path )
+
+  if err != nil {
+    return limits, err
+  }
+
+  if err = json.Unmarshal( data, &limits ); err != nil {
+    return limits, fmt.Errorf("Bad limits file %s: %v", path, err)
+  }
+
+  if limits.Version == "" {
+    return limits, fmt.Errorf("Limits file %s has no version", path)
+  }
+
+  return limits, nil
+}
+
+/*
+    Procedure Name : Lookup
+
+    Description    : Finds the limit for a test. A limit given for the
+                     assembly part number takes priority over the default
+                     limit for the test.
+
+    Arguments      : test     - Name of the test
+                     assembly - Assembly part number
+
+    Return Value   : The limit
+                     false if there is no limit for the test
+*/
+
+func (s LimitSet) Lookup( test string, assembly string ) (Limit, bool) {
+  if limit, ok := s.Assemblies[assembly][test]; ok {
+    return limit, true
+  }
+
+  limit, ok := s.Limits[test]
+
+  return limit, ok
+}
+
+/*
+    Procedure Name : TestLimit
+
+    Description    : Returns the limit for a test on the assembly being
+                     tested. A test without a limit is reported and the
+                     returned limit fails every value.
+
+    Arguments      : test - Name of the test
+
+    Return Value   : The limit
+*/
+
+func TestLimit( test string ) Limit {
+  limit, ok := Limits.Lookup( test, AssemblyPartNumber )
+
+  if !ok {
+    log.Printf("No limit for %q (assembly %s) in limits version %q", test, AssemblyPartNumber, Limits.Version)
+
+    limit = Limit{ missing: true }
+  }
+
+  return limit
+}
+
+/*
+    Procedure Name : Check
+
+    Description    : Checks a value against the limit.
+
+    Arguments      : value - Measured value
+
+    Return Value   : true if the value passes
+*/
+
+func (l Limit) Check( value int ) bool {
+  if l.missing {
+    return false
+  }
+
+  if l.Min != nil {
+    if value < *l.Min || (value == *l.Min && !l.Inclusive) {
+      return false
+    }
+  }
+
+  if l.Max != nil {
+    if value > *l.Max || (value == *l.Max && !l.Inclusive) {
+      return false
+    }
+  }
+
+  return true
+}
+
+/*
+    Procedure Name : String
+
+    Description    : Describes the limit for failure messages, for
+                     example "> 1900 and < 2100".
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Description of the passing values
+*/
+
+func (l Limit) String() string {
+  if l.missing {
+    return "a limit (none found)"
+  }
+
+  above, below := ">", "<"
+
+  if l.Inclusive {
+    above, below = ">=", "<="
+  }
+
+  switch {
+    case l.Min != nil && l.Max != nil :
+      return fmt.Sprintf("%s %d and %s %d", above, *l.Min, below, *l.Max)
+
+    case l.Min != nil :
+      return fmt.Sprintf("%s %d", above, *l.Min)
+
+    case l.Max != nil :
+      return fmt.Sprintf("%s %d", below, *l.Max)
+  }
+
+  return "any value"
+}
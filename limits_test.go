@@ -0,0 +1,202 @@
+package main
+
+import (
+        "os"
+        "path/filepath"
+        "strings"
+        "testing"
+)
+
+/*
+    Procedure Name : TestLimitCheck
+
+    Description    : Checks values against one and two sided limits, with
+                     and without the limits themselves passing.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestLimitCheck( t *testing.T ) {
+  min, max := 1900, 2100
+
+  tests := []struct {
+    name string
+    limit Limit
+    value int
+    want bool
+    text string
+  }{
+    { "inside", Limit{ Min: &min, Max: &max }, 2000, true, "> 1900 and < 2100" },
+
+    { "on the lower limit", Limit{ Min: &min, Max: &max }, 1900, false, "> 1900 and < 2100" },
+
+    { "on the upper limit", Limit{ Min: &min, Max: &max }, 2100, false, "> 1900 and < 2100" },
+
+    { "on the lower limit, inclusive", Limit{ Min: &min, Max: &max, Inclusive: true }, 1900, true, ">= 1900 and <= 2100" },
+
+    { "on the upper limit, inclusive", Limit{ Min: &min, Max: &max, Inclusive: true }, 2100, true, ">= 1900 and <= 2100" },
+
+    { "below", Limit{ Min: &min, Max: &max, Inclusive: true }, 1899, false, ">= 1900 and <= 2100" },
+
+    { "above", Limit{ Min: &min, Max: &max, Inclusive: true }, 2101, false, ">= 1900 and <= 2100" },
+
+    { "lower only", Limit{ Min: &min }, 5000, true, "> 1900" },
+
+    { "upper only", Limit{ Max: &max }, -5000, true, "< 2100" },
+
+    { "no limits", Limit{}, 0, true, "any value" },
+
+    { "missing", Limit{ missing: true }, 2000, false, "a limit (none found)" },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      if got := tt.limit.Check( tt.value ); got != tt.want {
+        t.Errorf("Check(%d) %v, want %v", tt.value, got, tt.want)
+      }
+
+      if got := tt.limit.String(); got != tt.text {
+        t.Errorf("String() %q, want %q", got, tt.text)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestLimitLookup
+
+    Description    : Checks that a limit given for an assembly takes
+                     priority over the default limit of the test.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestLimitLookup( t *testing.T ) {
+  low, high := 10, 20
+
+  set := LimitSet{
+    Version: "1",
+    Limits: map[string]Limit{ "Volts": { Min: &low } },
+    Assemblies: map[string]map[string]Limit{ "251470-200": { "Volts": { Min: &high } } },
+  }
+
+  tests := []struct {
+    name string
+    test string
+    assembly string
+    want *int
+    found bool
+  }{
+    { "default", "Volts", "251470-100", &low, true },
+
+    { "assembly override", "Volts", "251470-200", &high, true },
+
+    { "unknown test", "Amps", "251470-200", nil, false },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      limit, ok := set.Lookup( tt.test, tt.assembly )
+
+      if ok != tt.found || limit.Min != tt.want {
+        t.Errorf("Lookup %+v %v, want min %v found %v", limit, ok, tt.want, tt.found)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestLoadLimits
+
+    Description    : Checks that the limits file is read, and that a file
+                     that is missing, cannot be decoded or has no version
+                     is refused.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestLoadLimits( t *testing.T ) {
+  dir := t.TempDir()
+
+  tests := []struct {
+    name string
+    data string
+    err string
+  }{
+    { "good", `{ "version": "7", "limits": { "Volts": { "min": 1, "note": "for people" } } }`, "" },
+
+    { "no version", `{ "limits": {} }`, "has no version" },
+
+    { "bad JSON", `{ "version": "7", "limits": { "Volts": { "min": "one" } } }`, "Bad limits file" },
+
+    { "missing", "", "no such file" },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      path := filepath.Join( dir, strings.ReplaceAll( tt.name, " ", "_" ) + ".json" )
+
+      if tt.data != "" {
+        if err := os.WriteFile( path, []byte(tt.data), 0644 ); err != nil {
+          t.Fatal(err)
+        }
+      }
+
+      limits, err := LoadLimits( path )
+
+      switch {
+        case tt.err == "" && err != nil :
+          t.Errorf("LoadLimits: %v", err)
+
+        case tt.err == "" && (limits.Version != "7" || *limits.Limits["Volts"].Min != 1) :
+          t.Errorf("limits %+v", limits)
+
+        case tt.err != "" && (err == nil || !strings.Contains( err.Error(), tt.err )) :
+          t.Errorf("error %v, want %q", err, tt.err)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestShippedLimits
+
+    Description    : Checks that the limits file shipped with the tester
+                     loads and has a limit for every limited check of the
+                     shipped sequences.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestShippedLimits( t *testing.T ) {
+  limits, err := LoadLimits( DEFAULT_LIMITS_FILE )
+
+  if err != nil {
+    t.Fatalf("LoadLimits: %v", err)
+  }
+
+  seq, err := LoadSequence( filepath.Join( DEFAULT_SEQUENCE_DIR, CPU_MAIN_SEQUENCE ) )
+
+  if err != nil {
+    t.Fatalf("LoadSequence: %v", err)
+  }
+
+  for _, step := range seq.Steps {
+    for _, check := range step.Checks {
+      switch check.Type {
+        case CHECK_RANGE, CHECK_LOWER, CHECK_UPPER :
+          if _, ok := limits.Lookup( check.Name, "" ); !ok {
+            t.Errorf("no limit for %q", check.Name)
+          }
+      }
+    }
+  }
+}
@@ -0,0 +1,594 @@
+package replay
+
+import (
+        "bufio"
+        "bytes"
+        "encoding/json"
+        "errors"
+        "fmt"
+        "io"
+        "os"
+        "sync"
+        "time"
+
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+const POLL_INTERVAL = time.Millisecond     // How often a read checks for bytes coming due
+
+var ErrMismatch = errors.New("replay: does not match the recording")
+
+/* Recorded run, as found in a recording */
+
+type Recorded struct {
+  Run Run                                  // Run as it was started
+  Verdict string                           // Verdict it ended with, empty if it did not end
+  begin int                                // Index of its begin event
+}
+
+/* Player */
+
+type Player struct {
+  mu sync.Mutex                            // Protects everything below
+  events []Event                           // The recording
+  runs []Recorded                          // Runs in the recording
+
+  limit int                                // Index of the begin or end event after the part being played
+  link int                                 // Index of the next link event to play
+  fix int                                  // Index of the next fixture event to play
+  console int                              // Index of the next console event to play
+  consoleOffset int                        // Bytes of the next console event already read
+
+  anchor time.Time                         // When the last anchor event was played
+  anchorTime int64                         // Recorded time of the last anchor event
+
+  buffer []byte                            // IEM bytes that are due and not yet read
+  latches *fixture.Fake                    // Fixture latches as the tester set them
+  mismatches []error                       // Differences from the recording
+}
+
+/* Link that is played back */
+
+type playedLink struct {
+  p *Player                                // Recording being played
+  timeout time.Duration                    // Read timeout
+}
+
+/* Fixture that is played back */
+
+type playedFixture struct {
+  p *Player                                // Recording being played
+}
+
+/* Console input that is played back */
+
+type playedConsole struct {
+  p *Player                                // Recording being played
+}
+
+/*
+    Procedure Name : Load
+
+    Description    : Reads a recording. Playback starts at the beginning,
+                     up to the first run.
+
+    Arguments      : path - Recording file
+
+    Return Value   : The player
+                     Any error reading the file
+*/
+
+func Load( path string ) (*Player, error) {
+  f, err := os.Open( path )
+
+  if err != nil {
+    return nil, err
+  }
+
+  defer f.Close()
+
+  p := &Player{ latches: fixture.NewFake() }
+
+  scanner := bufio.NewScanner( f )
+
+  scanner.Buffer( make([]byte, 64*1024), 16*1024*1024 )
+
+  for line := 1;scanner.Scan();line++ {
+    var e Event
+
+    err = json.Unmarshal( scanner.Bytes(), &e )
+
+    if err != nil {
+      return nil, fmt.Errorf("%s line %d: %s", path, line, err)
+    }
+
+    switch {
+      case e.Kind == BEGIN && e.Run != nil :
+        p.runs = append(p.runs, Recorded{ Run: *e.Run, begin: len(p.events) })
+
+      case e.Kind == END && len(p.runs) > 0 :
+        p.runs[len(p.runs) - 1].Verdict = e.Verdict
+    }
+
+    p.events = append(p.events, e)
+  }
+
+  err = scanner.Err()
+
+  if err != nil {
+    return nil, err
+  }
+
+  p.start( 0 )
+
+  return p, nil
+}
+
+/*
+    Procedure Name : Runs
+
+    Description    : Returns the runs in the recording, in order.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The runs
+*/
+
+func (p *Player) Runs() []Recorded {
+  return p.runs
+}
+
+/*
+    Procedure Name : Seek
+
+    Description    : Starts playing a run from its begin event. Anything
+                     left of the part played before, such as the menu
+                     between two runs, is dropped with its differences.
+
+    Arguments      : run - Index of the run in Runs
+
+    Return Value   : This routine has no return value.
+*/
+
+func (p *Player) Seek( run int ) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  p.mismatches = nil
+
+  p.start( p.runs[run].begin + 1 )
+}
+
+/*
+    Procedure Name : Mismatches
+
+    Description    : Returns the writes to the IEM and the fixture that
+                     differed from the recording since the last Seek or
+                     Load.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The differences
+*/
+
+func (p *Player) Mismatches() []error {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  return append([]error(nil), p.mismatches...)
+}
+
+/*
+    Procedure Name : Missing
+
+    Description    : Returns the first write to the IEM and to the
+                     fixture that was recorded in the part being played
+                     but has not been made, as for a test that stopped
+                     sooner than it did in the recording.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The writes not made, as differences
+*/
+
+func (p *Player) Missing() []error {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  var missing []error
+
+  for i := p.next( p.link, TX );i < p.limit;i = p.next( i + 1, TX ) {
+    if p.events[i].Kind == TX {
+      missing = append(missing, fmt.Errorf("%w: link: % x was recorded but never sent", ErrMismatch, p.events[i].Data))
+
+      break
+    }
+  }
+
+  if i := p.next( p.fix, FIXTURE ); i < p.limit {
+    missing = append(missing, fmt.Errorf("%w: fixture: %s was recorded but never made", ErrMismatch, describe( p.events[i] )))
+  }
+
+  return missing
+}
+
+/*
+    Procedure Name : start
+
+    Description    : Positions every stream at an event and limits them
+                     to the events before the end of the run, or before
+                     the first run at power up. The caller must hold the
+                     player lock, except from Load.
+
+    Arguments      : first - Index of the first event to play
+
+    Return Value   : This routine has no return value.
+*/
+
+func (p *Player) start( first int ) {
+  p.limit = len(p.events)
+
+  for i := first;i < len(p.events);i++ {
+    if p.events[i].Kind == BEGIN || p.events[i].Kind == END {
+      p.limit = i
+
+      break
+    }
+  }
+
+  p.link, p.fix, p.console = first, first, first
+  p.consoleOffset = 0
+  p.buffer = nil
+
+  p.anchor = time.Now()
+
+  if first > 0 {
+    p.anchorTime = p.events[first - 1].Time
+  } else {
+    p.anchorTime = 0
+  }
+}
+
+/*
+    Procedure Name : next
+
+    Description    : Finds the next event of the link, fixture or console
+                     stream. The caller must hold the player lock.
+
+    Arguments      : i    - Index to start looking at
+                     kind - TX or RX for the link, FIXTURE or CONSOLE
+
+    Return Value   : Index of the event, limit if there is none
+*/
+
+func (p *Player) next( i int, kind string ) int {
+  link := kind == TX || kind == RX
+
+  for ;i < p.limit;i++ {
+    k := p.events[i].Kind
+
+    if k == kind || (link && (k == TX || k == RX)) {
+      return i
+    }
+  }
+
+  return p.limit
+}
+
+/*
+    Procedure Name : release
+
+    Description    : Moves the IEM bytes that have come due to the read
+                     buffer. Bytes are due as long after the last write
+                     as they were in the recording; none are due past the
+                     next write, which has to be made first. The caller
+                     must hold the player lock.
+
+    Arguments      : now - Current time
+                     all - Release the bytes before the next write even if
+                           they are not due yet
+
+    Return Value   : This routine has no return value.
+*/
+
+func (p *Player) release( now time.Time, all bool ) {
+  for {
+    i := p.next( p.link, RX )
+
+    if i >= p.limit || p.events[i].Kind != RX {
+      return
+    }
+
+    due := p.anchor.Add( (time.Duration) (p.events[i].Time - p.anchorTime) * time.Microsecond )
+
+    if !all && now.Before( due ) {
+      return
+    }
+
+    p.buffer = append(p.buffer, p.events[i].Data...)
+    p.link = i + 1
+  }
+}
+
+/*
+    Procedure Name : mismatch
+
+    Description    : Records a difference from the recording. The caller
+                     must hold the player lock.
+
+    Arguments      : format - Description, as for fmt.Sprintf
+                     a      - Arguments of the description
+
+    Return Value   : The difference, wrapping ErrMismatch
+*/
+
+func (p *Player) mismatch( format string, a ...interface{} ) error {
+  err := fmt.Errorf("%w: " + format, append([]interface{}{ ErrMismatch }, a...)...)
+
+  p.mismatches = append(p.mismatches, err)
+
+  return err
+}
+
+/*
+    Procedure Name : describe
+
+    Description    : Describes a fixture operation for a message.
+
+    Arguments      : e - Fixture event
+
+    Return Value   : The description
+*/
+
+func describe( e Event ) string {
+  switch e.Op {
+    case CONFIGURE, UPDATE :
+      return fmt.Sprintf("%s(%d, 0x%04x, 0x%04x)", e.Op, e.Chip, e.A, e.B)
+
+    case READ :
+      return fmt.Sprintf("%s(%d)", e.Op, e.Chip)
+  }
+
+  return fmt.Sprintf("%s(%d, 0x%04x)", e.Op, e.Chip, e.A)
+}
+
+/*
+    Procedure Name : Link
+
+    Description    : Returns the link to the recorded IEM. The bytes the
+                     IEM sent before the first write come due from now.
+                     Reads wait up to the timeout for recorded bytes to
+                     come due, and every write must be the next one
+                     recorded.
+
+    Arguments      : timeout - Read timeout
+
+    Return Value   : The link
+*/
+
+func (p *Player) Link( timeout time.Duration ) transport.Transport {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  p.anchor = time.Now()
+
+  return &playedLink{ p: p, timeout: timeout }
+}
+
+/*
+    Procedure Name : Fixture
+
+    Description    : Returns the recorded test fixture. Every operation
+                     must be the next one recorded, and inputs read back
+                     as they were recorded.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The fixture
+*/
+
+func (p *Player) Fixture() fixture.Fixture {
+  return &playedFixture{ p: p }
+}
+
+/*
+    Procedure Name : Console
+
+    Description    : Returns the recorded console input. It ends with
+                     io.EOF where the recording of the run does.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The console input
+*/
+
+func (p *Player) Console() io.Reader {
+  return &playedConsole{ p: p }
+}
+
+func (l *playedLink) Read( b []byte ) (int, error) {
+  deadline := time.Now().Add( l.timeout )
+
+  for {
+    l.p.mu.Lock()
+
+    l.p.release( time.Now(), false )
+
+    if len(l.p.buffer) > 0 {
+      n := copy( b, l.p.buffer )
+
+      l.p.buffer = l.p.buffer[n:]
+
+      l.p.mu.Unlock()
+
+      return n, nil
+    }
+
+    l.p.mu.Unlock()
+
+    if !time.Now().Before( deadline ) {
+      return 0, io.EOF
+    }
+
+    time.Sleep( POLL_INTERVAL )
+  }
+}
+
+func (l *playedLink) Write( b []byte ) (int, error) {
+  p := l.p
+
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  /*
+      Whatever the IEM sent before this write in the recording has
+      arrived by now, even if the tester got here sooner than it did.
+  */
+
+  p.release( time.Now(), true )
+
+  i := p.next( p.link, TX )
+
+  if i >= p.limit {
+    return 0, p.mismatch("link: sent % x, nothing more was recorded", b)
+  }
+
+  p.link = i + 1
+
+  p.anchor = time.Now()
+  p.anchorTime = p.events[i].Time
+
+  if !bytes.Equal( b, p.events[i].Data ) {
+    return 0, p.mismatch("link: sent % x, recorded % x", b, p.events[i].Data)
+  }
+
+  return len(b), nil
+}
+
+func (l *playedLink) Flush() error {
+  l.p.mu.Lock()
+  defer l.p.mu.Unlock()
+
+  l.p.release( time.Now(), false )
+
+  l.p.buffer = nil
+
+  return nil
+}
+
+func (l *playedLink) Close() error {
+  return nil
+}
+
+/*
+    Procedure Name : play
+
+    Description    : Checks a fixture operation against the next one
+                     recorded and applies it to the latches.
+
+    Arguments      : e     - Operation made, without a time
+                     apply - Applies it to the latches, nil for a read
+
+    Return Value   : The recorded operation
+                     A difference from the recording
+*/
+
+func (f *playedFixture) play( e Event, apply func() error ) (Event, error) {
+  p := f.p
+
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  if apply != nil {
+    apply()
+  }
+
+  i := p.next( p.fix, FIXTURE )
+
+  if i >= p.limit {
+    return e, p.mismatch("fixture: %s, nothing more was recorded", describe( e ))
+  }
+
+  p.fix = i + 1
+
+  recorded := p.events[i]
+
+  if recorded.Op != e.Op || recorded.Chip != e.Chip || recorded.A != e.A || recorded.B != e.B {
+    return recorded, p.mismatch("fixture: %s, recorded %s", describe( e ), describe( recorded ))
+  }
+
+  return recorded, nil
+}
+
+func (f *playedFixture) Configure( chip int, latch uint16, direction uint16 ) error {
+  _, err := f.play( Event{ Op: CONFIGURE, Chip: chip, A: latch, B: direction }, func() error { return f.p.latches.Configure( chip, latch, direction ) } )
+
+  return err
+}
+
+func (f *playedFixture) Write( chip int, latch uint16 ) error {
+  _, err := f.play( Event{ Op: WRITE, Chip: chip, A: latch }, func() error { return f.p.latches.Write( chip, latch ) } )
+
+  return err
+}
+
+func (f *playedFixture) Update( chip int, clear uint16, set uint16 ) error {
+  _, err := f.play( Event{ Op: UPDATE, Chip: chip, A: clear, B: set }, func() error { return f.p.latches.Update( chip, clear, set ) } )
+
+  return err
+}
+
+func (f *playedFixture) Set( chip int, mask uint16 ) error {
+  _, err := f.play( Event{ Op: SET, Chip: chip, A: mask }, func() error { return f.p.latches.Set( chip, mask ) } )
+
+  return err
+}
+
+func (f *playedFixture) Clear( chip int, mask uint16 ) error {
+  _, err := f.play( Event{ Op: CLEAR, Chip: chip, A: mask }, func() error { return f.p.latches.Clear( chip, mask ) } )
+
+  return err
+}
+
+func (f *playedFixture) ReadInputs( chip int ) (uint16, error) {
+  recorded, err := f.play( Event{ Op: READ, Chip: chip }, nil )
+
+  if err != nil {
+    return 0, err
+  }
+
+  return recorded.Value, nil
+}
+
+func (f *playedFixture) Snapshot() fixture.Snapshot {
+  return f.p.latches.Snapshot()
+}
+
+func (f *playedFixture) Close() error {
+  return nil
+}
+
+func (c *playedConsole) Read( b []byte ) (int, error) {
+  p := c.p
+
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  i := p.next( p.console, CONSOLE )
+
+  if i >= p.limit {
+    return 0, io.EOF
+  }
+
+  n := copy( b, p.events[i].Data[p.consoleOffset:] )
+
+  p.consoleOffset += n
+
+  if p.consoleOffset >= len(p.events[i].Data) {
+    p.console = i + 1
+    p.consoleOffset = 0
+  }
+
+  return n, nil
+}
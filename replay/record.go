@@ -0,0 +1,328 @@
+/*
+   Package replay records a session with a real IEM and plays it back, so
+   the tests can be run again offline against exactly the bytes a real
+   unit sent.
+
+   A recording is a file of JSON lines, one event per line, in the order
+   they happened: bytes written to the IEM, bytes read from it, every
+   operation on the test fixture, console input, and the start and end
+   of each run. When it is played back the IEM's bytes come back with
+   the same timing after the request they followed, the fixture inputs
+   read back as they were recorded, and every write to the IEM and the
+   fixture is checked against the recording.
+*/
+
+package replay
+
+import (
+        "encoding/json"
+        "io"
+        "os"
+        "path/filepath"
+        "sync"
+        "time"
+
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/results"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+const SUFFIX = ".capture"                  // Suffix of a recording
+
+/* Event Kinds */
+
+const (
+  TX = "tx"                                // Bytes written to the IEM
+  RX = "rx"                                // Bytes read from the IEM
+  FIXTURE = "fixture"                      // Operation on the test fixture
+  CONSOLE = "console"                      // Console input
+  BEGIN = "begin"                          // Start of a run
+  END = "end"                              // End of a run
+)
+
+/* Fixture Operations */
+
+const (
+  CONFIGURE = "configure"                  // A is the latch, B the directions
+  WRITE = "write"                          // A is the latch
+  UPDATE = "update"                        // A is cleared, B is set
+  SET = "set"                              // A is set
+  CLEAR = "clear"                          // A is cleared
+  READ = "read"                            // Value is the inputs read
+)
+
+/* Run, as recorded at its start */
+
+type Run struct {
+  Test string `json:"test"`                                 // Test that was run
+  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
+  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
+  Modules []results.Module `json:"modules"`                 // Modules entered for the assembly
+}
+
+/* Recorded Event */
+
+type Event struct {
+  Time int64 `json:"us"`                  // Microseconds since the recording started
+  Kind string `json:"kind"`               // One of the event kinds
+  Data []byte `json:"data,omitempty"`     // Bytes of a tx, rx or console event
+  Op string `json:"op,omitempty"`         // Fixture operation
+  Chip int `json:"chip,omitempty"`        // Port extender of a fixture operation
+  A uint16 `json:"a,omitempty"`           // First argument of a fixture operation
+  B uint16 `json:"b,omitempty"`           // Second argument of a fixture operation
+  Value uint16 `json:"value,omitempty"`   // Inputs read from the fixture
+  Run *Run `json:"run,omitempty"`         // Run started by a begin event
+  Verdict string `json:"verdict,omitempty"` // Verdict of the run ended by an end event
+}
+
+/* Recorder */
+
+type Recorder struct {
+  mu sync.Mutex                            // Protects the file
+  file *os.File                            // Recording being written
+  encoder *json.Encoder                    // Writes the events to file
+  start time.Time                          // When the recording started
+}
+
+/* Link that is recorded */
+
+type recordedLink struct {
+  transport.Transport                      // Link to the IEM
+  r *Recorder                              // Where the events go
+}
+
+/* Fixture that is recorded */
+
+type recordedFixture struct {
+  fixture.Fixture                          // Test fixture
+  r *Recorder                              // Where the events go
+}
+
+/* Console input that is recorded */
+
+type recordedConsole struct {
+  source io.Reader                         // Console input
+  r *Recorder                              // Where the events go
+}
+
+/*
+    Procedure Name : Create
+
+    Description    : Starts a recording in a directory, creating it if
+                     needed. The file is named for the time.
+
+    Arguments      : dir - Recording directory
+
+    Return Value   : The recorder
+                     Any error creating the directory or the file
+*/
+
+func Create( dir string ) (*Recorder, error) {
+  err := os.MkdirAll( dir, 0755 )
+
+  if err != nil {
+    return nil, err
+  }
+
+  start := time.Now()
+
+  name := "session-" + start.UTC().Format( "20060102T150405Z" ) + SUFFIX
+
+  f, err := os.Create( filepath.Join( dir, name ) )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return &Recorder{ file: f, encoder: json.NewEncoder( f ), start: start }, nil
+}
+
+/*
+    Procedure Name : Name
+
+    Description    : Returns the file name of the recording.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The file name
+*/
+
+func (r *Recorder) Name() string {
+  return r.file.Name()
+}
+
+/*
+    Procedure Name : record
+
+    Description    : Stamps an event with the time and writes it. A
+                     recording that cannot be written is not allowed to
+                     stop a test, so errors are ignored.
+
+    Arguments      : e - Event
+
+    Return Value   : This routine has no return value.
+*/
+
+func (r *Recorder) record( e Event ) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  e.Time = time.Since( r.start ).Microseconds()
+
+  r.encoder.Encode( e )
+}
+
+/*
+    Procedure Name : Link
+
+    Description    : Returns a link that records everything written to
+                     and read from the IEM through it.
+
+    Arguments      : port - Link to the IEM
+
+    Return Value   : The recorded link
+*/
+
+func (r *Recorder) Link( port transport.Transport ) transport.Transport {
+  return &recordedLink{ Transport: port, r: r }
+}
+
+/*
+    Procedure Name : Fixture
+
+    Description    : Returns a fixture that records every operation made
+                     on it.
+
+    Arguments      : fix - Test fixture
+
+    Return Value   : The recorded fixture
+*/
+
+func (r *Recorder) Fixture( fix fixture.Fixture ) fixture.Fixture {
+  return &recordedFixture{ Fixture: fix, r: r }
+}
+
+/*
+    Procedure Name : Console
+
+    Description    : Returns console input that records everything read
+                     from it.
+
+    Arguments      : source - Console input
+
+    Return Value   : The recorded console input
+*/
+
+func (r *Recorder) Console( source io.Reader ) io.Reader {
+  return &recordedConsole{ source: source, r: r }
+}
+
+/*
+    Procedure Name : Begin
+
+    Description    : Marks the start of a run. Playback starts each run
+                     from its mark.
+
+    Arguments      : run - Run being started
+
+    Return Value   : This routine has no return value.
+*/
+
+func (r *Recorder) Begin( run Run ) {
+  r.record( Event{ Kind: BEGIN, Run: &run } )
+}
+
+/*
+    Procedure Name : End
+
+    Description    : Marks the end of a run with its verdict.
+
+    Arguments      : verdict - Verdict of the run
+
+    Return Value   : This routine has no return value.
+*/
+
+func (r *Recorder) End( verdict string ) {
+  r.record( Event{ Kind: END, Verdict: verdict } )
+}
+
+/*
+    Procedure Name : Close
+
+    Description    : Finishes the recording.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error closing the file
+*/
+
+func (r *Recorder) Close() error {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  return r.file.Close()
+}
+
+func (l *recordedLink) Read( b []byte ) (int, error) {
+  n, err := l.Transport.Read( b )
+
+  if n > 0 {
+    l.r.record( Event{ Kind: RX, Data: append([]byte(nil), b[:n]...) } )
+  }
+
+  return n, err
+}
+
+func (l *recordedLink) Write( b []byte ) (int, error) {
+  l.r.record( Event{ Kind: TX, Data: append([]byte(nil), b...) } )
+
+  return l.Transport.Write( b )
+}
+
+func (f *recordedFixture) Configure( chip int, latch uint16, direction uint16 ) error {
+  f.r.record( Event{ Kind: FIXTURE, Op: CONFIGURE, Chip: chip, A: latch, B: direction } )
+
+  return f.Fixture.Configure( chip, latch, direction )
+}
+
+func (f *recordedFixture) Write( chip int, latch uint16 ) error {
+  f.r.record( Event{ Kind: FIXTURE, Op: WRITE, Chip: chip, A: latch } )
+
+  return f.Fixture.Write( chip, latch )
+}
+
+func (f *recordedFixture) Update( chip int, clear uint16, set uint16 ) error {
+  f.r.record( Event{ Kind: FIXTURE, Op: UPDATE, Chip: chip, A: clear, B: set } )
+
+  return f.Fixture.Update( chip, clear, set )
+}
+
+func (f *recordedFixture) Set( chip int, mask uint16 ) error {
+  f.r.record( Event{ Kind: FIXTURE, Op: SET, Chip: chip, A: mask } )
+
+  return f.Fixture.Set( chip, mask )
+}
+
+func (f *recordedFixture) Clear( chip int, mask uint16 ) error {
+  f.r.record( Event{ Kind: FIXTURE, Op: CLEAR, Chip: chip, A: mask } )
+
+  return f.Fixture.Clear( chip, mask )
+}
+
+func (f *recordedFixture) ReadInputs( chip int ) (uint16, error) {
+  inputs, err := f.Fixture.ReadInputs( chip )
+
+  f.r.record( Event{ Kind: FIXTURE, Op: READ, Chip: chip, Value: inputs } )
+
+  return inputs, err
+}
+
+func (c *recordedConsole) Read( b []byte ) (int, error) {
+  n, err := c.source.Read( b )
+
+  if n > 0 {
+    c.r.record( Event{ Kind: CONSOLE, Data: append([]byte(nil), b[:n]...) } )
+  }
+
+  return n, err
+}
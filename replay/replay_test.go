@@ -0,0 +1,267 @@
+package replay
+
+import (
+        "bytes"
+        "errors"
+        "io"
+        "strings"
+        "testing"
+        "time"
+
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/transport"
+)
+
+var request = []byte{ 0x01, 0x03, 0x01, 0xaa, 0x55 }      // Request the recorded tester sent
+var response = []byte{ 0x01, 0x03, 0x10, 0x20, 0x5a, 0xa5 } // Answer the recorded IEM gave
+
+/* IEM that answers every write with the next canned response */
+
+type scripted struct {
+  responses [][]byte                       // Responses still to give
+  pending []byte                           // Response to the last write, not yet read
+}
+
+func (s *scripted) Write( b []byte ) (int, error) {
+  if len(s.responses) > 0 {
+    s.pending = append(s.pending, s.responses[0]...)
+    s.responses = s.responses[1:]
+  }
+
+  return len(b), nil
+}
+
+func (s *scripted) Read( b []byte ) (int, error) {
+  if len(s.pending) == 0 {
+    return 0, io.EOF
+  }
+
+  n := copy( b, s.pending )
+
+  s.pending = s.pending[n:]
+
+  return n, nil
+}
+
+func (s *scripted) Flush() error {
+  s.pending = nil
+
+  return nil
+}
+
+func (s *scripted) Close() error {
+  return nil
+}
+
+/*
+    Procedure Name : session
+
+    Description    : Does what a tester does in one run: asks the IEM
+                     for a reading, turns on a fixture signal, reads the
+                     fixture inputs and asks the operator a question.
+
+    Arguments      : link    - Link to the IEM
+                     fix     - Test fixture
+                     console - Console input
+
+    Return Value   : The first error met
+*/
+
+func session( link transport.Transport, fix fixture.Fixture, console io.Reader ) error {
+  _, err := link.Write( request )
+
+  if err == nil {
+    got := make([]byte, len(response))
+
+    _, err = io.ReadFull( link, got )
+
+    if err == nil && !bytes.Equal( got, response ) {
+      err = errors.New( "wrong response" )
+    }
+  }
+
+  if err == nil {
+    err = fix.Set( 1, 0x0004 )
+  }
+
+  if err == nil {
+    var inputs uint16
+
+    inputs, err = fix.ReadInputs( 0 )
+
+    if err == nil && inputs != 0x0055 {
+      err = errors.New( "wrong inputs" )
+    }
+  }
+
+  if err == nil {
+    answer := make([]byte, 2)
+
+    _, err = io.ReadFull( console, answer )
+
+    if err == nil && string(answer) != "y\n" {
+      err = errors.New( "wrong answer" )
+    }
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : record
+
+    Description    : Records one run of the session against a scripted
+                     IEM and a fake fixture.
+
+    Arguments      : t - Test state
+
+    Return Value   : The recording file
+*/
+
+func record( t *testing.T ) string {
+  r, err := Create( t.TempDir() )
+
+  if err != nil {
+    t.Fatalf("Create: %v", err)
+  }
+
+  fake := fixture.NewFake()
+
+  fake.SetInputs( 0, 0x0055 )
+
+  r.Begin( Run{ Test: "Main CPU", AssemblySerialNumber: "A1" } )
+
+  err = session( r.Link( &scripted{ responses: [][]byte{ response } } ), r.Fixture( fake ), r.Console( strings.NewReader( "y\n" ) ) )
+
+  if err != nil {
+    t.Fatalf("session: %v", err)
+  }
+
+  r.End( "pass" )
+
+  if err = r.Close(); err != nil {
+    t.Fatalf("Close: %v", err)
+  }
+
+  return r.Name()
+}
+
+/*
+    Procedure Name : TestPlay
+
+    Description    : Records a run and plays it back to testers that do
+                     the same, send something else, stop short or go on
+                     further than the recording.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestPlay( t *testing.T ) {
+  path := record( t )
+
+  tests := []struct {
+    name string
+    run func( transport.Transport, fixture.Fixture, io.Reader ) error
+    err bool
+    mismatches int
+    missing int
+  }{
+    { "same", session, false, 0, 0 },
+
+    { "different request", func( link transport.Transport, fix fixture.Fixture, console io.Reader ) error {
+        _, err := link.Write( []byte{ 0x01, 0x03, 0x02, 0xaa, 0x55 } )
+
+        return err
+      }, true, 1, 1 },
+
+    { "stopped after the request", func( link transport.Transport, fix fixture.Fixture, console io.Reader ) error {
+        _, err := link.Write( request )
+
+        return err
+      }, false, 0, 1 },
+
+    { "different fixture operation", func( link transport.Transport, fix fixture.Fixture, console io.Reader ) error {
+        _, err := link.Write( request )
+
+        if err == nil {
+          err = fix.Clear( 1, 0x0004 )
+        }
+
+        return err
+      }, true, 1, 1 },
+
+    { "more than was recorded", func( link transport.Transport, fix fixture.Fixture, console io.Reader ) error {
+        err := session( link, fix, console )
+
+        if err == nil {
+          _, err = link.Write( request )
+        }
+
+        return err
+      }, true, 1, 0 },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      p, err := Load( path )
+
+      if err != nil {
+        t.Fatalf("Load: %v", err)
+      }
+
+      runs := p.Runs()
+
+      if len(runs) != 1 || runs[0].Run.Test != "Main CPU" || runs[0].Verdict != "pass" {
+        t.Fatalf("runs %+v, want one passed Main CPU run", runs)
+      }
+
+      p.Seek( 0 )
+
+      err = tt.run( p.Link( time.Second ), p.Fixture(), p.Console() )
+
+      if (err != nil) != tt.err || (err != nil && !errors.Is( err, ErrMismatch )) {
+        t.Errorf("error %v, want a mismatch %v", err, tt.err)
+      }
+
+      if got := p.Mismatches(); len(got) != tt.mismatches {
+        t.Errorf("mismatches %v, want %d", got, tt.mismatches)
+      }
+
+      if got := p.Missing(); len(got) != tt.missing {
+        t.Errorf("missing %v, want %d", got, tt.missing)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestLoadBadLine
+
+    Description    : Checks that a recording with a line that is not an
+                     event is refused with the line number.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestLoadBadLine( t *testing.T ) {
+  r, err := Create( t.TempDir() )
+
+  if err != nil {
+    t.Fatalf("Create: %v", err)
+  }
+
+  r.End( "pass" )
+
+  r.file.WriteString( "not an event\n" )
+  r.Close()
+
+  _, err = Load( r.Name() )
+
+  if err == nil || !strings.Contains( err.Error(), "line 2" ) {
+    t.Errorf("error %v, want one for line 2", err)
+  }
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+        "bufio"
+        "flag"
+        "fmt"
+        "io"
+        "os"
+        "strings"
+
+        "github.com/questrail/IEMTestDB/replay"
+)
+
+/*
+    Procedure Name : RunReplay
+
+    Description    : The replay subcommand. Runs the tests of a recorded
+                     session again against the recording instead of an
+                     IEM and a fixture, and reports every run whose
+                     verdict changed or whose writes to the IEM or the
+                     fixture differ from the recording. Nothing is saved.
+
+                       iemtestdb replay [-config file] [-tests cpu,abcm] [-o file] recording
+
+    Arguments      : args - Command line after "replay"
+
+    Return Value   : ExitError with EXIT_FAIL if any run differs, or
+                     EXIT_TESTER if the recording could not be played
+*/
+
+func RunReplay( args []string ) error {
+  flags := flag.NewFlagSet( "replay", flag.ContinueOnError )
+
+  configFile := flags.String("config", DEFAULT_CONFIG_FILE, "tester configuration file, for the limits and sequences")
+  tests := flags.String("tests", "", "comma separated tests to replay (4-20, cpu, abcm, adcm), all of them by default")
+  output := flags.String("o", "", "write the report to a file instead of stdout")
+
+  if flags.Parse( args ) != nil {
+    return ExitError{ EXIT_TESTER }
+  }
+
+  if flags.NArg() != 1 {
+    fmt.Fprintf(os.Stderr, "usage: iemtestdb replay [-config file] [-tests list] [-o file] recording\n")
+
+    return ExitError{ EXIT_TESTER }
+  }
+
+  w := io.Writer(os.Stdout)
+
+  if *output != "" {
+    f, err := os.Create( *output )
+
+    if err != nil {
+      return err
+    }
+
+    defer f.Close()
+
+    w = f
+  }
+
+  code, err := replaySession( w, flags.Arg(0), *configFile, *tests )
+
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "%s\n", err)
+  }
+
+  if code != EXIT_PASS {
+    return ExitError{ code }
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : replaySession
+
+    Description    : Powers up the recorded unit and runs each recorded
+                     test again from its place in the recording.
+
+    Arguments      : w          - Where the report goes
+                     path       - Recording file
+                     configFile - Tester configuration file
+                     tests      - Comma separated batch test names, all
+                                  if empty
+
+    Return Value   : Exit code
+                     Any error that kept the recording from being played
+*/
+
+func replaySession( w io.Writer, path string, configFile string, tests string ) (int, error) {
+  player, err := replay.Load( path )
+
+  if err == nil {
+    err = LoadSettings( configFile )
+  }
+
+  if err != nil {
+    return EXIT_TESTER, err
+  }
+
+  wanted := map[string]bool{}
+
+  for _, key := range strings.Split( tests, "," ) {
+    for _, t := range batchTests {
+      if t.key == strings.ToLower( strings.TrimSpace( key ) ) {
+        wanted[t.name] = true
+      }
+    }
+  }
+
+  /*
+      The recording stands in for the IEM, the fixture and the operator.
+      No sinks are opened, so the replayed results are not saved, and a
+      replay is never itself recorded.
+  */
+
+  ReplayPlayer = player
+
+  Settings.Transport.Type = "replay"
+  Settings.Fixture = "replay"
+  Settings.Record = ""
+
+  Operator = "replay"
+
+  ConsoleSource = player.Console()
+  ConsoleInput = bufio.NewReader( ConsoleSource )
+
+  fix, err := PowerUp()
+
+  if fix == nil {
+    return EXIT_TESTER, err
+  }
+
+  code := EXIT_PASS
+
+  report := func( name string, mismatches []error ) {
+    if len(mismatches) > 0 {
+      code = EXIT_FAIL
+    }
+
+    for _, m := range mismatches {
+      fmt.Fprintf(w, "%s: %s\n", name, m)
+    }
+  }
+
+  if err != nil {
+    report( "Power up", []error{ err } )
+  }
+
+  /*
+      Between power up and the first run the menu may have asked the IEM
+      for more than is played here, so only differences count.
+  */
+
+  report( "Power up", player.Mismatches() )
+
+  for i, run := range player.Runs() {
+    if len(wanted) > 0 && !wanted[run.Run.Test] {
+      continue
+    }
+
+    player.Seek( i )
+
+    err = setAssembly( run.Run.AssemblyPartNumber, run.Run.AssemblySerialNumber )
+
+    alerter := AssemblyType()
+
+    if err == nil && alerter != IEM_ADCM_ONLY {
+      err = applyModules( run.Run.Modules, alerter )
+    }
+
+    if err != nil {
+      report( run.Run.Test, []error{ err } )
+
+      continue
+    }
+
+    ConsoleInput.Reset( ConsoleSource )
+
+    _, err = RunTest( fix, run.Run.Test, alerter )
+
+    mismatches := append(player.Mismatches(), player.Missing()...)
+
+    if CurrentRun.Verdict != run.Verdict {
+      mismatches = append(mismatches, fmt.Errorf("verdict %s, recorded %s", CurrentRun.Verdict, run.Verdict))
+    }
+
+    fmt.Fprintf(w, "%s %s: recorded %s, replayed %s", run.Run.Test, run.Run.AssemblySerialNumber, run.Verdict, CurrentRun.Verdict)
+
+    if err != nil {
+      fmt.Fprintf(w, " (%s)", err)
+    }
+
+    fmt.Fprintf(w, "\n")
+
+    report( run.Run.Test, mismatches )
+
+    if Interrupted {
+      PowerDown( fix )
+
+      return EXIT_TESTER, fmt.Errorf("replay interrupted")
+    }
+  }
+
+  PowerDown( fix )
+
+  return code, nil
+}
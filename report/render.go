@@ -0,0 +1,180 @@
+package report
+
+import (
+        "fmt"
+        "html/template"
+        "io"
+        "strings"
+        "text/tabwriter"
+)
+
+/*
+    Procedure Name : passFail
+
+    Description    : Words a pass flag for the certificate.
+
+    Arguments      : pass - Pass/Fail flag
+
+    Return Value   : "PASS" or "FAIL"
+*/
+
+func passFail( pass bool ) string {
+  if pass {
+    return "PASS"
+  }
+
+  return "FAIL"
+}
+
+/*
+    Procedure Name : Text
+
+    Description    : Writes the certificate as plain text.
+
+    Arguments      : w - Where to write it
+
+    Return Value   : Any error writing
+*/
+
+func (c Certificate) Text( w io.Writer ) error {
+  t := tabwriter.NewWriter( w, 0, 8, 2, ' ', 0 )
+
+  fmt.Fprintf(t, "TEST CERTIFICATE\n\n")
+  fmt.Fprintf(t, "Assembly Part Number\t%s\n", c.AssemblyPartNumber)
+  fmt.Fprintf(t, "Assembly Serial Number\t%s\n", c.AssemblySerialNumber)
+
+  if c.Serial != c.AssemblySerialNumber {
+    fmt.Fprintf(t, "Requested Serial Number\t%s\n", c.Serial)
+  }
+
+  fmt.Fprintf(t, "Generated\t%s\n", c.Generated)
+
+  if len(c.Modules) > 0 {
+    fmt.Fprintf(t, "\nMODULES\n\n")
+
+    for _, m := range c.Modules {
+      fmt.Fprintf(t, "%s\t%s\t%s\n", m.Name, m.PartNumber, m.SerialNumber)
+    }
+  }
+
+  if len(c.Versions) > 0 {
+    fmt.Fprintf(t, "\nVERSIONS\n\n")
+
+    for _, v := range c.Versions {
+      fmt.Fprintf(t, "%s\t%s\n", v.Name, v.Value)
+    }
+  }
+
+  for _, s := range c.Sections {
+    fmt.Fprintf(t, "\n%s - %s\n", strings.ToUpper( s.Run.Test ), strings.ToUpper( s.Run.Verdict ))
+    if s.Run.RunID == "" {
+      fmt.Fprintf(t, "Stored before runs were recorded\n\n")
+    } else {
+      fmt.Fprintf(t, "Run %s started %s by %s on %s, attempt %d\n\n", s.Run.RunID, s.Run.Start, s.Run.Operator, s.Run.Station, s.Attempts)
+    }
+    fmt.Fprintf(t, "Test\tValue\tLimits\tUnits\tResult\n")
+
+    for _, l := range s.Lines {
+      fmt.Fprintf(t, "%s\t%s\t%s\t%s\t%s\n", l.Name, l.Value, l.Limits, l.Units, passFail( l.Pass ))
+    }
+  }
+
+  fmt.Fprintf(t, "\nTEST HISTORY\n\n")
+  fmt.Fprintf(t, "Started\tTest\tVerdict\tOperator\tStation\tSoftware\n")
+
+  for _, r := range c.History {
+    fmt.Fprintf(t, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Start, r.Test, r.Verdict, r.Operator, r.Station, r.SoftwareVersion)
+  }
+
+  return t.Flush()
+}
+
+/*
+   The HTML certificate. The printable form drops the colours, sets the
+   page size and leaves room for a signature, so that it can be printed
+   from any browser instead of needing a PDF library.
+*/
+
+var htmlTemplate = template.Must( template.New("certificate").Funcs( template.FuncMap{ "passFail": passFail } ).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Test Certificate {{.AssemblySerialNumber}}</title>
+<style>
+body { font-family: sans-serif; font-size: 10pt; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #999; padding: 2px 6px; text-align: left; }
+th { background: #eee; }
+td.PASS { color: #060; font-weight: bold; }
+td.FAIL { color: #c00; font-weight: bold; }
+.signature { margin-top: 3em; }
+.signature span { display: inline-block; width: 20em; border-top: 1px solid #000; margin-right: 3em; padding-top: 2px; }
+{{if .Print}}
+@page { size: letter; margin: 15mm; }
+body { margin: 0; }
+th { background: none; }
+td.PASS, td.FAIL { color: #000; }
+h2 { page-break-after: avoid; }
+table { page-break-inside: auto; }
+tr { page-break-inside: avoid; }
+{{end}}
+</style>
+</head>
+<body>
+<h1>Test Certificate</h1>
+<table>
+<tr><th>Assembly Part Number</th><td>{{.AssemblyPartNumber}}</td></tr>
+<tr><th>Assembly Serial Number</th><td>{{.AssemblySerialNumber}}</td></tr>
+{{if ne .Serial .AssemblySerialNumber}}<tr><th>Requested Serial Number</th><td>{{.Serial}}</td></tr>{{end}}
+<tr><th>Generated</th><td>{{.Generated}}</td></tr>
+</table>
+{{if .Modules}}
+<h2>Modules</h2>
+<table>
+<tr><th>Module</th><th>Part Number</th><th>Serial Number</th></tr>
+{{range .Modules}}<tr><td>{{.Name}}</td><td>{{.PartNumber}}</td><td>{{.SerialNumber}}</td></tr>
+{{end}}</table>
+{{end}}
+{{if .Versions}}
+<h2>Versions</h2>
+<table>
+{{range .Versions}}<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}
+{{range .Sections}}
+<h2>{{.Run.Test}} &mdash; {{.Run.Verdict}}</h2>
+{{if .Run.RunID}}<p>Run {{.Run.RunID}} started {{.Run.Start}} by {{.Run.Operator}} on {{.Run.Station}}, attempt {{.Attempts}}</p>{{else}}<p>Stored before runs were recorded</p>{{end}}
+<table>
+<tr><th>Test</th><th>Value</th><th>Limits</th><th>Units</th><th>Result</th></tr>
+{{range .Lines}}<tr><td>{{.Name}}</td><td>{{.Value}}</td><td>{{.Limits}}</td><td>{{.Units}}</td><td class="{{passFail .Pass}}">{{passFail .Pass}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Test History</h2>
+<table>
+<tr><th>Started</th><th>Test</th><th>Verdict</th><th>Operator</th><th>Station</th><th>Software</th></tr>
+{{range .History}}<tr><td>{{.Start}}</td><td>{{.Test}}</td><td>{{.Verdict}}</td><td>{{.Operator}}</td><td>{{.Station}}</td><td>{{.SoftwareVersion}}</td></tr>
+{{end}}</table>
+{{if .Print}}
+<div class="signature"><span>Inspected by</span><span>Date</span></div>
+{{end}}
+</body>
+</html>
+`) )
+
+/*
+    Procedure Name : HTML
+
+    Description    : Writes the certificate as an HTML page.
+
+    Arguments      : w     - Where to write it
+                     print - true to lay the page out for printing
+
+    Return Value   : Any error writing
+*/
+
+func (c Certificate) HTML( w io.Writer, print bool ) error {
+  return htmlTemplate.Execute( w, struct {
+    Certificate
+    Print bool
+  }{ c, print } )
+}
@@ -0,0 +1,239 @@
+/*
+   Package report builds the test certificate of one unit from its stored
+   runs and results: the modules it was built from, its firmware
+   versions, the results of the latest run of each test with their
+   limits, and every earlier attempt. A certificate can be written as
+   plain text or as HTML, either for the screen or laid out for printing.
+*/
+
+package report
+
+import (
+        "fmt"
+        "sort"
+        "time"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* One line of a test section */
+
+type Line struct {
+  Name string                      // Test name
+  Value string                     // Measured or received value
+  Limits string                    // Limits or expected value, empty if none
+  Units string                     // Units of the value
+  Pass bool                        // Pass/Fail flag
+}
+
+/* The latest run of one test */
+
+type Section struct {
+  Run results.Run                  // The run
+  Attempts int                     // Number of times the test was run on the unit
+  Lines []Line                     // Results of the run
+}
+
+/* Test Certificate */
+
+type Certificate struct {
+  Serial string                    // Serial number the certificate was asked for
+  AssemblyPartNumber string        // Assembly Part Number
+  AssemblySerialNumber string      // Assembly Serial Number
+  Modules []results.Module         // Modules of the assembly, from the latest run
+  Versions []Line                  // Firmware and hardware versions, from the latest runs
+  Sections []Section               // Latest run of each test
+  History []results.Run            // Every run, oldest first
+  Generated string                 // When the certificate was made
+}
+
+/*
+    Procedure Name : value
+
+    Description    : Formats the measured value of a result.
+
+    Arguments      : r - Result
+
+    Return Value   : The value
+*/
+
+func value( r results.TestResult ) string {
+  switch {
+    case r.Text != "" :
+      return r.Text
+
+    case r.Value == nil :
+      return ""
+
+    case r.Kind == results.MATCH :
+      return fmt.Sprintf("0x%X", *r.Value)
+  }
+
+  return fmt.Sprint( *r.Value )
+}
+
+/*
+    Procedure Name : limits
+
+    Description    : Formats the limits or expected value of a result.
+                     Limits that a value may equal are shown as at least
+                     or at most.
+
+    Arguments      : r - Result
+
+    Return Value   : The limits, empty if the result has none
+*/
+
+func limits( r results.TestResult ) string {
+  switch {
+    case r.Expected != nil :
+      return fmt.Sprintf("0x%X", *r.Expected)
+
+    case r.LowerLimit != nil && r.UpperLimit != nil :
+      return fmt.Sprintf("%d to %d", *r.LowerLimit, *r.UpperLimit)
+
+    case r.LowerLimit != nil && r.Inclusive != nil && *r.Inclusive :
+      return fmt.Sprintf("at least %d", *r.LowerLimit)
+
+    case r.LowerLimit != nil :
+      return fmt.Sprintf("above %d", *r.LowerLimit)
+
+    case r.UpperLimit != nil && r.Inclusive != nil && *r.Inclusive :
+      return fmt.Sprintf("at most %d", *r.UpperLimit)
+
+    case r.UpperLimit != nil :
+      return fmt.Sprintf("below %d", *r.UpperLimit)
+  }
+
+  return ""
+}
+
+/*
+    Procedure Name : line
+
+    Description    : Makes the certificate line of a result.
+
+    Arguments      : r - Result
+
+    Return Value   : The line
+*/
+
+func line( r results.TestResult ) Line {
+  return Line{ Name: r.TestName, Value: value( r ), Limits: limits( r ), Units: r.Units, Pass: r.Pass }
+}
+
+/*
+    Procedure Name : Build
+
+    Description    : Builds the certificate of a unit. Results that do not
+                     belong to any of the runs, such as results stored
+                     before runs were recorded, are gathered into one run
+                     named "Earlier results".
+
+    Arguments      : serial      - Assembly or module serial number asked for
+                     runs        - Runs of the unit
+                     testResults - Results of the unit
+
+    Return Value   : The certificate
+*/
+
+func Build( serial string, runs []results.Run, testResults []results.TestResult ) Certificate {
+  c := Certificate{ Serial: serial, Generated: time.Now().Format( "2006-01-02 15:04 MST" ) }
+
+  byRun := map[string][]results.TestResult{}
+  known := map[string]bool{}
+
+  for _, run := range runs {
+    known[run.RunID] = true
+  }
+
+  for _, r := range testResults {
+    id := r.RunID
+
+    if !known[id] {
+      id = ""
+    }
+
+    byRun[id] = append(byRun[id], r)
+  }
+
+  if len(byRun[""]) > 0 {
+    earlier := results.Run{ Test: "Earlier results", Start: byRun[""][0].Timestamp, Verdict: results.VERDICT_PASS }
+
+    for _, r := range byRun[""] {
+      if !r.Pass {
+        earlier.Verdict = results.VERDICT_FAIL
+      }
+
+      earlier.AssemblyPartNumber = r.AssemblyPartNumber
+      earlier.AssemblySerialNumber = r.AssemblySerialNumber
+    }
+
+    runs = append(runs, earlier)
+  }
+
+  sort.SliceStable( runs, func( a, b int ) bool { return runs[a].Start < runs[b].Start } )
+
+  c.History = runs
+
+  /*
+      The latest finished run of each test makes its section. A run that
+      never finished is only used if there is nothing else.
+  */
+
+  latest := map[string]int{}
+  attempts := map[string]int{}
+  order := []string{}
+
+  for i, run := range runs {
+    attempts[run.Test]++
+
+    j, seen := latest[run.Test]
+
+    if !seen {
+      order = append(order, run.Test)
+    }
+
+    if !seen || run.Verdict != results.VERDICT_RUNNING || runs[j].Verdict == results.VERDICT_RUNNING {
+      latest[run.Test] = i
+    }
+  }
+
+  versions := map[string]int{}
+
+  for _, test := range order {
+    run := runs[latest[test]]
+    section := Section{ Run: run, Attempts: attempts[test] }
+
+    for _, r := range byRun[run.RunID] {
+      l := line( r )
+
+      section.Lines = append(section.Lines, l)
+
+      if r.Kind == results.SOFTWARE_VERSION || r.Kind == results.HARDWARE_VERSION {
+        if i, ok := versions[r.TestName]; ok {
+          c.Versions[i] = l
+        } else {
+          versions[r.TestName] = len(c.Versions)
+          c.Versions = append(c.Versions, l)
+        }
+      }
+    }
+
+    c.Sections = append(c.Sections, section)
+
+    if run.AssemblySerialNumber != "" {
+      c.AssemblyPartNumber = run.AssemblyPartNumber
+      c.AssemblySerialNumber = run.AssemblySerialNumber
+    }
+
+  }
+
+  for _, run := range runs {
+    if len(run.Modules) > 0 {
+      c.Modules = run.Modules
+    }
+  }
+
+  return c
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+        "encoding/json"
+        "flag"
+        "fmt"
+        "io"
+        "os"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/report"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/*
+    Procedure Name : decode
+
+    Description    : Converts a document read from the database into one
+                     of the result structures.
+
+    Arguments      : doc - Document
+                     v   - Pointer to the structure to fill in
+
+    Return Value   : Any error converting the document
+*/
+
+func decode( doc interface{}, v interface{} ) error {
+  data, err := json.Marshal( doc )
+
+  if err == nil {
+    err = json.Unmarshal( data, v )
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : serialResults
+
+    Description    : Reads every result stored for a serial number from
+                     one of the serial number views, oldest first.
+
+    Arguments      : db     - Test database
+                     view   - View name
+                     serial - Serial number
+
+    Return Value   : The results
+                     Any error querying the view
+*/
+
+func serialResults( db *couchdb.Database, view string, serial string ) ([]results.TestResult, error) {
+  options := map[string]interface{}{
+    "startkey": []interface{}{ serial },
+    "endkey": []interface{}{ serial, map[string]interface{}{} },
+    "include_docs": true,
+  }
+
+  v, err := db.View( "results/" + view, nil, options )
+
+  var rows []couchdb.Row
+
+  if err == nil {
+    rows, err = v.Rows()
+  }
+
+  list := []results.TestResult{}
+
+  for i := 0;i < len(rows) && err == nil;i++ {
+    var r results.TestResult
+
+    err = decode( rows[i].Doc, &r )
+
+    list = append(list, r)
+  }
+
+  return list, err
+}
+
+/*
+    Procedure Name : FetchUnit
+
+    Description    : Reads the runs and results of a unit from the test
+                     database. The serial number is looked for as an
+                     assembly serial number first and then as a module
+                     serial number.
+
+    Arguments      : db     - Test database
+                     serial - Serial number
+
+    Return Value   : The runs
+                     The results
+                     Any error reading them
+*/
+
+func FetchUnit( db *couchdb.Database, serial string ) ([]results.Run, []results.TestResult, error) {
+  list, err := serialResults( db, "by_assembly_serial", serial )
+
+  if err == nil && len(list) == 0 {
+    list, err = serialResults( db, "by_module_serial", serial )
+  }
+
+  runs := []results.Run{}
+  seen := map[string]bool{}
+
+  for i := 0;i < len(list) && err == nil;i++ {
+    id := list[i].RunID
+
+    if id == "" || seen[id] {
+      continue
+    }
+
+    seen[id] = true
+
+    var doc map[string]interface{}
+    var run results.Run
+
+    doc, err = db.Get( id, nil )
+
+    if err == nil {
+      err = decode( doc, &run )
+    }
+
+    runs = append(runs, run)
+  }
+
+  return runs, list, err
+}
+
+/*
+    Procedure Name : RunReport
+
+    Description    : The report subcommand. Writes the test certificate of
+                     a unit.
+
+                       iemtestdb report [-format text|html|print] [-o file] serial
+
+    Arguments      : args - Command line after "report"
+
+    Return Value   : Any error making the report
+*/
+
+func RunReport( args []string ) error {
+  flags := flag.NewFlagSet( "report", flag.ContinueOnError )
+
+  configFile := flags.String("config", DEFAULT_CONFIG_FILE, "tester configuration file")
+  format := flags.String("format", "text", "text, html, or print for printable html")
+  output := flags.String("o", "", "file to write, standard output if not given")
+
+  if flags.Parse( args ) != nil {
+    return ExitError{ EXIT_TESTER }
+  }
+
+  if flags.NArg() != 1 {
+    return fmt.Errorf("usage: iemtestdb report [-format text|html|print] [-o file] serial")
+  }
+
+  serial := flags.Arg(0)
+
+  var err error
+
+  Settings, err = LoadConfig( *configFile )
+
+  if err != nil {
+    return err
+  }
+
+  db, err := couchdb.NewDatabase( Settings.Database )
+
+  if err == nil {
+    _, err = results.InstallViews( db )
+  }
+
+  var runs []results.Run
+  var list []results.TestResult
+
+  if err == nil {
+    runs, list, err = FetchUnit( db, serial )
+  }
+
+  if err != nil {
+    return err
+  }
+
+  if len(list) == 0 {
+    return fmt.Errorf("No results stored for serial number %s", serial)
+  }
+
+  certificate := report.Build( serial, runs, list )
+
+  var w io.Writer = os.Stdout
+
+  if *output != "" {
+    f, err := os.Create( *output )
+
+    if err != nil {
+      return err
+    }
+
+    defer f.Close()
+
+    w = f
+  }
+
+  switch *format {
+    case "text" :
+      return certificate.Text( w )
+
+    case "html" :
+      return certificate.HTML( w, false )
+
+    case "print" :
+      return certificate.HTML( w, true )
+  }
+
+  return fmt.Errorf("Unknown report format %q", *format)
+}
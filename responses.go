@@ -0,0 +1,477 @@
+package main
+
+import (
+        "errors"
+        "net"
+)
+
+/*
+   Information Selection Response Structures
+
+   Every response from the IEM is laid out as
+
+     0xf5 selector subselector data... crc-high crc-low 0xf6
+
+   after unstuffing, so the data for every response starts at offset 3.
+   Multi-byte values are sent high byte first.
+*/
+
+const RESPONSE_DATA_OFFSET = 3
+
+// Software Version Response
+
+type SoftwareVersion struct {
+  Version string                     // Software version string
+}
+
+// Hardware Version Response
+
+type HardwareVersion struct {
+  Version int                        // Hardware version number
+}
+
+// Monitored Voltages Response
+
+type MonitoredVoltages struct {
+  Voltages [5]uint16                 // Monitored voltages in millivolts
+}
+
+// Reset Counter Response
+
+type ResetCounter struct {
+  Count uint16                       // Number of resets recorded by the processor
+}
+
+// Status Vector Response
+
+type StatusVector struct {
+  Raw byte                           // Status byte as sent by the IEM
+  FlashTestPassed bool               // Power on flash test passed
+  CPToSPLinkActive bool              // Communications to I/O processor link active (Communications processor only)
+  Detected74V bool                   // 74 volt supply detected (ABCM only)
+  ABCMAMagValveDrive bool            // Processor A mag valve drive on (ABCM only)
+  ABCMBMagValveDrive bool            // Processor B mag valve drive on (ABCM only)
+  AToBCommLinkActive bool            // Processor A to processor B link active (ABCM only)
+}
+
+// Digital Inputs Response
+
+type DigitalInputs struct {
+  Inputs [7]byte                     // Digital input bytes as sent by the IEM
+}
+
+// Analog Inputs Response
+
+type AnalogInputs struct {
+  Channels [8]uint16                 // Analog input counts for channels 1 to 8
+}
+
+// Pressure Inputs Response
+
+type PressureInputs struct {
+  Channels [8]uint16                 // Pressure input counts for channels 1 to 8
+}
+
+// 4-20 mA Current Inputs Response
+
+type CurrentInputs struct {
+  Channels [8]uint16                 // 4-20 mA input counts for channels 1 to 8
+}
+
+// Speed Sensor Inputs Response
+
+type SpeedSensorInputs struct {
+  Count uint16                       // Speed sensor pulse count
+}
+
+// Network Interface Parameters Response
+//
+// The 18 byte response has 12 data bytes: the IP address, network mask and
+// gateway. The IEM does not report its MAC address in this response, so
+// there is no field for it.
+
+type NetworkParams struct {
+  IP net.IP                          // IEM IP address
+  Mask net.IPMask                    // IEM network mask
+  Gateway net.IP                     // IEM default gateway
+}
+
+// Ambient Light Intensity Response
+
+type AmbientLight struct {
+  Intensity uint16                   // ADCM ambient light intensity
+}
+
+/*
+    Procedure Name : responseWord
+
+    Description    : Extracts a high byte first 16 bit value from the data
+                     portion of a response.
+
+    Arguments      : response - Unstuffed response message
+                     index    - Word index within the response data
+
+    Return Value   : The 16 bit value
+*/
+
+func responseWord( response []byte, index int ) uint16 {
+  offset := RESPONSE_DATA_OFFSET + 2*index
+
+  return ((uint16) (response[offset]) << 8) | (uint16) (response[offset + 1])
+}
+
+/*
+    Procedure Name : checkResponse
+
+    Description    : Checks that a response has the length expected for
+                     its information selector.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+                     expected      - Expected response length
+                     name          - Name of the response used in the error
+
+    Return Value   : Any length error
+*/
+
+func checkResponse( response []byte, responseCount int, expected int, name string ) error {
+  if responseCount != expected || len(response) < expected {
+    return errors.New("Bad " + name + " response count.")
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : ParseSoftwareVersion
+
+    Description    : Converts a SW_VERSION response into its structure.
+                     The version string is terminated by the first zero byte.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Software version
+                     Any parsing error
+*/
+
+func ParseSoftwareVersion( response []byte, responseCount int ) (SoftwareVersion, error) {
+  var v SoftwareVersion
+
+  err := checkResponse( response, responseCount, SW_VERSION_RESPONSE_LEN, "Software Version" )
+
+  if err == nil {
+    data := response[RESPONSE_DATA_OFFSET:responseCount - 3]
+
+    for i := 0;i < len(data);i++ {
+      if data[i] == 0 {
+        data = data[:i]
+
+        break
+      }
+    }
+
+    v.Version = string(data)
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseHardwareVersion
+
+    Description    : Converts a HW_VERSION response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Hardware version
+                     Any parsing error
+*/
+
+func ParseHardwareVersion( response []byte, responseCount int ) (HardwareVersion, error) {
+  var v HardwareVersion
+
+  err := checkResponse( response, responseCount, HW_VERSION_RESPONSE_LEN, "Hardware Version" )
+
+  if err == nil {
+    v.Version = (int) (response[RESPONSE_DATA_OFFSET])
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseMonitoredVoltages
+
+    Description    : Converts a MON_VOLTAGES response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Monitored voltages
+                     Any parsing error
+*/
+
+func ParseMonitoredVoltages( response []byte, responseCount int ) (MonitoredVoltages, error) {
+  var v MonitoredVoltages
+
+  err := checkResponse( response, responseCount, MON_VOLTAGES_RESPONSE_LEN, "Monitored Voltages" )
+
+  if err == nil {
+    for i := 0;i < len(v.Voltages);i++ {
+      v.Voltages[i] = responseWord( response, i )
+    }
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseResetCounter
+
+    Description    : Converts a RESET_COUNTER response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Reset counter
+                     Any parsing error
+*/
+
+func ParseResetCounter( response []byte, responseCount int ) (ResetCounter, error) {
+  var v ResetCounter
+
+  err := checkResponse( response, responseCount, RESET_COUNTER_RESPONSE_LEN, "Reset Counter" )
+
+  if err == nil {
+    v.Count = responseWord( response, 0 )
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseStatusVector
+
+    Description    : Converts a STATUS_VECTOR response into its structure.
+                     The meaning of the status bits depends on the
+                     subselector used for the request.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Status vector
+                     Any parsing error
+*/
+
+func ParseStatusVector( response []byte, responseCount int ) (StatusVector, error) {
+  var v StatusVector
+
+  err := checkResponse( response, responseCount, STATUS_VECTOR_RESPONSE_LEN, "Status Vector" )
+
+  if err == nil {
+    v.Raw = response[RESPONSE_DATA_OFFSET]
+
+    switch (int) (response[2]) {
+      case COMM_PROC_AND_IO_PROC_12 :
+        v.FlashTestPassed = (v.Raw & FLASH_TEST_PASSED_01) == FLASH_TEST_PASSED_01
+        v.CPToSPLinkActive = (v.Raw & CP_TO_SP_LINK_ACTIVE_01) == CP_TO_SP_LINK_ACTIVE_01
+
+      case ADCM_12 :
+        v.FlashTestPassed = (v.Raw & FLASH_TEST_PASSED_03) == FLASH_TEST_PASSED_03
+
+      case ABCM_12 :
+        v.FlashTestPassed = (v.Raw & FLASH_TEST_PASSED_04) == FLASH_TEST_PASSED_04
+        v.Detected74V = (v.Raw & DETECTED_74V) == DETECTED_74V
+        v.ABCMAMagValveDrive = (v.Raw & ABCM_A_MAG_VALVE_DRIVE) == ABCM_A_MAG_VALVE_DRIVE
+        v.ABCMBMagValveDrive = (v.Raw & ABCM_B_MAG_VALVE_DRIVE) == ABCM_B_MAG_VALVE_DRIVE
+        v.AToBCommLinkActive = (v.Raw & A_TO_B_COMM_LINK_ACTIVE) == A_TO_B_COMM_LINK_ACTIVE
+
+      default :
+        err = errors.New("Unknown Status Vector Subselector")
+    }
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseDigitalInputs
+
+    Description    : Converts a DIGITAL_INPUTS response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Digital inputs
+                     Any parsing error
+*/
+
+func ParseDigitalInputs( response []byte, responseCount int ) (DigitalInputs, error) {
+  var v DigitalInputs
+
+  err := checkResponse( response, responseCount, DIGITAL_INPUTS_RESPONSE_LEN, "Digital Inputs" )
+
+  if err == nil {
+    copy( v.Inputs[:], response[RESPONSE_DATA_OFFSET:] )
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseAnalogInputs
+
+    Description    : Converts an ANALOG_INPUTS response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Analog inputs
+                     Any parsing error
+*/
+
+func ParseAnalogInputs( response []byte, responseCount int ) (AnalogInputs, error) {
+  var v AnalogInputs
+
+  err := checkResponse( response, responseCount, ANALOG_INPUTS_RESPONSE_LEN, "Analog Inputs" )
+
+  if err == nil {
+    for i := 0;i < len(v.Channels);i++ {
+      v.Channels[i] = responseWord( response, i )
+    }
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParsePressureInputs
+
+    Description    : Converts a PRESSURE_INPUTS response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Pressure inputs
+                     Any parsing error
+*/
+
+func ParsePressureInputs( response []byte, responseCount int ) (PressureInputs, error) {
+  var v PressureInputs
+
+  err := checkResponse( response, responseCount, PRESSURE_INPUTS_RESPONSE_LEN, "Pressure Inputs" )
+
+  if err == nil {
+    for i := 0;i < len(v.Channels);i++ {
+      v.Channels[i] = responseWord( response, i )
+    }
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseCurrentInputs
+
+    Description    : Converts a CUR_4_20MA_INPUTS response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : 4-20 mA inputs
+                     Any parsing error
+*/
+
+func ParseCurrentInputs( response []byte, responseCount int ) (CurrentInputs, error) {
+  var v CurrentInputs
+
+  err := checkResponse( response, responseCount, CUR_4_20MA_INPUTS_RESPONSE_LEN, "4_20 MA Inputs" )
+
+  if err == nil {
+    for i := 0;i < len(v.Channels);i++ {
+      v.Channels[i] = responseWord( response, i )
+    }
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseSpeedSensorInputs
+
+    Description    : Converts a SPEED_SENSOR_INPUTS response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Speed sensor inputs
+                     Any parsing error
+*/
+
+func ParseSpeedSensorInputs( response []byte, responseCount int ) (SpeedSensorInputs, error) {
+  var v SpeedSensorInputs
+
+  err := checkResponse( response, responseCount, SPEED_SENSOR_INPUTS_RESPONSE_LEN, "Speed Sensor Inputs" )
+
+  if err == nil {
+    v.Count = responseWord( response, 0 )
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseNetworkParams
+
+    Description    : Converts a NETWORK_INTERFACE_PARAMS response into its
+                     structure. The response carries the IP address, network
+                     mask and gateway, four bytes each.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Network parameters
+                     Any parsing error
+*/
+
+func ParseNetworkParams( response []byte, responseCount int ) (NetworkParams, error) {
+  var v NetworkParams
+
+  err := checkResponse( response, responseCount, NETWORK_INTERFACE_PARAMS_RESPONSE_LEN, "Network Parameters" )
+
+  if err == nil {
+    data := response[RESPONSE_DATA_OFFSET:]
+
+    v.IP = net.IPv4( data[0], data[1], data[2], data[3] )
+    v.Mask = net.IPv4Mask( data[4], data[5], data[6], data[7] )
+    v.Gateway = net.IPv4( data[8], data[9], data[10], data[11] )
+  }
+
+  return v, err
+}
+
+/*
+    Procedure Name : ParseAmbientLight
+
+    Description    : Converts an AMBIENT_LIGHT_INT response into its structure.
+
+    Arguments      : response      - Unstuffed response message
+                     responseCount - Length of the response message
+
+    Return Value   : Ambient light intensity
+                     Any parsing error
+*/
+
+func ParseAmbientLight( response []byte, responseCount int ) (AmbientLight, error) {
+  var v AmbientLight
+
+  err := checkResponse( response, responseCount, AMBIENT_LIGHT_INT_RESPONSE_LEN, "Ambient Light Intensity" )
+
+  if err == nil {
+    v.Intensity = responseWord( response, 0 )
+  }
+
+  return v, err
+}
@@ -0,0 +1,204 @@
+package results
+
+import (
+        "time"
+)
+
+/*
+    Procedure Name : rename
+
+    Description    : Moves a field of a document to a new name.
+
+    Arguments      : doc  - Document
+                     from - Old field name
+                     to   - New field name
+
+    Return Value   : This routine has no return value.
+*/
+
+func rename( doc map[string]interface{}, from string, to string ) {
+  if v, ok := doc[from]; ok {
+    delete(doc, from)
+
+    doc[to] = v
+  }
+}
+
+/*
+    Procedure Name : Kind
+
+    Description    : Works out the kind of a schema version 1 result from
+                     the fields it has.
+
+    Arguments      : doc - Document as read from the database
+
+    Return Value   : The result kind, "" if the document is not a result
+*/
+
+func Kind( doc map[string]interface{} ) string {
+  has := func( field string ) bool {
+    _, ok := doc[field]
+
+    return ok
+  }
+
+  if !has("name") || !has("pass") {
+    return ""
+  }
+
+  switch {
+    case has("softwareversion") :
+      return SOFTWARE_VERSION
+
+    case has("hardwareversion") :
+      return HARDWARE_VERSION
+
+    case has("received") :
+      return MATCH
+
+    case has("maxvalue") || has("minvalue") :
+      return RANGE
+
+    case has("lowerlimit") :
+      return LOWER_LIMIT
+
+    case has("upperlimit") :
+      return UPPER_LIMIT
+  }
+
+  return PASS_FAIL
+}
+
+/*
+    Procedure Name : Version
+
+    Description    : Returns the schema version of a document.
+
+    Arguments      : doc - Document as read from the database
+
+    Return Value   : The schema version, 1 if the document has none
+*/
+
+func Version( doc map[string]interface{} ) int {
+  switch v := doc["schema_version"].(type) {
+    case float64 :
+      return (int) (v)
+
+    case int :
+      return v
+  }
+
+  return 1
+}
+
+/*
+    Procedure Name : ParseLocalTime
+
+    Description    : Converts the local date and time strings of a result
+                     to a UTC timestamp. A time that falls in the hour
+                     repeated when daylight saving ends is taken as the
+                     first of the two.
+
+    Arguments      : date - Date as MM/DD/YYYY
+                     tod  - Time as HH:MM:SS
+                     loc  - Time zone of the tester that stored the result
+
+    Return Value   : The timestamp
+                     Any error parsing the date and time
+*/
+
+func ParseLocalTime( date string, tod string, loc *time.Location ) (string, error) {
+  t, err := time.ParseInLocation( "01/02/2006 15:04:05", date + " " + tod, loc )
+
+  if err != nil {
+    return "", err
+  }
+
+  return Timestamp( t ), nil
+}
+
+/*
+    Procedure Name : Migrate
+
+    Description    : Rewrites a result or run document in the current
+                     schema. Documents that are already current, or are
+                     not test results at all, are left alone. Results
+                     written before version 3 are given a timestamp from
+                     their local date and time; the time since the
+                     previous result is not known and is left out. The
+                     duration of version 3 results is renamed. Run times
+                     are rewritten with the fixed number of digits.
+
+    Arguments      : doc - Document as read from the database, changed in
+                           place
+                     loc - Time zone of the tester that stored the document
+
+    Return Value   : true if the document was changed
+                     Any error converting the date and time
+*/
+
+func Migrate( doc map[string]interface{}, loc *time.Location ) (bool, error) {
+  version := Version( doc )
+
+  if version >= SCHEMA_VERSION {
+    return false, nil
+  }
+
+  if version < 2 {
+    kind := Kind( doc )
+
+    if kind == "" {
+      return false, nil
+    }
+
+    switch kind {
+      case RANGE :
+        rename( doc, "minvalue", "lowerlimit" )
+        rename( doc, "maxvalue", "upperlimit" )
+
+      case MATCH :
+        rename( doc, "received", "value" )
+
+      case HARDWARE_VERSION :
+        rename( doc, "hardwareversion", "value" )
+
+      case SOFTWARE_VERSION :
+        rename( doc, "softwareversion", "text" )
+    }
+
+    doc["kind"] = kind
+  }
+
+  if version == 3 {
+    rename( doc, "duration_ms", "since_previous_ms" )
+  }
+
+  if doc["kind"] == RUN {
+    for _, field := range []string{ "start", "end" } {
+      if v, ok := doc[field].(string); ok {
+        t, err := time.Parse( time.RFC3339, v )
+
+        if err != nil {
+          return false, err
+        }
+
+        doc[field] = Timestamp( t )
+      }
+    }
+  } else if _, ok := doc["timestamp"]; !ok {
+    date, _ := doc["date"].(string)
+    tod, _ := doc["time"].(string)
+
+    timestamp, err := ParseLocalTime( date, tod, loc )
+
+    if err != nil {
+      return false, err
+    }
+
+    doc["timestamp"] = timestamp
+  }
+
+  doc["schema_version"] = SCHEMA_VERSION
+
+  return true, nil
+}
@@ -0,0 +1,144 @@
+package results
+
+import (
+        "reflect"
+        "testing"
+        "time"
+)
+
+/*
+    Procedure Name : TestMigrate
+
+    Description    : Checks that documents of each older schema are
+                     rewritten in the current one, and that current
+                     documents and documents that are not results are
+                     left alone.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestMigrate( t *testing.T ) {
+  chicago, err := time.LoadLocation( "America/Chicago" )
+
+  if err != nil {
+    t.Skipf("no time zone data: %v", err)
+  }
+
+  tests := []struct {
+    name string
+    doc map[string]interface{}
+    loc *time.Location
+    changed bool
+    want map[string]interface{}
+  }{
+    { "version 1 range", map[string]interface{}{
+        "name": "80 Volt", "pass": true, "value": 80.0, "minvalue": 76.0, "maxvalue": 84.0,
+        "date": "10/05/2026", "time": "10:00:00" }, time.UTC, true,
+      map[string]interface{}{
+        "name": "80 Volt", "pass": true, "value": 80.0, "lowerlimit": 76.0, "upperlimit": 84.0,
+        "date": "10/05/2026", "time": "10:00:00", "kind": RANGE, "schema_version": SCHEMA_VERSION,
+        "timestamp": "2026-10-05T10:00:00.000Z" } },
+
+    { "version 1 match", map[string]interface{}{
+        "name": "Echo", "pass": false, "received": 3.0, "expected": 4.0,
+        "date": "10/05/2026", "time": "10:00:00" }, chicago, true,
+      map[string]interface{}{
+        "name": "Echo", "pass": false, "value": 3.0, "expected": 4.0,
+        "date": "10/05/2026", "time": "10:00:00", "kind": MATCH, "schema_version": SCHEMA_VERSION,
+        "timestamp": "2026-10-05T15:00:00.000Z" } },
+
+    { "version 1 lower limit", map[string]interface{}{
+        "name": "Resets", "pass": true, "value": 4000.0, "lowerlimit": 3492.0,
+        "date": "10/05/2026", "time": "10:00:00" }, time.UTC, true,
+      map[string]interface{}{
+        "name": "Resets", "pass": true, "value": 4000.0, "lowerlimit": 3492.0,
+        "date": "10/05/2026", "time": "10:00:00", "kind": LOWER_LIMIT, "schema_version": SCHEMA_VERSION,
+        "timestamp": "2026-10-05T10:00:00.000Z" } },
+
+    { "version 1 software version", map[string]interface{}{
+        "name": "Software", "pass": true, "softwareversion": "1.2",
+        "date": "10/05/2026", "time": "10:00:00" }, time.UTC, true,
+      map[string]interface{}{
+        "name": "Software", "pass": true, "text": "1.2",
+        "date": "10/05/2026", "time": "10:00:00", "kind": SOFTWARE_VERSION, "schema_version": SCHEMA_VERSION,
+        "timestamp": "2026-10-05T10:00:00.000Z" } },
+
+    { "version 1 hardware version", map[string]interface{}{
+        "name": "Hardware", "pass": true, "hardwareversion": 2.0,
+        "date": "10/05/2026", "time": "10:00:00" }, time.UTC, true,
+      map[string]interface{}{
+        "name": "Hardware", "pass": true, "value": 2.0,
+        "date": "10/05/2026", "time": "10:00:00", "kind": HARDWARE_VERSION, "schema_version": SCHEMA_VERSION,
+        "timestamp": "2026-10-05T10:00:00.000Z" } },
+
+    { "version 1 pass fail, repeated hour", map[string]interface{}{
+        "name": "Relay", "pass": true, "date": "11/01/2026", "time": "01:30:00" }, chicago, true,
+      map[string]interface{}{
+        "name": "Relay", "pass": true, "date": "11/01/2026", "time": "01:30:00",
+        "kind": PASS_FAIL, "schema_version": SCHEMA_VERSION, "timestamp": "2026-11-01T06:30:00.000Z" } },
+
+    { "version 3 duration", map[string]interface{}{
+        "schema_version": 3.0, "kind": RANGE, "name": "80 Volt", "pass": true,
+        "timestamp": "2026-10-05T10:00:00.000Z", "duration_ms": 250.0 }, time.UTC, true,
+      map[string]interface{}{
+        "schema_version": SCHEMA_VERSION, "kind": RANGE, "name": "80 Volt", "pass": true,
+        "timestamp": "2026-10-05T10:00:00.000Z", "since_previous_ms": 250.0 } },
+
+    { "version 3 run", map[string]interface{}{
+        "schema_version": 3.0, "kind": RUN, "start": "2026-10-05T05:00:00-05:00", "end": "2026-10-05T10:01:00Z" },
+      time.UTC, true,
+      map[string]interface{}{
+        "schema_version": SCHEMA_VERSION, "kind": RUN, "start": "2026-10-05T10:00:00.000Z",
+        "end": "2026-10-05T10:01:00.000Z" } },
+
+    { "current", map[string]interface{}{
+        "schema_version": (float64) (SCHEMA_VERSION), "kind": RANGE, "name": "80 Volt", "pass": true },
+      time.UTC, false,
+      map[string]interface{}{
+        "schema_version": (float64) (SCHEMA_VERSION), "kind": RANGE, "name": "80 Volt", "pass": true } },
+
+    { "not a result", map[string]interface{}{ "language": "javascript", "views": "x" }, time.UTC, false,
+      map[string]interface{}{ "language": "javascript", "views": "x" } },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      changed, err := Migrate( tt.doc, tt.loc )
+
+      if err != nil {
+        t.Fatalf("Migrate: %v", err)
+      }
+
+      if changed != tt.changed {
+        t.Errorf("changed %v, want %v", changed, tt.changed)
+      }
+
+      if !reflect.DeepEqual( tt.doc, tt.want ) {
+        t.Errorf("document\n%v\nwant\n%v", tt.doc, tt.want)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestMigrateBadDate
+
+    Description    : Checks that a result whose local date cannot be read
+                     is reported and not marked as changed.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestMigrateBadDate( t *testing.T ) {
+  doc := map[string]interface{}{ "name": "Relay", "pass": true, "date": "2026-10-05", "time": "10:00:00" }
+
+  changed, err := Migrate( doc, time.UTC )
+
+  if err == nil || changed {
+    t.Errorf("changed %v error %v, want an error", changed, err)
+  }
+}
@@ -0,0 +1,132 @@
+/*
+   Package results holds the test result document stored in the test
+   database for every check the tester makes, and the conversion of
+   documents written by earlier versions of the tester.
+*/
+
+package results
+
+import (
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+)
+
+/*
+   Schema version 1 was seven separate document layouts, one for each
+   kind of result, none of which carried a version. Version 2 is the
+   single TestResult document below. Version 3 adds the UTC timestamp
+   and the duration. Version 4 renames the duration since_previous_ms,
+   as it is the time since the previous result and not the time the
+   check took.
+*/
+
+const SCHEMA_VERSION = 4
+
+/*
+   Timestamps are RFC 3339 in UTC with a fixed number of digits, so that
+   they sort as strings.
+*/
+
+const TIMESTAMP_FORMAT = "2006-01-02T15:04:05.000Z"
+
+/* Result Kinds */
+
+const (
+  RANGE = "range"                              // Value between a lower and an upper limit
+  LOWER_LIMIT = "lowerlimit"                   // Value above a lower limit
+  UPPER_LIMIT = "upperlimit"                   // Value below an upper limit
+  MATCH = "match"                              // Value equal to an expected value
+  PASS_FAIL = "passfail"                       // Pass or fail only
+  HARDWARE_VERSION = "hardwareversion"         // Hardware version number in Value
+  SOFTWARE_VERSION = "softwareversion"         // Software version string in Text
+)
+
+/* Test Result */
+
+type TestResult struct {
+  SchemaVersion int `json:"schema_version"`                 // Version of this document layout
+  Kind string `json:"kind"`                                 // One of the result kinds above
+  RunID string `json:"run_id,omitempty"`                     // Run the result was taken in
+  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
+  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
+  PartNumber string `json:"partnumber"`                     // Module Part Number
+  SerialNumber string `json:"serialnumber"`                 // Module Serial Number
+  TestName string `json:"name"`                             // Name of the test
+  Label string `json:"label,omitempty"`                     // Check within the test, such as one channel
+  Time string `json:"time"`                                 // Local time the test was run
+  Date string `json:"date"`                                 // Local date the test was run
+  Timestamp string `json:"timestamp,omitempty"`             // UTC time the test was run
+  SincePrevious *int64 `json:"since_previous_ms,omitempty"` // Milliseconds since the previous result of the run
+  Elapsed *int64 `json:"elapsed_ms,omitempty"`             // Milliseconds from the stimulus of the step to the check
+  Value *int `json:"value,omitempty"`                       // Measured or received value, less Zero if set
+  Zero *int `json:"zero,omitempty"`                         // Reading before the stimulus, taken off the value
+  Text string `json:"text,omitempty"`                       // Measured text, such as a software version
+  Expected *int `json:"expected,omitempty"`                 // Value expected by a match test
+  LowerLimit *int `json:"lowerlimit,omitempty"`             // Lowest passing value
+  UpperLimit *int `json:"upperlimit,omitempty"`             // Highest passing value
+  Inclusive *bool `json:"inclusive,omitempty"`              // true if a value equal to a limit passes, set for limited kinds
+  Units string `json:"units,omitempty"`                     // Units of the value and limits
+  LimitsVersion string `json:"limitsversion,omitempty"`     // Version of the limits file used
+  Pass bool `json:"pass"`                                   // Pass/Fail flag
+  couchdb.Document                                          // Associated Document Information
+}
+
+/*
+    Procedure Name : New
+
+    Description    : Starts a test result of the given kind in the
+                     current schema.
+
+    Arguments      : kind - Result kind
+
+    Return Value   : The result
+*/
+
+func New( kind string ) TestResult {
+  return TestResult{ SchemaVersion: SCHEMA_VERSION, Kind: kind }
+}
+
+/*
+    Procedure Name : Int
+
+    Description    : Returns a pointer to a copy of a value, for filling in
+                     the optional fields of a result.
+
+    Arguments      : v - Value
+
+    Return Value   : Pointer to the copy
+*/
+
+func Int( v int ) *int {
+  return &v
+}
+
+/*
+    Procedure Name : Bool
+
+    Description    : Returns a pointer to a copy of a flag, for filling in
+                     the optional fields of a result.
+
+    Arguments      : v - Flag
+
+    Return Value   : Pointer to the copy
+*/
+
+func Bool( v bool ) *bool {
+  return &v
+}
+
+/*
+    Procedure Name : Timestamp
+
+    Description    : Formats a time the way it is stored in the results.
+
+    Arguments      : t - Time
+
+    Return Value   : The timestamp
+*/
+
+func Timestamp( t time.Time ) string {
+  return t.UTC().Format( TIMESTAMP_FORMAT )
+}
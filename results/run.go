@@ -0,0 +1,101 @@
+package results
+
+import (
+        "github.com/leesper/couchdb-golang"
+)
+
+const RUN = "run"                              // Kind of a run document
+
+/* Run Verdicts */
+
+const (
+  VERDICT_RUNNING = "running"                  // The run has not finished
+  VERDICT_PASS = "pass"                        // Every test passed
+  VERDICT_FAIL = "fail"                        // At least one test failed
+  VERDICT_INCOMPLETE = "incomplete"            // The run stopped on an error
+)
+
+/* Module Part and Serial Number */
+
+type Module struct {
+  Name string `json:"name"`                               // Module name, such as "main" or "abcm"
+  PartNumber string `json:"partnumber"`                   // Module Part Number
+  SerialNumber string `json:"serialnumber"`               // Module Serial Number
+}
+
+/*
+   File attached to a document. CouchDB takes the data inline, base64
+   encoded, when the document is saved, and returns only a stub with the
+   length when it is read.
+*/
+
+type Attachment struct {
+  ContentType string `json:"content_type"`                   // MIME type of the file
+  Data []byte `json:"data,omitempty"`                         // Contents, when the document is saved
+  Length int `json:"length,omitempty"`                        // Size in bytes, when the document is read
+  Stub bool `json:"stub,omitempty"`                           // Set when Data was left out
+}
+
+/*
+   Counts of the IEM link over a run: requests made and the faults they
+   met, as counted by the IEM client.
+*/
+
+type LinkStats struct {
+  Requests int `json:"requests"`                           // Requests sent, not counting retries
+  Retries int `json:"retries"`                             // Requests sent again
+  NoResponse int `json:"noresponse"`                       // Responses that never came
+  ShortFrames int `json:"shortframes"`                     // Responses cut short
+  CRCMismatches int `json:"crcmismatches"`                 // Responses that failed the CRC check
+  UnexpectedEchoes int `json:"unexpectedechoes"`           // Responses to some other request
+  Unsolicited int `json:"unsolicited"`                     // Frames that answered nothing asked for
+  NoiseBytes int `json:"noisebytes"`                       // Bytes thrown away outside of frames
+}
+
+/*
+   Test Run
+
+   One run is one test chosen from the tester menu on one unit. Every
+   result stored during the run carries its RunID, which is also the
+   document ID of the run.
+*/
+
+type Run struct {
+  SchemaVersion int `json:"schema_version"`                 // Version of this document layout
+  Kind string `json:"kind"`                                 // Always RUN
+  RunID string `json:"run_id"`                             // Identifier shared with the results
+  Test string `json:"test"`                                 // Test that was run
+  AssemblyPartNumber string `json:"assemblypartnumber"`     // Assembly Part Number
+  AssemblySerialNumber string `json:"assemblyserialnumber"` // Assembly Serial Number
+  Modules []Module `json:"modules"`                         // Modules entered for the assembly
+  Operator string `json:"operator"`                         // Who ran the test
+  Station string `json:"station"`                           // Which tester it was run on
+  SoftwareVersion string `json:"softwareversion"`           // Version of the tester software
+  LimitsVersion string `json:"limitsversion"`               // Version of the limits file used
+  Start string `json:"start"`                               // UTC timestamp of the start of the run
+  End string `json:"end,omitempty"`                         // UTC timestamp of the end of the run
+  Verdict string `json:"verdict"`                           // One of the run verdicts above
+  UnsavedResults int `json:"unsavedresults,omitempty"`     // Results of the run that could not be saved
+  Link *LinkStats `json:"link,omitempty"`                   // IEM link counts over the run, if connected
+  Attachments map[string]Attachment `json:"_attachments,omitempty"` // Files kept with the run, such as the protocol trace
+  couchdb.Document                                          // Associated Document Information
+}
+
+/*
+    Procedure Name : NewRun
+
+    Description    : Starts a run document in the current schema.
+
+    Arguments      : id   - Run ID
+                     test - Name of the test being run
+
+    Return Value   : The run
+*/
+
+func NewRun( id string, test string ) Run {
+  run := Run{ SchemaVersion: SCHEMA_VERSION, Kind: RUN, RunID: id, Test: test, Verdict: VERDICT_RUNNING }
+
+  run.SetID( id )
+
+  return run
+}
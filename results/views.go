@@ -0,0 +1,240 @@
+package results
+
+import (
+        "net/url"
+)
+
+const DESIGN_DOC = "_design/results"
+
+/*
+   Version of the views below. Bump it whenever a view changes; the
+   tester replaces an older design document when it connects. The design
+   document is also replaced when SCHEMA_VERSION changes.
+*/
+
+const DESIGN_VERSION = 2
+
+/* The test database, as far as the views need it */
+
+type Database interface {
+  Get( docid string, options url.Values ) (map[string]interface{}, error)
+  Set( docid string, doc map[string]interface{} ) error
+}
+
+/*
+   Reduce of first_pass to the number of units and the number that
+   passed their first run, at any group level. CouchDB reduces ranges of
+   keys, so a unit's runs can be split over several reductions, but only
+   at the ends of a range: the lowest and highest unit of every range are
+   kept with their earliest run until the ranges on either side are
+   merged in, and every other unit is complete. The ends are found by
+   comparing keys, with localeCompare standing in for CouchDB's
+   collation, as the order the reductions arrive in is not fixed.
+*/
+
+const firstPassReduce = `function (keys, values, rereduce) {
+  var compare = function (a, b) {
+    for (var i = 0; i < a.length && i < b.length; i++) {
+      var c = String(a[i]).localeCompare(String(b[i]));
+
+      if (c !== 0) { return c; }
+    }
+
+    return a.length - b.length;
+  };
+
+  var earliest = function (a, b) {
+    return (a === undefined || b.start < a.start) ? b : a;
+  };
+
+  var units = 0, firstPass = 0, ends = {}, first, last, i, id;
+
+  var add = function (key, value) {
+    id = JSON.stringify(key);
+    ends[id] = earliest(ends[id], { key: key, start: value.start, pass: value.pass });
+
+    if (first === undefined || compare(key, first) < 0) { first = key; }
+    if (last === undefined || compare(key, last) > 0) { last = key; }
+  };
+
+  for (i = 0; i < values.length; i++) {
+    if (!rereduce) {
+      add(keys[i][0], values[i]);
+
+      continue;
+    }
+
+    units += values[i].units;
+    firstPass += values[i].first_pass;
+
+    for (id in values[i].ends) {
+      var end = values[i].ends[id];
+
+      units--;
+      if (end.pass) { firstPass--; }
+
+      add(end.key, end);
+    }
+  }
+
+  var kept = {};
+
+  for (id in ends) {
+    if (compare(ends[id].key, first) === 0 || compare(ends[id].key, last) === 0) {
+      kept[id] = ends[id];
+    }
+
+    units++;
+    if (ends[id].pass) { firstPass++; }
+  }
+
+  return { units: units, first_pass: firstPass, ends: kept };
+}`
+
+/*
+   Map functions. Results are keyed with their timestamp last so that a
+   key range gives them in time order; the value is enough to list them
+   without include_docs.
+*/
+
+const resultValue = `{ kind: doc.kind, name: doc.name, value: doc.value, text: doc.text, pass: doc.pass, run_id: doc.run_id }`
+
+var views = map[string]map[string]string{
+  "by_assembly_serial": {
+    "map": `function (doc) {
+  if (doc.kind && doc.kind !== "run") {
+    emit([doc.assemblyserialnumber, doc.timestamp], ` + resultValue + `);
+  }
+}`,
+  },
+  "by_module_serial": {
+    "map": `function (doc) {
+  if (doc.kind && doc.kind !== "run") {
+    emit([doc.serialnumber, doc.timestamp], ` + resultValue + `);
+  }
+}`,
+  },
+  "by_test_name": {
+    "map": `function (doc) {
+  if (doc.kind && doc.kind !== "run") {
+    emit([doc.name, doc.timestamp], ` + resultValue + `);
+  }
+}`,
+  },
+  "by_pass": {
+    "map": `function (doc) {
+  if (doc.kind && doc.kind !== "run") {
+    emit([doc.pass, doc.timestamp], ` + resultValue + `);
+  }
+}`,
+    "reduce": "_count",
+  },
+  "by_date": {
+    "map": `function (doc) {
+  if (doc.kind && doc.kind !== "run") {
+    emit(doc.timestamp, ` + resultValue + `);
+  }
+}`,
+  },
+  "runs_by_serial": {
+    "map": `function (doc) {
+  if (doc.kind === "run") {
+    emit([doc.assemblyserialnumber, doc.start], { test: doc.test, verdict: doc.verdict, end: doc.end });
+  }
+}`,
+  },
+  "first_pass": {
+    "map": `function (doc) {
+  if (doc.kind === "run" && doc.verdict !== "running") {
+    emit([doc.assemblypartnumber, doc.test, doc.assemblyserialnumber],
+         { start: doc.start, pass: doc.verdict === "pass" });
+  }
+}`,
+    "reduce": firstPassReduce,
+  },
+}
+
+/*
+    Procedure Name : DesignDocument
+
+    Description    : Builds the design document holding the views.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The design document
+*/
+
+func DesignDocument() map[string]interface{} {
+  v := map[string]interface{}{}
+
+  for name, functions := range views {
+    view := map[string]interface{}{}
+
+    for kind, source := range functions {
+      view[kind] = source
+    }
+
+    v[name] = view
+  }
+
+  return map[string]interface{}{
+    "language": "javascript",
+    "version": DESIGN_VERSION,
+    "schema_version": SCHEMA_VERSION,
+    "views": v,
+  }
+}
+
+/*
+    Procedure Name : InstallViews
+
+    Description    : Installs the design document, or replaces one left by
+                     an older tester. A design document from a newer
+                     tester is left alone.
+
+    Arguments      : db - Test database
+
+    Return Value   : true if the design document was written
+                     Any error reading or writing it
+*/
+
+func InstallViews( db Database ) (bool, error) {
+  doc := DesignDocument()
+
+  current, err := db.Get( DESIGN_DOC, nil )
+
+  if err == nil {
+    if Version( current ) > SCHEMA_VERSION ||
+       (Version( current ) == SCHEMA_VERSION && designVersion( current ) >= DESIGN_VERSION) {
+      return false, nil
+    }
+
+    doc["_rev"] = current["_rev"]
+  }
+
+  err = db.Set( DESIGN_DOC, doc )
+
+  return err == nil, err
+}
+
+/*
+    Procedure Name : designVersion
+
+    Description    : Returns the view version of a design document.
+
+    Arguments      : doc - Design document as read from the database
+
+    Return Value   : The version, 0 if it has none
+*/
+
+func designVersion( doc map[string]interface{} ) int {
+  switch v := doc["version"].(type) {
+    case float64 :
+      return (int) (v)
+
+    case int :
+      return v
+  }
+
+  return 0
+}
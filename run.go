@@ -0,0 +1,283 @@
+package main
+
+import (
+        "context"
+        "fmt"
+        "log"
+        "os"
+        "os/signal"
+        "sort"
+        "strings"
+        "syscall"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/iemclient"
+        "github.com/questrail/IEMTestDB/iemtrace"
+        "github.com/questrail/IEMTestDB/replay"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/*
+   Version of the tester software, stored with every run. Release builds
+   set it with
+
+     go build -ldflags "-X main.TesterVersion=1.2.0"
+*/
+
+var TesterVersion = "development"
+
+const TRACE_ATTACHMENT = "protocol.trace"  // Name of the protocol trace attached to a run
+
+var Operator string                        // Operator running the tests
+var CurrentRun results.Run                 // Run in progress, or the last one run
+var lastResult time.Time                   // When the previous result of the run was taken
+var startStats iemclient.Stats             // IEM link counts when the run started
+var runContext context.Context             // Cancelled when the run in progress is interrupted, nil between runs
+var stopRun context.CancelFunc             // Releases runContext
+var Interrupted bool                       // Set when a run was stopped by a signal; no more should be started
+
+/*
+    Procedure Name : RunContext
+
+    Description    : Returns the context of IEM requests. During a run it
+                     is cancelled when the tester is interrupted, so a
+                     request in progress gives up at once instead of
+                     waiting out its timeout and retries.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The context
+*/
+
+func RunContext() context.Context {
+  if runContext == nil {
+    return context.Background()
+  }
+
+  return runContext
+}
+
+/*
+    Procedure Name : LinkStats
+
+    Description    : Converts the IEM link counts for storing with a run
+                     or a batch summary.
+
+    Arguments      : s - Counts now
+                     base - Counts to take away, such as those at the
+                            start of the run
+
+    Return Value   : The counts from base to s
+*/
+
+func LinkStats( s iemclient.Stats, base iemclient.Stats ) *results.LinkStats {
+  return &results.LinkStats{
+    Requests: s.Requests - base.Requests,
+    Retries: s.Retries - base.Retries,
+    NoResponse: s.NoResponse - base.NoResponse,
+    ShortFrames: s.ShortFrames - base.ShortFrames,
+    CRCMismatches: s.CRCMismatches - base.CRCMismatches,
+    UnexpectedEchoes: s.UnexpectedEchoes - base.UnexpectedEchoes,
+    Unsolicited: s.Unsolicited - base.Unsolicited,
+    NoiseBytes: s.NoiseBytes - base.NoiseBytes,
+  }
+}
+
+/*
+    Procedure Name : StartRun
+
+    Description    : Starts a new test run for the assembly that has been
+                     entered and stores it with the verdict "running", so
+                     a run that never finishes can still be found. When
+                     the link is traced, the run gets a trace of its own,
+                     and when the session is recorded the start of the
+                     run is marked for playback.
+
+    Arguments      : test - Name of the test being run
+
+    Return Value   : This routine has no return value.
+*/
+
+func StartRun( test string ) {
+  UnsavedResults = 0
+
+  /*
+      An interrupt during a run stops its IEM requests so the run can
+      finish as incomplete and the unit be powered down. Between runs an
+      interrupt stops the tester as it always has.
+  */
+
+  runContext, stopRun = signal.NotifyContext( context.Background(), os.Interrupt, syscall.SIGTERM )
+
+  CurrentRun = results.NewRun( couchdb.GenerateUUID(), test )
+
+  CurrentRun.AssemblyPartNumber = AssemblyPartNumber
+  CurrentRun.AssemblySerialNumber = AssemblySerialNumber
+  CurrentRun.Operator = Operator
+  CurrentRun.Station = Settings.Station
+  CurrentRun.SoftwareVersion = TesterVersion
+  CurrentRun.LimitsVersion = Limits.Version
+  lastResult = time.Now()
+
+  CurrentRun.Start = results.Timestamp( lastResult )
+
+  if IEM != nil {
+    startStats = IEM.Stats()
+  }
+
+  names := make([]string, 0, len(modules))
+
+  for name := range modules {
+    names = append(names, name)
+  }
+
+  sort.Strings( names )
+
+  for _, name := range names {
+    partNumber, serialNumber := modules[name]()
+
+    if name != "assembly" && partNumber != "" {
+      CurrentRun.Modules = append(CurrentRun.Modules, results.Module{ Name: name, PartNumber: partNumber, SerialNumber: serialNumber })
+    }
+  }
+
+  if IEMRecorder != nil {
+    IEMRecorder.Begin( replay.Run{ Test: test, AssemblyPartNumber: AssemblyPartNumber, AssemblySerialNumber: AssemblySerialNumber, Modules: CurrentRun.Modules } )
+  }
+
+  if IEMTrace != nil {
+    err := IEMTrace.Begin( CurrentRun.RunID )
+
+    if err != nil {
+      log.Printf("error starting the protocol trace of run %s: %s", CurrentRun.RunID, err)
+    }
+  }
+
+  SaveRun( &CurrentRun )
+}
+
+/*
+    Procedure Name : FinishRun
+
+    Description    : Records the end time and verdict of the current run.
+                     The verdict is that of the unit; results that could
+                     not be saved are recorded beside it, and a run that
+                     was interrupted is incomplete. When the link
+                     is traced, the trace of the run is attached.
+
+    Arguments      : passed - 1 if every test passed, 0 if any failed, as
+                              returned by the test routines
+                     err    - Error that stopped the test, if any
+
+    Return Value   : This routine has no return value.
+*/
+
+func FinishRun( passed int, err error ) {
+  CurrentRun.End = results.Timestamp( time.Now() )
+
+  if runContext != nil {
+    Interrupted = runContext.Err() != nil
+
+    stopRun()
+
+    runContext = nil
+  }
+
+  switch {
+    case err != nil || Interrupted :
+      CurrentRun.Verdict = results.VERDICT_INCOMPLETE
+
+    case passed == 0 :
+      CurrentRun.Verdict = results.VERDICT_FAIL
+
+    default :
+      CurrentRun.Verdict = results.VERDICT_PASS
+  }
+
+  CurrentRun.UnsavedResults = UnsavedResults
+
+  if IEM != nil {
+    CurrentRun.Link = LinkStats( IEM.Stats(), startStats )
+  }
+
+  if IEMRecorder != nil {
+    IEMRecorder.End( CurrentRun.Verdict )
+  }
+
+  if IEMTrace != nil {
+    trace, err := IEMTrace.End()
+
+    if err != nil {
+      log.Printf("error finishing the protocol trace of run %s: %s", CurrentRun.RunID, err)
+    }
+
+    if trace != nil {
+      CurrentRun.Attachments = map[string]results.Attachment{
+        TRACE_ATTACHMENT: { ContentType: iemtrace.CONTENT_TYPE, Data: trace },
+      }
+    }
+  }
+
+  SaveRun( &CurrentRun )
+}
+
+/*
+    Procedure Name : NewResult
+
+    Description    : Starts a test result of the given kind that belongs to
+                     the current run, stamped with the time and the time
+                     since the previous result of the run, measured on the
+                     monotonic clock, so that those times add up to the
+                     cycle time of the run.
+
+    Arguments      : kind - Result kind
+
+    Return Value   : The result
+*/
+
+func NewResult( kind string ) results.TestResult {
+  Test := results.New( kind )
+
+  now := time.Now()
+
+  Test.RunID = CurrentRun.RunID
+  Test.Timestamp = results.Timestamp( now )
+
+  if !lastResult.IsZero() {
+    since := now.Sub( lastResult ).Milliseconds()
+
+    Test.SincePrevious = &since
+  }
+
+  lastResult = now
+
+  return Test
+}
+
+/*
+    Procedure Name : GetOperator
+
+    Description    : Asks for the name of the operator, unless it was
+                     given on the command line.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error reading the console
+*/
+
+func GetOperator() error {
+  var err error
+
+  for Operator == "" && err == nil {
+    var name string
+
+    fmt.Printf("\r\nEnter Operator Name : ")
+
+    name, err = ConsoleInput.ReadString('\n')
+
+    Operator = strings.TrimSpace( name )
+  }
+
+  return err
+}
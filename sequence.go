@@ -0,0 +1,888 @@
+package main
+
+import (
+        "encoding/json"
+        "fmt"
+        "log"
+        "os"
+        "os/exec"
+        "path/filepath"
+        "reflect"
+        "strings"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/fixture"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+const DEFAULT_SEQUENCE_DIR = "sequences"
+
+const CPU_MAIN_SEQUENCE = "cpu_main.json"  // Sequence file for the Main CPU test
+
+/*
+   Test Sequences
+
+   A sequence file describes a test as a list of steps. Each step may
+   switch fixture signals, send a command, wait for the IEM to settle, ask
+   the IEM for one information selector and then check any number of
+   fields of the response. Selectors, subselectors and command arguments
+   are written with the names of the protocol constants, fixture signals
+   with their names in the test description spreadsheet and limits are
+   looked up in the limits file by test name.
+*/
+
+/* Selector or Constant Code */
+
+type Code int
+
+// Protocol constants that may be named in a sequence file
+
+var codeNames = map[string]int{
+  "SW_VERSION": SW_VERSION,
+  "COMM_PROC_01": COMM_PROC_01,
+  "IO_PROCESSOR_01": IO_PROCESSOR_01,
+  "ADCM_01": ADCM_01,
+  "ABCM_PROC_A_01": ABCM_PROC_A_01,
+  "ABCM_PROC_B_01": ABCM_PROC_B_01,
+  "HW_VERSION": HW_VERSION,
+  "COMM_PROC_02": COMM_PROC_02,
+  "IO_PROCESSOR_02": IO_PROCESSOR_02,
+  "ADCM_02": ADCM_02,
+  "ABCM_02": ABCM_02,
+  "MON_VOLTAGES": MON_VOLTAGES,
+  "IEM_CPU_BOARD_03": IEM_CPU_BOARD_03,
+  "ADCM_03": ADCM_03,
+  "ABCM_03": ABCM_03,
+  "RESET_COUNTER": RESET_COUNTER,
+  "COMM_PROC_10": COMM_PROC_10,
+  "ADCM_10": ADCM_10,
+  "ABCM_10": ABCM_10,
+  "STATUS_VECTOR": STATUS_VECTOR,
+  "COMM_PROC_AND_IO_PROC_12": COMM_PROC_AND_IO_PROC_12,
+  "ADCM_12": ADCM_12,
+  "ABCM_12": ABCM_12,
+  "DIGITAL_INPUTS": DIGITAL_INPUTS,
+  "ANALOG_INPUTS": ANALOG_INPUTS,
+  "ANALOG_16V_INPUTS_32": ANALOG_16V_INPUTS_32,
+  "ANALOG_10V_INPUTS_32": ANALOG_10V_INPUTS_32,
+  "ANALOG_80V_INPUTS_32": ANALOG_80V_INPUTS_32,
+  "PRESSURE_INPUTS": PRESSURE_INPUTS,
+  "CUR_4_20MA_INPUTS": CUR_4_20MA_INPUTS,
+  "SPEED_SENSOR_INPUTS": SPEED_SENSOR_INPUTS,
+  "NETWORK_INTERFACE_PARAMS": NETWORK_INTERFACE_PARAMS,
+  "AMBIENT_LIGHT_INT": AMBIENT_LIGHT_INT,
+  "REST_CMD": REST_CMD,
+  "COMM_PORC_11": COMM_PORC_11,
+  "ADCM_11": ADCM_11,
+  "ABCM_11": ABCM_11,
+  "SET_SPEED_SENSOR_REF_CMD": SET_SPEED_SENSOR_REF_CMD,
+  "ZERO_CROSSING_2_5V": ZERO_CROSSING_2_5V,
+  "ZERO_CROSSING_0V": ZERO_CROSSING_0V,
+  "SET_ADCM_LED_STATE_CMD": SET_ADCM_LED_STATE_CMD,
+  "SET_ADCM_SONALERT_STATE_CMD": SET_ADCM_SONALERT_STATE_CMD,
+  "SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD": SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD,
+  "ABCM_PROC_A_92": ABCM_PROC_A_92,
+  "ABCM_PROC_B_92": ABCM_PROC_B_92,
+}
+
+/*
+    Procedure Name : UnmarshalJSON
+
+    Description    : Accepts a code as either a number or the name of a
+                     protocol constant.
+
+    Arguments      : data - JSON value
+
+    Return Value   : Any error decoding the value
+*/
+
+func (c *Code) UnmarshalJSON( data []byte ) error {
+  var name string
+
+  if err := json.Unmarshal( data, &name ); err != nil {
+    var n int
+
+    if err = json.Unmarshal( data, &n ); err != nil {
+      return fmt.Errorf("Code %s is not a name or a number", data)
+    }
+
+    *c = (Code) (n)
+
+    return nil
+  }
+
+  n, ok := codeNames[name]
+
+  if !ok {
+    return fmt.Errorf("Unknown protocol constant %q", name)
+  }
+
+  *c = (Code) (n)
+
+  return nil
+}
+
+/* Information Selector Response Parser */
+
+type responseParser struct {
+  Zero interface{}                               // Empty response structure, used to check field names
+  Parse func( []byte, int ) (interface{}, error) // Converts a response into its structure
+}
+
+var responseParsers = map[int]responseParser{
+  SW_VERSION: { SoftwareVersion{}, func( r []byte, n int ) (interface{}, error) { return ParseSoftwareVersion( r, n ) } },
+  HW_VERSION: { HardwareVersion{}, func( r []byte, n int ) (interface{}, error) { return ParseHardwareVersion( r, n ) } },
+  MON_VOLTAGES: { MonitoredVoltages{}, func( r []byte, n int ) (interface{}, error) { return ParseMonitoredVoltages( r, n ) } },
+  RESET_COUNTER: { ResetCounter{}, func( r []byte, n int ) (interface{}, error) { return ParseResetCounter( r, n ) } },
+  STATUS_VECTOR: { StatusVector{}, func( r []byte, n int ) (interface{}, error) { return ParseStatusVector( r, n ) } },
+  DIGITAL_INPUTS: { DigitalInputs{}, func( r []byte, n int ) (interface{}, error) { return ParseDigitalInputs( r, n ) } },
+  ANALOG_INPUTS: { AnalogInputs{}, func( r []byte, n int ) (interface{}, error) { return ParseAnalogInputs( r, n ) } },
+  PRESSURE_INPUTS: { PressureInputs{}, func( r []byte, n int ) (interface{}, error) { return ParsePressureInputs( r, n ) } },
+  CUR_4_20MA_INPUTS: { CurrentInputs{}, func( r []byte, n int ) (interface{}, error) { return ParseCurrentInputs( r, n ) } },
+  SPEED_SENSOR_INPUTS: { SpeedSensorInputs{}, func( r []byte, n int ) (interface{}, error) { return ParseSpeedSensorInputs( r, n ) } },
+  NETWORK_INTERFACE_PARAMS: { NetworkParams{}, func( r []byte, n int ) (interface{}, error) { return ParseNetworkParams( r, n ) } },
+  AMBIENT_LIGHT_INT: { AmbientLight{}, func( r []byte, n int ) (interface{}, error) { return ParseAmbientLight( r, n ) } },
+}
+
+/* Module Part and Serial Numbers */
+
+var modules = map[string]func() (string, string){
+  "assembly": func() (string, string) { return AssemblyPartNumber, AssemblySerialNumber },
+  "main": func() (string, string) { return MainCircuitPartNumber, MainCircuitSerialNumber },
+  "power": func() (string, string) { return DualPowerSupplyPartNumber, DualPowerSupplySerialNumber },
+  "cab": func() (string, string) { return CabConnectorCardPartNumber, CabConnectorSerialNumber },
+  "io": func() (string, string) { return IOConnectorPartNumber, IOConnectorSerialNumber },
+  "4-20": func() (string, string) { return IEM4_20mAPartNumber, IEM4_20mASerialNumber },
+  "abcm": func() (string, string) { return ABCMPartNumber, ABCMSerialNumber },
+  "adcm": func() (string, string) { return ADCMPartNumber, ADCMSerialNumber },
+}
+
+/* Check Types */
+
+const (
+  CHECK_RANGE = "range"                          // Value between the limits
+  CHECK_LOWER = "lower"                          // Value above the lower limit
+  CHECK_UPPER = "upper"                          // Value below the upper limit
+  CHECK_MATCH = "match"                          // Masked value equal to Expected
+  CHECK_FLAG = "flag"                            // Status flag set
+  CHECK_HARDWARE = "hardware"                    // Hardware version recorded
+  CHECK_SOFTWARE = "software"                    // Software version recorded
+  CHECK_PING = "ping"                            // Address answers a ping
+)
+
+// Kind of result stored for each check type
+
+var resultKinds = map[string]string{
+  CHECK_RANGE: results.RANGE,
+  CHECK_LOWER: results.LOWER_LIMIT,
+  CHECK_UPPER: results.UPPER_LIMIT,
+  CHECK_MATCH: results.MATCH,
+  CHECK_FLAG: results.PASS_FAIL,
+  CHECK_HARDWARE: results.HARDWARE_VERSION,
+  CHECK_SOFTWARE: results.SOFTWARE_VERSION,
+  CHECK_PING: results.PASS_FAIL,
+}
+
+/* Sequence Check */
+
+type Check struct {
+  Name string `json:"name"`                     // Test name stored with the result and used to find the limit
+  Label string `json:"label"`                   // Name of the check in console messages
+  Type string `json:"type"`                     // One of the check types above
+  Field string `json:"field"`                   // Field of the response structure to check
+  Index int `json:"index,omitempty"`            // Element of an array field
+  Invert bool `json:"invert,omitempty"`         // Invert the field before masking (active low inputs)
+  Mask int `json:"mask,omitempty"`              // Bits of the field to compare, all if 0
+  Expected int `json:"expected,omitempty"`      // Value a match check expects
+  Module string `json:"module,omitempty"`       // Module the result belongs to, the sequence module if empty
+}
+
+/* Sequence Command */
+
+type Command struct {
+  Command Code `json:"command"`                 // Command selector, such as SET_SPEED_SENSOR_REF_CMD
+  Arguments []Code `json:"arguments"`           // Command arguments
+}
+
+/* Response Polling */
+
+type Poll struct {
+  Tries int `json:"tries"`                      // Number of readings before the step fails
+  Interval int `json:"interval"`                // Milliseconds between readings
+}
+
+/* Sequence Step */
+
+type Step struct {
+  Group string `json:"group,omitempty"`         // Steps that the operator can skip together
+  Ask string `json:"ask,omitempty"`             // Question to the operator, "n" skips the rest of the group
+  Alerter bool `json:"alerter,omitempty"`       // Only run on an IEM with an alerter
+  Deassert []string `json:"deassert,omitempty"` // Fixture signals to turn off
+  Assert []string `json:"assert,omitempty"`     // Fixture signals to turn on
+  Command *Command `json:"command,omitempty"`   // Command to send to the IEM
+  Settle int `json:"settle,omitempty"`          // Milliseconds to wait before reading
+  Zero bool `json:"zero,omitempty"`             // Subtract a reading taken before the prompt
+  Prompt string `json:"prompt,omitempty"`       // Instructions for the operator
+  WaitKey bool `json:"waitkey,omitempty"`       // Wait for a key after the prompt
+  Selector Code `json:"selector,omitempty"`     // Information selector to read, none if 0
+  Subselector Code `json:"subselector,omitempty"` // Information subselector to read
+  Poll *Poll `json:"poll,omitempty"`            // Keep reading until the checks pass
+  Checks []Check `json:"checks,omitempty"`      // Checks of the response
+  Passed string `json:"passed,omitempty"`       // Message shown if every check since the last message passed
+}
+
+/* Test Sequence */
+
+type Sequence struct {
+  Name string `json:"name"`                     // Name of the test
+  Version string `json:"version"`               // Version of the procedure
+  Module string `json:"module"`                 // Module the results belong to
+  Steps []Step `json:"steps"`                   // Steps in the order they run
+}
+
+var CPUMainSequence Sequence               // Main CPU test sequence
+
+/*
+    Procedure Name : LoadSequence
+
+    Description    : Reads a test sequence file and checks that every
+                     signal, selector, field and module it names exists.
+
+    Arguments      : path - Name of the sequence file
+
+    Return Value   : The sequence
+                     Any error reading or checking the file
+*/
+
+func LoadSequence( path string ) (Sequence, error) {
+  var seq Sequence
+
+  data, err := os.ReadFile( path )
+
+  if err != nil {
+    return seq, err
+  }
+
+  if err = json.Unmarshal( data, &seq ); err != nil {
+    return seq, fmt.Errorf("Bad sequence file %s: %v", path, err)
+  }
+
+  if err = seq.validate(); err != nil {
+    return seq, fmt.Errorf("Sequence file %s: %v", path, err)
+  }
+
+  return seq, nil
+}
+
+/*
+    Procedure Name : LoadSequences
+
+    Description    : Reads the sequence files for the tests that are run
+                     from sequences.
+
+    Arguments      : dir - Directory holding the sequence files
+
+    Return Value   : Any error reading a sequence file
+*/
+
+func LoadSequences( dir string ) error {
+  var err error
+
+  CPUMainSequence, err = LoadSequence( filepath.Join( dir, CPU_MAIN_SEQUENCE ) )
+
+  return err
+}
+
+/*
+    Procedure Name : validate
+
+    Description    : Checks a sequence before it is run so that a mistake
+                     in the file is found when the tester starts rather than
+                     part way through a test.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The first problem found
+*/
+
+func (seq Sequence) validate() error {
+  if seq.Version == "" {
+    return fmt.Errorf("no version")
+  }
+
+  if _, ok := modules[seq.Module]; !ok {
+    return fmt.Errorf("unknown module %q", seq.Module)
+  }
+
+  for n, step := range seq.Steps {
+    for _, name := range append(step.Deassert, step.Assert...) {
+      if _, ok := fixture.Signals[name]; !ok {
+        return fmt.Errorf("step %d: unknown signal %q", n + 1, name)
+      }
+    }
+
+    if step.Command != nil {
+      switch (int) (step.Command.Command) {
+        case SET_SPEED_SENSOR_REF_CMD, REST_CMD :
+          if len(step.Command.Arguments) != 1 {
+            return fmt.Errorf("step %d: command takes one argument", n + 1)
+          }
+
+        case SET_ADCM_LED_STATE_CMD, SET_ADCM_SONALERT_STATE_CMD, SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD :
+          if len(step.Command.Arguments) != 2 {
+            return fmt.Errorf("step %d: command takes two arguments", n + 1)
+          }
+
+        default :
+          return fmt.Errorf("step %d: unknown command %#x", n + 1, (int) (step.Command.Command))
+      }
+    }
+
+    if step.Selector == 0 {
+      if step.Zero || step.Poll != nil || len(step.Checks) != 0 {
+        return fmt.Errorf("step %d: checks without a selector", n + 1)
+      }
+
+      continue
+    }
+
+    parser, ok := responseParsers[(int) (step.Selector)]
+
+    if !ok {
+      return fmt.Errorf("step %d: unknown selector %#x", n + 1, (int) (step.Selector))
+    }
+
+    for _, check := range step.Checks {
+      if check.Module != "" {
+        if _, ok := modules[check.Module]; !ok {
+          return fmt.Errorf("step %d: unknown module %q", n + 1, check.Module)
+        }
+      }
+
+      if _, ok := resultKinds[check.Type]; !ok {
+        return fmt.Errorf("step %d: %s has unknown check type %q", n + 1, check.Name, check.Type)
+      }
+
+      if _, err := responseField( parser.Zero, check ); err != nil {
+        return fmt.Errorf("step %d: %s: %v", n + 1, check.Name, err)
+      }
+    }
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : responseField
+
+    Description    : Finds the field of a parsed response that a check
+                     looks at.
+
+    Arguments      : reading - Parsed response structure
+                     check   - Check naming the field and element
+
+    Return Value   : The field value
+                     Any error finding the field
+*/
+
+func responseField( reading interface{}, check Check ) (reflect.Value, error) {
+  v := reflect.ValueOf( reading ).FieldByName( check.Field )
+
+  if !v.IsValid() {
+    return v, fmt.Errorf("%T has no field %q", reading, check.Field)
+  }
+
+  if v.Kind() == reflect.Array {
+    if check.Index < 0 || check.Index >= v.Len() {
+      return v, fmt.Errorf("%s index %d out of range", check.Field, check.Index)
+    }
+
+    v = v.Index( check.Index )
+  }
+
+  return v, nil
+}
+
+/*
+    Procedure Name : fieldValue
+
+    Description    : Converts a numeric or flag field to an int, applying
+                     the inversion and mask of the check.
+
+    Arguments      : v     - Field value
+                     check - Check being made
+
+    Return Value   : The value
+*/
+
+func fieldValue( v reflect.Value, check Check ) int {
+  value := 0
+
+  switch v.Kind() {
+    case reflect.Bool :
+      if v.Bool() {
+        value = 1
+      }
+
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64 :
+      value = (int) (v.Int())
+
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64 :
+      value = (int) (v.Uint())
+  }
+
+  if check.Invert {
+    value = ^value & ((1 << (8*v.Type().Size())) - 1)
+  }
+
+  if check.Mask != 0 {
+    value &= check.Mask
+  }
+
+  return value
+}
+
+/*
+    Procedure Name : testTime
+
+    Description    : Formats the current time and date the way they are
+                     stored in the test results.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Time string
+                     Date string
+*/
+
+func testTime() (string, string) {
+  timeDate := time.Now()
+  hour, min, sec := timeDate.Clock()
+  year, month, day := timeDate.Date()
+
+  return fmt.Sprintf("%2.2d:%2.2d:%2.2d", hour, min, sec), fmt.Sprintf("%2.2d/%2.2d/%4d", month, day, year)
+}
+
+/*
+    Procedure Name : readKey
+
+    Description    : Waits for the operator to press a key.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The key
+*/
+
+func readKey() byte {
+  char, err := ConsoleInput.ReadByte()
+
+  for err != nil && char == 0 {
+    char, err = ConsoleInput.ReadByte()
+  }
+
+  ConsoleInput.Reset(ConsoleSource)
+
+  return char
+}
+
+/*
+    Procedure Name : readResponse
+
+    Description    : Asks the IEM for an information selector and parses
+                     the response.
+
+    Arguments      : step - Step naming the selector and subselector
+
+    Return Value   : The parsed response
+                     Any error talking to the IEM or parsing the response
+*/
+
+func readResponse( step Step ) (interface{}, error) {
+  response := make([] byte, 50)
+
+  responseCount, err := InformationSelectionCommand( (int) (step.Selector), (int) (step.Subselector), response )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return responseParsers[(int) (step.Selector)].Parse( response, responseCount )
+}
+
+/*
+    Procedure Name : sendCommand
+
+    Description    : Sends the command of a step to the IEM.
+
+    Arguments      : command - Command and its arguments
+
+    Return Value   : Any error sending the command
+*/
+
+func sendCommand( command *Command ) error {
+  var responseCount int
+  var err error
+
+  a := command.Arguments
+
+  switch (int) (command.Command) {
+    case SET_SPEED_SENSOR_REF_CMD :
+      responseCount, err = SetSpeedSensorRefCommand( (int) (a[0]) )
+
+    case REST_CMD :
+      responseCount, err = ResetCounterCommand( (int) (a[0]) )
+
+    case SET_ADCM_LED_STATE_CMD :
+      responseCount, err = SetADCM_LEDStateCommand( (int) (a[0]), (int) (a[1]) )
+
+    case SET_ADCM_SONALERT_STATE_CMD :
+      responseCount, err = SetADCMSonalertStateCommand( (int) (a[0]), (int) (a[1]) )
+
+    case SET_ABCM_MAG_VALVE_DRIVE_STATE_CMD :
+      responseCount, err = SetABCMMagValveDriveStateCommand( (int) (a[0]), (int) (a[1]) )
+  }
+
+  /*
+      The speed sensor reference, LEDs and sonalert cannot be read back;
+      the checks of the step show whether the command took.
+  */
+
+  err = allowUnconfirmed( err )
+
+  if err == nil && responseCount != 1 {
+    err = fmt.Errorf("Command %#x was not accepted.", (int) (command.Command))
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : signals
+
+    Description    : Looks up fixture signals by their spreadsheet names.
+                     The names were checked when the sequence was loaded.
+
+    Arguments      : names - Signal names
+
+    Return Value   : The signals
+*/
+
+func signals( names []string ) []fixture.Signal {
+  list := make([]fixture.Signal, 0, len(names))
+
+  for _, name := range names {
+    list = append(list, fixture.Signals[name])
+  }
+
+  return list
+}
+
+/*
+    Procedure Name : runCheck
+
+    Description    : Checks one field of a response and stores the result.
+                     A ranged check with a zero reading is judged and
+                     stored on the change from the zero reading, which
+                     is kept with the result.
+
+    Arguments      : check   - Check to make
+                     module  - Module the result belongs to
+                     reading - Parsed response
+                     zero    - Parsed response taken before the stimulus,
+                               nil if none
+                     start   - When the step applied its stimulus
+
+    Return Value   : true if the check passed
+*/
+
+func runCheck( check Check, module string, reading interface{}, zero interface{}, start time.Time ) bool {
+  v, _ := responseField( reading, check )
+  value := fieldValue( v, check )
+  offset := 0
+
+  if zero != nil {
+    z, _ := responseField( zero, check )
+    offset = fieldValue( z, check )
+  }
+
+  if check.Module != "" {
+    module = check.Module
+  }
+
+  Test := NewResult( resultKinds[check.Type] )
+
+  Test.AssemblyPartNumber = AssemblyPartNumber
+  Test.AssemblySerialNumber = AssemblySerialNumber
+  Test.PartNumber, Test.SerialNumber = modules[module]()
+  Test.TestName = check.Name
+  Test.Label = check.Label
+  Test.Time, Test.Date = testTime()
+  Test.Pass = true
+
+  switch check.Type {
+    case CHECK_RANGE, CHECK_LOWER, CHECK_UPPER :
+      limit := TestLimit( check.Name )
+
+      Test.Value = results.Int( value - offset )
+      Test.Units = limit.Units
+      Test.Inclusive = results.Bool( limit.Inclusive )
+      Test.LimitsVersion = Limits.Version
+
+      if zero != nil {
+        Test.Zero = results.Int( offset )
+      }
+
+      if check.Type != CHECK_UPPER {
+        Test.LowerLimit = limit.Min
+      }
+
+      if check.Type != CHECK_LOWER {
+        Test.UpperLimit = limit.Max
+      }
+
+      if !limit.Check( value - offset ) {
+        fmt.Printf(" %s Failed (Got %d Expected %s)\r\n", check.Label, value - offset, limit)
+
+        Test.Pass = false
+      }
+
+    case CHECK_MATCH :
+      Test.Value = results.Int( value )
+      Test.Expected = results.Int( check.Expected )
+
+      if value != check.Expected {
+        fmt.Printf(" %s Failed (Got %X Expected %X)\r\n", check.Label, value, check.Expected)
+
+        Test.Pass = false
+      }
+
+    case CHECK_FLAG, CHECK_PING :
+      Test.Pass = value != 0
+
+      if check.Type == CHECK_PING {
+        out, _ := exec.Command("ping", fmt.Sprint( v.Interface() ), "-c 3", "-w 3").Output()
+
+        fmt.Printf("%s", out)
+
+        Test.Pass = strings.Contains( string(out), " 0% packet loss" )
+      }
+
+      if Test.Pass {
+        fmt.Printf("%s Passed.\r\n", check.Label)
+      } else {
+        fmt.Printf("%s Failed.\r\n", check.Label)
+      }
+
+    case CHECK_HARDWARE :
+      fmt.Printf("%s %d\r\n", check.Label, value)
+
+      Test.Value = results.Int( value )
+
+    case CHECK_SOFTWARE :
+      fmt.Printf("%s %s\r\n", check.Label, v.String())
+
+      Test.Text = v.String()
+  }
+
+  elapsed := time.Since( start ).Milliseconds()
+
+  Test.Elapsed = &elapsed
+
+  Test.SetID(couchdb.GenerateUUID())
+
+  SaveResult( &Test )
+
+  return Test.Pass
+}
+
+/*
+    Procedure Name : pollPassed
+
+    Description    : Reports whether every check of a polled step passes
+                     on a reading, without storing anything.
+
+    Arguments      : step    - Step being polled
+                     reading - Parsed response
+                     zero    - Parsed response taken before the prompt,
+                               nil if none
+
+    Return Value   : true once the checks pass
+*/
+
+func pollPassed( step Step, reading interface{}, zero interface{} ) bool {
+  for _, check := range step.Checks {
+    v, _ := responseField( reading, check )
+    value := fieldValue( v, check )
+
+    if zero != nil {
+      z, _ := responseField( zero, check )
+      value -= fieldValue( z, check )
+    }
+
+    switch check.Type {
+      case CHECK_RANGE, CHECK_LOWER, CHECK_UPPER :
+        if !TestLimit( check.Name ).Check( value ) {
+          return false
+        }
+
+      case CHECK_MATCH :
+        if value != check.Expected {
+          return false
+        }
+
+      case CHECK_FLAG :
+        if value == 0 {
+          return false
+        }
+    }
+  }
+
+  return true
+}
+
+/*
+    Procedure Name : RunSequence
+
+    Description    : Runs the steps of a test sequence and stores a result
+                     for every check. An error talking to the IEM stops
+                     the sequence. A result that cannot be stored is
+                     counted in UnsavedResults and the sequence carries
+                     on. The fixture is put back to its initial state at
+                     the end.
+
+    Arguments      : fix     - Port extenders of the test fixture
+                     seq     - Sequence to run
+                     IEMType - 0 for no alerter, 1 for alerter present
+
+    Return Value   : Flag indicating whether the test passed or failed.
+                     Any error that occurred during the test.
+*/
+
+func RunSequence( fix fixture.Fixture, seq Sequence, IEMType int ) (int, error) {
+  var err error = nil
+  var reading interface{}
+
+  RetValue := 1
+  Passed := 1
+  skipped := make(map[string]bool)
+
+  for n := 0;n < len(seq.Steps) && err == nil;n++ {
+    step := seq.Steps[n]
+
+    if step.Alerter && IEMType != IEM_WITH_ALERTER {
+      continue
+    }
+
+    if step.Group != "" && skipped[step.Group] {
+      continue
+    }
+
+    if step.Ask != "" {
+      fmt.Printf("\r\n%s y or n (y default) : ", step.Ask)
+
+      if readKey() == 'n' {
+        skipped[step.Group] = true
+
+        continue
+      }
+    }
+
+    /*
+        The time each check took is measured from here, on the monotonic
+        clock, and stored with its result.
+    */
+
+    start := time.Now()
+
+    if len(step.Deassert) != 0 || len(step.Assert) != 0 {
+      err = fixture.Switch( fix, signals( step.Deassert ), signals( step.Assert ) )
+    }
+
+    if err == nil && step.Command != nil {
+      err = sendCommand( step.Command )
+    }
+
+    if err == nil && step.Settle > 0 {
+      time.Sleep( time.Duration(step.Settle)*time.Millisecond )
+    }
+
+    /*
+        A zero reading is taken before the operator is told to apply the
+        stimulus, so any offset can be taken out of the checks.
+    */
+
+    var zero interface{}
+
+    if err == nil && step.Zero {
+      zero, err = readResponse( step )
+    }
+
+    if err == nil && step.Prompt != "" {
+      fmt.Printf("\r\n%s\r\n", step.Prompt)
+
+      if step.WaitKey {
+        fmt.Printf("Hit any key when ready.\r\n")
+
+        readKey()
+      }
+    }
+
+    if err == nil && step.Selector != 0 {
+      reading, err = readResponse( step )
+
+      if step.Poll != nil {
+        /*
+            Keep reading until the checks pass or the operator runs out of
+            time, showing the seconds left.
+        */
+
+        interval := time.Duration(step.Poll.Interval)*time.Millisecond
+
+        for i := 1;err == nil && i < step.Poll.Tries && !pollPassed( step, reading, zero );i++ {
+          time.Sleep( interval )
+
+          fmt.Printf("\r%2d", (int) (time.Duration(step.Poll.Tries - i)*interval/time.Second))
+
+          reading, err = readResponse( step )
+        }
+
+        fmt.Printf("\r\n")
+      }
+    }
+
+    if err != nil {
+      RetValue = 0
+
+      log.Printf("%q", err)
+
+      break
+    }
+
+    for _, check := range step.Checks {
+      if !runCheck( check, seq.Module, reading, zero, start ) {
+        RetValue = 0
+        Passed = 0
+      }
+    }
+
+    if step.Passed != "" {
+      if Passed == 1 {
+        fmt.Printf(" %s\r\n", step.Passed)
+      }
+
+      Passed = 1
+    }
+  }
+
+  /*
+       Set the IEM tester back to its initial condition.
+  */
+
+  _, err1 := Init( fix )
+
+  if err == nil {
+    err = err1
+  }
+
+  return RetValue, err
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+        "encoding/json"
+        "path/filepath"
+        "strings"
+        "testing"
+)
+
+/*
+    Procedure Name : TestSequenceValidate
+
+    Description    : Checks that a sequence naming a signal, selector,
+                     command, field or module that does not exist is
+                     refused with the step it is in, and that a good
+                     sequence is accepted.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestSequenceValidate( t *testing.T ) {
+  tests := []struct {
+    name string
+    sequence string
+    err string
+  }{
+    { "good", `{ "version": "1", "module": "main", "steps": [
+        { "assert": ["74V_NOGO_1"], "settle": 200 },
+        { "command": { "command": "SET_ADCM_LED_STATE_CMD", "arguments": [1, 0] } },
+        { "selector": "MON_VOLTAGES", "subselector": "IEM_CPU_BOARD_03", "checks": [
+          { "name": "Volts", "type": "range", "field": "Voltages", "index": 2, "module": "power" } ] } ] }`, "" },
+
+    { "no version", `{ "module": "main", "steps": [] }`, "no version" },
+
+    { "unknown module", `{ "version": "1", "module": "cpu", "steps": [] }`, `unknown module "cpu"` },
+
+    { "unknown signal", `{ "version": "1", "module": "main", "steps": [
+        { "settle": 200 },
+        { "deassert": ["74V_NOGO_99"] } ] }`, `step 2: unknown signal "74V_NOGO_99"` },
+
+    { "command arguments", `{ "version": "1", "module": "main", "steps": [
+        { "command": { "command": "SET_SPEED_SENSOR_REF_CMD", "arguments": [1, 2] } } ] }`, "step 1: command takes one argument" },
+
+    { "unknown command", `{ "version": "1", "module": "main", "steps": [
+        { "command": { "command": "MON_VOLTAGES", "arguments": [] } } ] }`, "step 1: unknown command" },
+
+    { "checks without a selector", `{ "version": "1", "module": "main", "steps": [
+        { "checks": [ { "name": "Volts", "type": "range", "field": "Voltages" } ] } ] }`, "step 1: checks without a selector" },
+
+    { "zero without a selector", `{ "version": "1", "module": "main", "steps": [
+        { "zero": true } ] }`, "step 1: checks without a selector" },
+
+    { "selector without a parser", `{ "version": "1", "module": "main", "steps": [
+        { "selector": 127 } ] }`, "step 1: unknown selector 0x7f" },
+
+    { "unknown check type", `{ "version": "1", "module": "main", "steps": [
+        { "selector": "MON_VOLTAGES", "checks": [ { "name": "Volts", "type": "between", "field": "Voltages" } ] } ] }`,
+      `step 1: Volts has unknown check type "between"` },
+
+    { "unknown field", `{ "version": "1", "module": "main", "steps": [
+        { "selector": "MON_VOLTAGES", "checks": [ { "name": "Volts", "type": "range", "field": "Volts" } ] } ] }`,
+      `step 1: Volts: main.MonitoredVoltages has no field "Volts"` },
+
+    { "index out of range", `{ "version": "1", "module": "main", "steps": [
+        { "selector": "MON_VOLTAGES", "checks": [ { "name": "Volts", "type": "range", "field": "Voltages", "index": 99 } ] } ] }`,
+      "step 1: Volts: Voltages index 99 out of range" },
+
+    { "unknown check module", `{ "version": "1", "module": "main", "steps": [
+        { "selector": "MON_VOLTAGES", "checks": [ { "name": "Volts", "type": "range", "field": "Voltages", "module": "psu" } ] } ] }`,
+      `step 1: unknown module "psu"` },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      var seq Sequence
+
+      if err := json.Unmarshal( []byte(tt.sequence), &seq ); err != nil {
+        t.Fatalf("Unmarshal: %v", err)
+      }
+
+      err := seq.validate()
+
+      switch {
+        case tt.err == "" && err != nil :
+          t.Errorf("validate: %v", err)
+
+        case tt.err != "" && (err == nil || !strings.Contains( err.Error(), tt.err )) :
+          t.Errorf("error %v, want %q", err, tt.err)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestCodeUnmarshal
+
+    Description    : Checks that a code is read from a number or the name
+                     of a protocol constant, and that anything else is
+                     refused.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestCodeUnmarshal( t *testing.T ) {
+  tests := []struct {
+    data string
+    want Code
+    ok bool
+  }{
+    { `"MON_VOLTAGES"`, (Code) (MON_VOLTAGES), true },
+
+    { `16`, 16, true },
+
+    { `"MON_VOLTS"`, 0, false },
+
+    { `true`, 0, false },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.data, func( t *testing.T ) {
+      var c Code
+
+      err := json.Unmarshal( []byte(tt.data), &c )
+
+      if (err == nil) != tt.ok || c != tt.want {
+        t.Errorf("code %#x error %v, want %#x", (int) (c), err, (int) (tt.want))
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestShippedSequences
+
+    Description    : Checks that the sequence files shipped with the
+                     tester load.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestShippedSequences( t *testing.T ) {
+  if _, err := LoadSequence( filepath.Join( DEFAULT_SEQUENCE_DIR, CPU_MAIN_SEQUENCE ) ); err != nil {
+    t.Errorf("LoadSequence: %v", err)
+  }
+}
@@ -0,0 +1,40 @@
+package sinks
+
+import (
+        "github.com/questrail/IEMTestDB/journal"
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* CouchDB, through the journal that replicates to it */
+
+type couchSink struct {
+  j *journal.Journal               // Journal replicated to the test database
+}
+
+/*
+    Procedure Name : NewCouchDB
+
+    Description    : Returns a sink that writes to the journal. The
+                     journal's replicator copies the documents to the
+                     test database.
+
+    Arguments      : j - Open journal
+
+    Return Value   : The sink
+*/
+
+func NewCouchDB( j *journal.Journal ) ResultSink {
+  return couchSink{ j: j }
+}
+
+func (s couchSink) SaveResult( result *results.TestResult ) error {
+  return s.j.Put( result.GetID(), result )
+}
+
+func (s couchSink) SaveRun( run *results.Run ) error {
+  return s.j.Put( run.GetID(), run )
+}
+
+func (s couchSink) Close() error {
+  return nil
+}
@@ -0,0 +1,211 @@
+package sinks
+
+import (
+        "encoding/csv"
+        "fmt"
+        "os"
+        "path/filepath"
+        "strconv"
+        "strings"
+        "sync"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* Column headings of a unit's CSV file */
+
+var csvHeader = []string{ "run_id", "timestamp", "date", "time", "partnumber", "serialnumber", "name", "label", "kind",
+                          "value", "zero", "text", "expected", "lowerlimit", "upperlimit", "inclusive", "units",
+                          "limitsversion", "pass" }
+
+/* One CSV file per unit */
+
+type csvSink struct {
+  mu sync.Mutex                    // Keeps rows whole
+  dir string                       // Directory of the unit files
+}
+
+/*
+    Procedure Name : OpenCSV
+
+    Description    : Returns a sink that appends each result to a CSV file
+                     named after the assembly serial number, so that every
+                     unit has one file to attach to its traveler. Runs are
+                     not written.
+
+    Arguments      : dir - Directory for the files, created if needed
+
+    Return Value   : The sink
+                     Any error creating the directory
+*/
+
+func OpenCSV( dir string ) (ResultSink, error) {
+  err := os.MkdirAll( dir, 0755 )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return &csvSink{ dir: dir }, nil
+}
+
+/*
+    Procedure Name : optional
+
+    Description    : Formats an optional number for a CSV cell.
+
+    Arguments      : v - Number, nil if not set
+
+    Return Value   : The cell, empty if not set
+*/
+
+func optional( v *int ) string {
+  if v == nil {
+    return ""
+  }
+
+  return strconv.Itoa( *v )
+}
+
+/*
+    Procedure Name : optionalBool
+
+    Description    : Formats an optional flag for a CSV cell.
+
+    Arguments      : v - Flag, nil if not set
+
+    Return Value   : The cell, empty if not set
+*/
+
+func optionalBool( v *bool ) string {
+  if v == nil {
+    return ""
+  }
+
+  return strconv.FormatBool( *v )
+}
+
+/*
+    Procedure Name : moveAside
+
+    Description    : Renames a unit file whose columns are not those of
+                     this tester, so that its rows are kept and a new
+                     file is started. The old file gets the first free
+                     number, such as 1234.1.csv.
+
+    Arguments      : name - File name
+
+    Return Value   : Any error reading or renaming the file, nil if the
+                     file does not exist or has the current columns
+*/
+
+func moveAside( name string ) error {
+  f, err := os.Open( name )
+
+  if os.IsNotExist( err ) {
+    return nil
+  }
+
+  if err != nil {
+    return err
+  }
+
+  header, err := csv.NewReader( f ).Read()
+
+  f.Close()
+
+  if err == nil && strings.Join( header, "," ) == strings.Join( csvHeader, "," ) {
+    return nil
+  }
+
+  base := strings.TrimSuffix( name, ".csv" )
+
+  for n := 1;;n++ {
+    old := fmt.Sprintf("%s.%d.csv", base, n)
+
+    if _, err = os.Stat( old ); os.IsNotExist( err ) {
+      return os.Rename( name, old )
+    }
+  }
+}
+
+/*
+    Procedure Name : fileName
+
+    Description    : Turns a serial number into a safe file name.
+
+    Arguments      : serial - Assembly serial number
+
+    Return Value   : The file name
+*/
+
+func fileName( serial string ) string {
+  name := strings.Map( func( r rune ) rune {
+    if r == '/' || r == '\\' || r == os.PathSeparator || r < ' ' {
+      return '_'
+    }
+
+    return r
+  }, strings.TrimSpace( serial ) )
+
+  if name == "" || name == "." || name == ".." {
+    name = "unknown"
+  }
+
+  return name + ".csv"
+}
+
+func (s *csvSink) SaveResult( result *results.TestResult ) error {
+  row := []string{ result.RunID, result.Timestamp, result.Date, result.Time, result.PartNumber, result.SerialNumber,
+                   result.TestName, result.Label, result.Kind, optional( result.Value ), optional( result.Zero ), result.Text,
+                   optional( result.Expected ), optional( result.LowerLimit ), optional( result.UpperLimit ),
+                   optionalBool( result.Inclusive ), result.Units, result.LimitsVersion, strconv.FormatBool( result.Pass ) }
+
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  name := filepath.Join( s.dir, fileName( result.AssemblySerialNumber ) )
+
+  err := moveAside( name )
+
+  if err != nil {
+    return err
+  }
+
+  _, statErr := os.Stat( name )
+
+  f, err := os.OpenFile( name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644 )
+
+  if err != nil {
+    return err
+  }
+
+  w := csv.NewWriter( f )
+
+  if os.IsNotExist( statErr ) {
+    w.Write( csvHeader )
+  }
+
+  w.Write( row )
+  w.Flush()
+
+  err = w.Error()
+
+  if err == nil {
+    err = f.Sync()
+  }
+
+  if closeErr := f.Close(); err == nil {
+    err = closeErr
+  }
+
+  return err
+}
+
+func (s *csvSink) SaveRun( run *results.Run ) error {
+  return nil
+}
+
+func (s *csvSink) Close() error {
+  return nil
+}
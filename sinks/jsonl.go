@@ -0,0 +1,81 @@
+package sinks
+
+import (
+        "encoding/json"
+        "os"
+        "sync"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* JSON lines file */
+
+type jsonlSink struct {
+  mu sync.Mutex                    // Keeps lines whole
+  f *os.File                       // File being appended to
+}
+
+/*
+    Procedure Name : OpenJSONL
+
+    Description    : Opens a file that every result and run is appended to
+                     as one line of JSON. The "kind" field tells them
+                     apart; a run appears twice, and the later line is the
+                     finished run.
+
+    Arguments      : path - File name
+
+    Return Value   : The sink
+                     Any error opening the file
+*/
+
+func OpenJSONL( path string ) (ResultSink, error) {
+  f, err := os.OpenFile( path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644 )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return &jsonlSink{ f: f }, nil
+}
+
+/*
+    Procedure Name : write
+
+    Description    : Appends one document to the file and syncs it.
+
+    Arguments      : doc - Document
+
+    Return Value   : Any error writing the line
+*/
+
+func (s *jsonlSink) write( doc interface{} ) error {
+  line, err := json.Marshal( doc )
+
+  if err != nil {
+    return err
+  }
+
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  _, err = s.f.Write( append(line, '\n') )
+
+  if err == nil {
+    err = s.f.Sync()
+  }
+
+  return err
+}
+
+func (s *jsonlSink) SaveResult( result *results.TestResult ) error {
+  return s.write( result )
+}
+
+func (s *jsonlSink) SaveRun( run *results.Run ) error {
+  return s.write( run )
+}
+
+func (s *jsonlSink) Close() error {
+  return s.f.Close()
+}
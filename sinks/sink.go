@@ -0,0 +1,23 @@
+/*
+   Package sinks provides the places the tester saves its results and
+   runs. CouchDB, through the journal, is the system of record; SQLite,
+   CSV and JSON lines copies can be kept beside it for the quality team,
+   the unit traveler and the MES.
+
+   A sink may be given the same run twice, once when it starts and again
+   when it finishes, and must keep only the latest.
+*/
+
+package sinks
+
+import (
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/* Result Sink Interface */
+
+type ResultSink interface {
+  SaveResult( result *results.TestResult ) error   // Save one test result
+  SaveRun( run *results.Run ) error                 // Save or update a run
+  Close() error                                     // Release the sink
+}
@@ -0,0 +1,352 @@
+package sinks
+
+import (
+        "database/sql"
+        "encoding/json"
+        "fmt"
+        "strings"
+
+        "github.com/questrail/IEMTestDB/results"
+        _ "modernc.org/sqlite"
+)
+
+/*
+   Layout of the tables, kept in the database as PRAGMA user_version.
+   Version 0 is a database made before the version was kept, or a new
+   one. Version 1 is the tables below. A database is brought up to date
+   when it is opened: missing columns are added and duration_ms, the old
+   name of since_previous_ms, is renamed.
+*/
+
+const SQLITE_VERSION = 1
+
+/* Column of a table */
+
+type sqliteColumn struct {
+  name string                      // Column name, the JSON field name of the document
+  decl string                      // Type and constraints
+}
+
+/*
+   Tables of the SQLite copy. The columns follow the JSON field names of
+   the documents; the modules and link counts of a run are kept as JSON
+   text. New columns go at the end, so that they can be added to an
+   existing table.
+*/
+
+var sqliteTables = []struct {
+  name string
+  columns []sqliteColumn
+}{
+  { "runs", []sqliteColumn{
+    { "id", "TEXT PRIMARY KEY" },
+    { "schema_version", "INTEGER" },
+    { "test", "TEXT" },
+    { "assemblypartnumber", "TEXT" },
+    { "assemblyserialnumber", "TEXT" },
+    { "modules", "TEXT" },
+    { "operator", "TEXT" },
+    { "station", "TEXT" },
+    { "softwareversion", "TEXT" },
+    { "limitsversion", "TEXT" },
+    { "start", "TEXT" },
+    { "end", "TEXT" },
+    { "verdict", "TEXT" },
+    { "unsavedresults", "INTEGER" },
+    { "link", "TEXT" },
+  } },
+  { "results", []sqliteColumn{
+    { "id", "TEXT PRIMARY KEY" },
+    { "schema_version", "INTEGER" },
+    { "kind", "TEXT" },
+    { "run_id", "TEXT" },
+    { "assemblypartnumber", "TEXT" },
+    { "assemblyserialnumber", "TEXT" },
+    { "partnumber", "TEXT" },
+    { "serialnumber", "TEXT" },
+    { "name", "TEXT" },
+    { "time", "TEXT" },
+    { "date", "TEXT" },
+    { "timestamp", "TEXT" },
+    { "since_previous_ms", "INTEGER" },
+    { "value", "INTEGER" },
+    { "text", "TEXT" },
+    { "expected", "INTEGER" },
+    { "lowerlimit", "INTEGER" },
+    { "upperlimit", "INTEGER" },
+    { "units", "TEXT" },
+    { "limitsversion", "TEXT" },
+    { "pass", "INTEGER" },
+    { "inclusive", "INTEGER" },
+    { "label", "TEXT" },
+    { "elapsed_ms", "INTEGER" },
+    { "zero", "INTEGER" },
+  } },
+}
+
+const sqliteIndexes = `
+CREATE INDEX IF NOT EXISTS results_serial ON results (assemblyserialnumber);
+CREATE INDEX IF NOT EXISTS results_run ON results (run_id);
+`
+
+/* Embedded SQLite database */
+
+type sqliteSink struct {
+  db *sql.DB                       // Open database
+}
+
+/* Column and the value to store in it */
+
+type sqliteField struct {
+  name string                      // Column name
+  value interface{}                // Value, nil for NULL
+}
+
+/*
+    Procedure Name : migrateSQLite
+
+    Description    : Creates the tables, or brings the tables of an older
+                     database up to SQLITE_VERSION. A database written by
+                     a newer tester is left alone.
+
+    Arguments      : db - Open database
+
+    Return Value   : Any error creating or changing the tables
+*/
+
+func migrateSQLite( db *sql.DB ) error {
+  var version int
+
+  err := db.QueryRow( "PRAGMA user_version" ).Scan( &version )
+
+  if err != nil || version >= SQLITE_VERSION {
+    return err
+  }
+
+  tx, err := db.Begin()
+
+  if err != nil {
+    return err
+  }
+
+  for t := 0;t < len(sqliteTables) && err == nil;t++ {
+    table := sqliteTables[t]
+
+    var have map[string]bool
+
+    have, err = sqliteColumns( tx, table.name )
+
+    if err != nil {
+      break
+    }
+
+    switch {
+      case len(have) == 0 :
+        decls := []string{}
+
+        for _, c := range table.columns {
+          decls = append(decls, fmt.Sprintf("%q %s", c.name, c.decl))
+        }
+
+        _, err = tx.Exec( fmt.Sprintf("CREATE TABLE %q (%s)", table.name, strings.Join( decls, ", " )) )
+
+      default :
+        if have["duration_ms"] && !have["since_previous_ms"] {
+          _, err = tx.Exec( fmt.Sprintf("ALTER TABLE %q RENAME COLUMN duration_ms TO since_previous_ms", table.name) )
+
+          have["since_previous_ms"] = true
+        }
+
+        for i := 0;i < len(table.columns) && err == nil;i++ {
+          c := table.columns[i]
+
+          if !have[c.name] {
+            _, err = tx.Exec( fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q %s", table.name, c.name, c.decl) )
+          }
+        }
+    }
+  }
+
+  if err == nil {
+    _, err = tx.Exec( sqliteIndexes )
+  }
+
+  if err == nil {
+    _, err = tx.Exec( fmt.Sprintf("PRAGMA user_version = %d", SQLITE_VERSION) )
+  }
+
+  if err != nil {
+    tx.Rollback()
+
+    return err
+  }
+
+  return tx.Commit()
+}
+
+/*
+    Procedure Name : sqliteColumns
+
+    Description    : Lists the columns a table has.
+
+    Arguments      : tx    - Transaction to read in
+                     table - Table name
+
+    Return Value   : The column names, empty if there is no such table
+                     Any error reading them
+*/
+
+func sqliteColumns( tx *sql.Tx, table string ) (map[string]bool, error) {
+  rows, err := tx.Query( "SELECT name FROM pragma_table_info(?)", table )
+
+  if err != nil {
+    return nil, err
+  }
+
+  defer rows.Close()
+
+  have := map[string]bool{}
+
+  for rows.Next() && err == nil {
+    var name string
+
+    err = rows.Scan( &name )
+
+    have[name] = true
+  }
+
+  if err == nil {
+    err = rows.Err()
+  }
+
+  return have, err
+}
+
+/*
+    Procedure Name : insert
+
+    Description    : Stores a row, replacing any row with the same ID,
+                     naming every column it fills.
+
+    Arguments      : table  - Table name
+                     fields - Columns and their values
+
+    Return Value   : Any error storing the row
+*/
+
+func (s *sqliteSink) insert( table string, fields []sqliteField ) error {
+  names := make([]string, len(fields))
+  values := make([]interface{}, len(fields))
+
+  for i, f := range fields {
+    names[i] = fmt.Sprintf("%q", f.name)
+    values[i] = f.value
+  }
+
+  query := fmt.Sprintf("INSERT OR REPLACE INTO %q (%s) VALUES (?%s)", table, strings.Join( names, ", " ),
+                       strings.Repeat( ", ?", len(fields) - 1 ))
+
+  _, err := s.db.Exec( query, values... )
+
+  return err
+}
+
+/*
+    Procedure Name : OpenSQLite
+
+    Description    : Opens an SQLite database file, creating the tables if
+                     they are not there and bringing the tables of an
+                     older tester up to date.
+
+    Arguments      : path - Database file name
+
+    Return Value   : The sink
+                     Any error opening the database
+*/
+
+func OpenSQLite( path string ) (ResultSink, error) {
+  db, err := sql.Open( "sqlite", path )
+
+  if err == nil {
+    err = migrateSQLite( db )
+
+    if err != nil {
+      db.Close()
+    }
+  }
+
+  if err != nil {
+    return nil, err
+  }
+
+  return &sqliteSink{ db: db }, nil
+}
+
+func (s *sqliteSink) SaveResult( r *results.TestResult ) error {
+  return s.insert( "results", []sqliteField{
+    { "id", r.GetID() },
+    { "schema_version", r.SchemaVersion },
+    { "kind", r.Kind },
+    { "run_id", r.RunID },
+    { "assemblypartnumber", r.AssemblyPartNumber },
+    { "assemblyserialnumber", r.AssemblySerialNumber },
+    { "partnumber", r.PartNumber },
+    { "serialnumber", r.SerialNumber },
+    { "name", r.TestName },
+    { "label", r.Label },
+    { "time", r.Time },
+    { "date", r.Date },
+    { "timestamp", r.Timestamp },
+    { "since_previous_ms", r.SincePrevious },
+    { "elapsed_ms", r.Elapsed },
+    { "value", r.Value },
+    { "zero", r.Zero },
+    { "text", r.Text },
+    { "expected", r.Expected },
+    { "lowerlimit", r.LowerLimit },
+    { "upperlimit", r.UpperLimit },
+    { "inclusive", r.Inclusive },
+    { "units", r.Units },
+    { "limitsversion", r.LimitsVersion },
+    { "pass", r.Pass },
+  } )
+}
+
+func (s *sqliteSink) SaveRun( r *results.Run ) error {
+  modules, err := json.Marshal( r.Modules )
+
+  var link interface{}
+
+  if err == nil && r.Link != nil {
+    var data []byte
+
+    data, err = json.Marshal( r.Link )
+    link = string(data)
+  }
+
+  if err == nil {
+    err = s.insert( "runs", []sqliteField{
+      { "id", r.GetID() },
+      { "schema_version", r.SchemaVersion },
+      { "test", r.Test },
+      { "assemblypartnumber", r.AssemblyPartNumber },
+      { "assemblyserialnumber", r.AssemblySerialNumber },
+      { "modules", string(modules) },
+      { "operator", r.Operator },
+      { "station", r.Station },
+      { "softwareversion", r.SoftwareVersion },
+      { "limitsversion", r.LimitsVersion },
+      { "start", r.Start },
+      { "end", r.End },
+      { "verdict", r.Verdict },
+      { "unsavedresults", r.UnsavedResults },
+      { "link", link },
+    } )
+  }
+
+  return err
+}
+
+func (s *sqliteSink) Close() error {
+  return s.db.Close()
+}
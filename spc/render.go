@@ -0,0 +1,241 @@
+package spc
+
+import (
+        "fmt"
+        "html"
+        "io"
+        "math"
+        "sort"
+        "strings"
+        "text/tabwriter"
+)
+
+/*
+    Procedure Name : number
+
+    Description    : Formats a statistic for the table.
+
+    Arguments      : v - Statistic, NaN if it does not apply
+
+    Return Value   : The statistic, "-" if it does not apply
+*/
+
+func number( v float64 ) string {
+  if math.IsNaN( v ) || math.IsInf( v, 0 ) {
+    return "-"
+  }
+
+  return fmt.Sprintf("%.2f", v)
+}
+
+/*
+    Procedure Name : Alerts
+
+    Description    : Lists what is wrong with a chart in words.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The alerts, empty if the process looks in control
+*/
+
+func (c Chart) Alerts() []string {
+  alerts := []string{}
+  rules := map[int]int{}
+
+  for _, v := range c.Violations {
+    rules[v.Rule]++
+  }
+
+  keys := []int{}
+
+  for rule := range rules {
+    keys = append(keys, rule)
+  }
+
+  sort.Ints( keys )
+
+  for _, rule := range keys {
+    alerts = append(alerts, fmt.Sprintf("rule %d x%d (%s)", rule, rules[rule], RuleDescriptions[rule]))
+  }
+
+  if c.Drift {
+    alerts = append(alerts, fmt.Sprintf("drift %+.1f sigma", c.Shift))
+  }
+
+  if c.Trend {
+    alerts = append(alerts, fmt.Sprintf("trend over the last %d points", TREND_LENGTH))
+  }
+
+  if !math.IsNaN( c.Cpk ) && c.Cpk < 1.33 {
+    alerts = append(alerts, "Cpk below 1.33")
+  }
+
+  return alerts
+}
+
+/*
+    Procedure Name : Text
+
+    Description    : Writes a table of the charts.
+
+    Arguments      : w      - Where to write it
+                     charts - Charts
+
+    Return Value   : Any error writing
+*/
+
+func Text( w io.Writer, charts []Chart ) error {
+  t := tabwriter.NewWriter( w, 0, 8, 2, ' ', 0 )
+
+  fmt.Fprintf(t, "Test\tN\tMean\tSigma\tStdDev\tLSL\tUSL\tCp\tCpk\tAlerts\n")
+
+  for _, c := range charts {
+    lsl, usl := "-", "-"
+
+    if c.LSL != nil {
+      lsl = fmt.Sprint( *c.LSL )
+    }
+
+    if c.USL != nil {
+      usl = fmt.Sprint( *c.USL )
+    }
+
+    fmt.Fprintf(t, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.Title(), len(c.Points), number( c.Mean ), number( c.Sigma ),
+                number( c.StdDev ), lsl, usl, number( c.Cp ), number( c.Cpk ), strings.Join( c.Alerts(), "; " ))
+  }
+
+  return t.Flush()
+}
+
+/* Chart layout, in SVG user units */
+
+const (
+  svgWidth = 900
+  svgHeight = 400
+  svgLeft = 70
+  svgRight = 130
+  svgTop = 40
+  svgBottom = 30
+)
+
+/*
+    Procedure Name : SVG
+
+    Description    : Draws the individuals control chart of a test: the
+                     values in time order, the mean, the 1, 2 and 3 sigma
+                     lines and the specification limits. Points that
+                     complete a rule violation are drawn in red.
+
+    Arguments      : w - Where to write it
+
+    Return Value   : Any error writing
+*/
+
+func (c Chart) SVG( w io.Writer ) error {
+  lo := c.Mean - 3.5*c.Sigma
+  hi := c.Mean + 3.5*c.Sigma
+
+  for _, p := range c.Points {
+    lo = math.Min( lo, p.Value )
+    hi = math.Max( hi, p.Value )
+  }
+
+  for _, l := range []*float64{ c.LSL, c.USL } {
+    if l != nil {
+      lo = math.Min( lo, *l )
+      hi = math.Max( hi, *l )
+    }
+  }
+
+  if hi == lo {
+    hi, lo = hi + 1, lo - 1
+  }
+
+  plotWidth := (float64) (svgWidth - svgLeft - svgRight)
+  plotHeight := (float64) (svgHeight - svgTop - svgBottom)
+
+  x := func( i int ) float64 {
+    if len(c.Points) < 2 {
+      return svgLeft
+    }
+
+    return svgLeft + plotWidth*(float64) (i)/(float64) (len(c.Points) - 1)
+  }
+
+  y := func( v float64 ) float64 {
+    return svgTop + plotHeight*(hi - v)/(hi - lo)
+  }
+
+  var b strings.Builder
+
+  fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"11\">\n",
+              svgWidth, svgHeight, svgWidth, svgHeight)
+  fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+  fmt.Fprintf(&b, "<text x=\"%d\" y=\"20\" font-size=\"14\">%s</text>\n", svgLeft, html.EscapeString( c.Title() ))
+  fmt.Fprintf(&b, "<text x=\"%d\" y=\"34\">n=%d  mean=%s  sigma=%s  Cpk=%s %s</text>\n", svgLeft, len(c.Points),
+              number( c.Mean ), number( c.Sigma ), number( c.Cpk ), html.EscapeString( c.Units ))
+
+  line := func( v float64, colour string, dash string, label string ) {
+    fmt.Fprintf(&b, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%d\" y2=\"%.1f\" stroke=\"%s\" stroke-dasharray=\"%s\"/>\n",
+                svgLeft, y( v ), svgWidth - svgRight, y( v ), colour, dash)
+    fmt.Fprintf(&b, "<text x=\"%d\" y=\"%.1f\" fill=\"%s\">%s %s</text>\n", svgWidth - svgRight + 5, y( v ) + 4, colour, label, number( v ))
+  }
+
+  line( c.Mean, "green", "none", "mean" )
+
+  for k := 1;k <= 3;k++ {
+    colour := "#bbb"
+
+    if k == 3 {
+      colour = "orange"
+    }
+
+    line( c.Mean + (float64) (k)*c.Sigma, colour, "4,3", fmt.Sprintf("+%ds", k) )
+    line( c.Mean - (float64) (k)*c.Sigma, colour, "4,3", fmt.Sprintf("-%ds", k) )
+  }
+
+  if c.USL != nil {
+    line( *c.USL, "red", "8,4", "USL" )
+  }
+
+  if c.LSL != nil {
+    line( *c.LSL, "red", "8,4", "LSL" )
+  }
+
+  fmt.Fprintf(&b, "<polyline fill=\"none\" stroke=\"steelblue\" points=\"")
+
+  for i, p := range c.Points {
+    fmt.Fprintf(&b, "%.1f,%.1f ", x( i ), y( p.Value ))
+  }
+
+  fmt.Fprintf(&b, "\"/>\n")
+
+  flagged := map[int]bool{}
+
+  for _, v := range c.Violations {
+    flagged[v.Index] = true
+  }
+
+  for i, p := range c.Points {
+    colour := "steelblue"
+
+    if flagged[i] {
+      colour = "red"
+    }
+
+    fmt.Fprintf(&b, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"2.5\" fill=\"%s\"><title>%s %s %s</title></circle>\n",
+                x( i ), y( p.Value ), colour, html.EscapeString( p.Timestamp ), html.EscapeString( p.Serial ), number( p.Value ))
+  }
+
+  if len(c.Points) > 0 {
+    fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\">%s</text>\n", svgLeft, svgHeight - 10, html.EscapeString( c.Points[0].Timestamp ))
+    fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" text-anchor=\"end\">%s</text>\n", svgWidth - svgRight, svgHeight - 10,
+                html.EscapeString( c.Points[len(c.Points) - 1].Timestamp ))
+  }
+
+  fmt.Fprintf(&b, "</svg>\n")
+
+  _, err := io.WriteString( w, b.String() )
+
+  return err
+}
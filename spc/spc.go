@@ -0,0 +1,385 @@
+/*
+   Package spc applies statistical process control to the ranged results
+   of each test: the mean and spread of the measured values, the process
+   capability against the limits stored with the results, the Western
+   Electric rules on an individuals control chart, and drift alerts.
+
+   The values of a test are taken in time order as an individuals chart.
+   Sigma is estimated from the average moving range (MR-bar / 1.128), as
+   is usual for individuals, and is used for both the control limits and
+   Cp/Cpk. The overall standard deviation is reported beside it.
+*/
+
+package spc
+
+import (
+        "math"
+        "sort"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+const D2 = 1.128                           // Bias correction of a moving range of two
+const DEFAULT_WINDOW = 20                  // Points in the recent window checked for drift
+const TREND_LENGTH = 6                     // Points rising or falling in a row that make a trend
+
+/* Western Electric Rules */
+
+const (
+  RULE_1 = 1                               // One point beyond 3 sigma
+  RULE_2 = 2                               // Two of three points beyond 2 sigma on one side
+  RULE_3 = 3                               // Four of five points beyond 1 sigma on one side
+  RULE_4 = 4                               // Eight points in a row on one side of the centre line
+)
+
+var RuleDescriptions = map[int]string{
+  RULE_1: "one point beyond 3 sigma",
+  RULE_2: "two of three points beyond 2 sigma",
+  RULE_3: "four of five points beyond 1 sigma",
+  RULE_4: "eight points on one side of the mean",
+}
+
+/* One measurement */
+
+type Point struct {
+  Timestamp string                 // When it was taken
+  Serial string                    // Module serial number
+  Value float64                    // Measured value
+}
+
+/* A broken rule */
+
+type Violation struct {
+  Rule int                         // Western Electric rule
+  Index int                        // Point that completed the pattern
+}
+
+/* Control statistics of one test */
+
+type Chart struct {
+  Name string                      // Test name
+  Channel string                   // Label of the check, set when a test has several
+  Units string                     // Units of the values
+  Points []Point                   // Values in time order
+  Mean float64                     // Centre line
+  Sigma float64                    // Sigma from the average moving range
+  StdDev float64                   // Overall sample standard deviation
+  LSL *float64                     // Lower specification limit, nil if none
+  USL *float64                     // Upper specification limit, nil if none
+  Cp float64                       // Potential capability, NaN without both limits
+  Cpk float64                      // Capability, NaN without any limit
+  Violations []Violation           // Western Electric rule violations
+  Trend bool                       // The last points rise or fall steadily
+  Shift float64                    // Move of the recent window mean, in sigma
+  Drift bool                       // The recent window has moved by more than one sigma
+}
+
+/*
+    Procedure Name : limit
+
+    Description    : Converts an optional limit.
+
+    Arguments      : v - Limit, nil if not set
+
+    Return Value   : The limit as a float, nil if not set
+*/
+
+func limit( v *int ) *float64 {
+  if v == nil {
+    return nil
+  }
+
+  f := (float64) (*v)
+
+  return &f
+}
+
+/*
+    Procedure Name : Charts
+
+    Description    : Builds the control chart of every test that has
+                     ranged results, one for each check labelled in the
+                     results, as a sequence may store several channels
+                     under one test name. The specification limits are
+                     those of the latest result, so a change in the
+                     limits file shows up straight away.
+
+    Arguments      : list      - Results in any order
+                     minPoints - Fewest values a chart needs
+                     window    - Points in the recent window for drift
+
+    Return Value   : Charts, sorted by test name and channel
+*/
+
+func Charts( list []results.TestResult, minPoints int, window int ) []Chart {
+  sorted := append([]results.TestResult{}, list...)
+
+  sort.SliceStable( sorted, func( a, b int ) bool { return sorted[a].Timestamp < sorted[b].Timestamp } )
+
+  type check struct {
+    name string
+    label string
+  }
+
+  byCheck := map[check]*Chart{}
+  labels := map[string]int{}
+
+  for _, r := range sorted {
+    if r.Value == nil || (r.Kind != results.RANGE && r.Kind != results.LOWER_LIMIT && r.Kind != results.UPPER_LIMIT) {
+      continue
+    }
+
+    c, ok := byCheck[check{ r.TestName, r.Label }]
+
+    if !ok {
+      c = &Chart{ Name: r.TestName, Channel: r.Label }
+      byCheck[check{ r.TestName, r.Label }] = c
+      labels[r.TestName]++
+    }
+
+    c.Points = append(c.Points, Point{ Timestamp: r.Timestamp, Serial: r.SerialNumber, Value: (float64) (*r.Value) })
+    c.Units = r.Units
+    c.LSL = limit( r.LowerLimit )
+    c.USL = limit( r.UpperLimit )
+  }
+
+  charts := []Chart{}
+
+  for _, c := range byCheck {
+    if labels[c.Name] == 1 {
+      c.Channel = ""
+    }
+
+    if len(c.Points) >= minPoints && len(c.Points) >= 2 {
+      c.compute( window )
+
+      charts = append(charts, *c)
+    }
+  }
+
+  sort.Slice( charts, func( a, b int ) bool {
+    if charts[a].Name != charts[b].Name {
+      return charts[a].Name < charts[b].Name
+    }
+
+    return charts[a].Channel < charts[b].Channel
+  } )
+
+  return charts
+}
+
+/*
+    Procedure Name : Title
+
+    Description    : Returns the name a chart is shown under: the test
+                     name, followed by the channel if the test has
+                     several.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The title
+*/
+
+func (c Chart) Title() string {
+  if c.Channel == "" {
+    return c.Name
+  }
+
+  return c.Name + ": " + c.Channel
+}
+
+/*
+    Procedure Name : compute
+
+    Description    : Works out the statistics, rule violations and drift of
+                     a chart from its points.
+
+    Arguments      : window - Points in the recent window for drift
+
+    Return Value   : This routine has no return value.
+*/
+
+func (c *Chart) compute( window int ) {
+  n := len(c.Points)
+  sum := 0.0
+  movingRange := 0.0
+
+  for i := 0;i < n;i++ {
+    sum += c.Points[i].Value
+
+    if i > 0 {
+      movingRange += math.Abs( c.Points[i].Value - c.Points[i - 1].Value )
+    }
+  }
+
+  c.Mean = sum/(float64) (n)
+  c.Sigma = movingRange/(float64) (n - 1)/D2
+
+  squares := 0.0
+
+  for i := 0;i < n;i++ {
+    squares += (c.Points[i].Value - c.Mean)*(c.Points[i].Value - c.Mean)
+  }
+
+  c.StdDev = math.Sqrt( squares/(float64) (n - 1) )
+
+  /*
+      Capability. A one-sided limit gives a one-sided Cpk and no Cp.
+  */
+
+  c.Cp = math.NaN()
+  c.Cpk = math.NaN()
+
+  if c.Sigma > 0 {
+    upper := math.Inf(1)
+    lower := math.Inf(1)
+
+    if c.USL != nil {
+      upper = (*c.USL - c.Mean)/(3*c.Sigma)
+    }
+
+    if c.LSL != nil {
+      lower = (c.Mean - *c.LSL)/(3*c.Sigma)
+    }
+
+    if c.USL != nil && c.LSL != nil {
+      c.Cp = (*c.USL - *c.LSL)/(6*c.Sigma)
+    }
+
+    if c.USL != nil || c.LSL != nil {
+      c.Cpk = math.Min( upper, lower )
+    }
+  }
+
+  c.Violations = WesternElectric( c.values(), c.Mean, c.Sigma )
+
+  /*
+      Drift: the mean of the recent window against the mean of the points
+      before it, and a steady rise or fall at the end of the chart.
+  */
+
+  if window > 0 && n >= 2*window && c.Sigma > 0 {
+    before := 0.0
+    recent := 0.0
+
+    for i := 0;i < n - window;i++ {
+      before += c.Points[i].Value
+    }
+
+    for i := n - window;i < n;i++ {
+      recent += c.Points[i].Value
+    }
+
+    c.Shift = (recent/(float64) (window) - before/(float64) (n - window))/c.Sigma
+    c.Drift = math.Abs( c.Shift ) > 1
+  }
+
+  if n >= TREND_LENGTH {
+    rising, falling := true, true
+
+    for i := n - TREND_LENGTH + 1;i < n;i++ {
+      rising = rising && c.Points[i].Value > c.Points[i - 1].Value
+      falling = falling && c.Points[i].Value < c.Points[i - 1].Value
+    }
+
+    c.Trend = rising || falling
+  }
+}
+
+/*
+    Procedure Name : values
+
+    Description    : Returns the values of the chart's points.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The values in time order
+*/
+
+func (c *Chart) values() []float64 {
+  v := make([]float64, len(c.Points))
+
+  for i := 0;i < len(c.Points);i++ {
+    v[i] = c.Points[i].Value
+  }
+
+  return v
+}
+
+/*
+    Procedure Name : WesternElectric
+
+    Description    : Checks a series against the four Western Electric
+                     rules. Each rule is reported at the point that
+                     completes the pattern, once per pattern.
+
+    Arguments      : v     - Values in time order
+                     mean  - Centre line
+                     sigma - Sigma
+
+    Return Value   : The violations, in time order
+*/
+
+func WesternElectric( v []float64, mean float64, sigma float64 ) []Violation {
+  violations := []Violation{}
+
+  if sigma <= 0 {
+    return violations
+  }
+
+  /*
+      zone returns the signed number of sigmas a point is from the mean.
+  */
+
+  zone := func( i int ) float64 {
+    return (v[i] - mean)/sigma
+  }
+
+  /*
+      count returns how many of the last n points up to i are more than k
+      sigma out on the same side as point i.
+  */
+
+  count := func( i int, n int, k float64 ) int {
+    side := math.Copysign( 1, zone( i ) )
+    found := 0
+
+    for j := i - n + 1;j <= i;j++ {
+      if j >= 0 && side*zone( j ) > k {
+        found++
+      }
+    }
+
+    return found
+  }
+
+  last := map[int]int{ RULE_1: -1, RULE_2: -1, RULE_3: -1, RULE_4: -1 }
+
+  report := func( rule int, i int, length int ) {
+    if last[rule] < 0 || i - last[rule] >= length {
+      violations = append(violations, Violation{ Rule: rule, Index: i })
+
+      last[rule] = i
+    }
+  }
+
+  for i := 0;i < len(v);i++ {
+    if math.Abs( zone( i ) ) > 3 {
+      report( RULE_1, i, 1 )
+    }
+
+    if math.Abs( zone( i ) ) > 2 && count( i, 3, 2 ) >= 2 {
+      report( RULE_2, i, 3 )
+    }
+
+    if math.Abs( zone( i ) ) > 1 && count( i, 5, 1 ) >= 4 {
+      report( RULE_3, i, 5 )
+    }
+
+    if i >= 7 && count( i, 8, 0 ) == 8 {
+      report( RULE_4, i, 8 )
+    }
+  }
+
+  return violations
+}
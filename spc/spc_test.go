@@ -0,0 +1,257 @@
+package spc
+
+import (
+        "fmt"
+        "math"
+        "reflect"
+        "testing"
+
+        "github.com/questrail/IEMTestDB/results"
+)
+
+/*
+    Procedure Name : repeat
+
+    Description    : Makes a series of one value repeated.
+
+    Arguments      : v - Value
+                     n - Number of points
+
+    Return Value   : The series
+*/
+
+func repeat( v float64, n int ) []float64 {
+  s := make([]float64, n)
+
+  for i := 0;i < n;i++ {
+    s[i] = v
+  }
+
+  return s
+}
+
+/*
+    Procedure Name : TestWesternElectric
+
+    Description    : Checks each rule on a series around a mean of 0 with a
+                     sigma of 1, and that a pattern is reported once at the
+                     point that completes it.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestWesternElectric( t *testing.T ) {
+  tests := []struct {
+    name string
+    values []float64
+    sigma float64
+    want []Violation
+  }{
+    { "in control", []float64{ 0.5, -0.5, 0.5, -0.5 }, 1, []Violation{} },
+
+    { "rule 1 above", []float64{ 0, 0, 3.5, 0 }, 1, []Violation{ { RULE_1, 2 } } },
+
+    { "rule 1 below", []float64{ 0, -3.5 }, 1, []Violation{ { RULE_1, 1 } } },
+
+    { "rule 2", []float64{ 0, 2.5, 0, 2.5 }, 1, []Violation{ { RULE_2, 3 } } },
+
+    { "rule 2 on both sides", []float64{ 2.5, 0, -2.5 }, 1, []Violation{} },
+
+    { "rule 3", []float64{ 1.5, 1.5, 0, 1.5, 1.5 }, 1, []Violation{ { RULE_3, 4 } } },
+
+    { "rule 4", repeat( 0.5, 8 ), 1, []Violation{ { RULE_4, 7 } } },
+
+    { "rule 4 reported once", repeat( -0.5, 10 ), 1, []Violation{ { RULE_4, 7 } } },
+
+    { "rule 4 twice", repeat( 0.5, 16 ), 1, []Violation{ { RULE_4, 7 }, { RULE_4, 15 } } },
+
+    { "no spread", []float64{ 5, 5, 5 }, 0, []Violation{} },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      got := WesternElectric( tt.values, 0, tt.sigma )
+
+      if !reflect.DeepEqual( got, tt.want ) {
+        t.Errorf("violations %v, want %v", got, tt.want)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : result
+
+    Description    : Makes a ranged result of a check.
+
+    Arguments      : name  - Test name
+                     label - Label of the check
+                     i     - Order of the result, sets its timestamp
+                     value - Measured value
+
+    Return Value   : The result
+*/
+
+func result( name string, label string, i int, value int ) results.TestResult {
+  r := results.New( results.RANGE )
+
+  r.TestName = name
+  r.Label = label
+  r.Timestamp = fmt.Sprintf("2026-10-05T10:00:%2.2d.000Z", i)
+  r.Value = results.Int( value )
+  r.LowerLimit = results.Int( 90 )
+  r.UpperLimit = results.Int( 110 )
+
+  return r
+}
+
+/*
+    Procedure Name : TestCharts
+
+    Description    : Checks that a chart is made for each check of a test,
+                     that the channel is only shown for tests with several
+                     checks, and that charts with too few points or
+                     without values are left out.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestCharts( t *testing.T ) {
+  list := []results.TestResult{
+    result( "Volts", "Channel 2", 3, 100 ),
+    result( "Volts", "Channel 1", 0, 100 ),
+    result( "Volts", "Channel 1", 1, 102 ),
+    result( "Volts", "Channel 2", 2, 101 ),
+    result( "Amps", "Channel 1", 4, 50 ),
+    result( "Amps", "Channel 1", 5, 52 ),
+    result( "Short", "", 6, 1 ),
+  }
+
+  pf := results.New( results.PASS_FAIL )
+
+  pf.TestName = "Volts"
+  pf.Timestamp = "2026-10-05T10:00:07.000Z"
+
+  list = append(list, pf)
+
+  charts := Charts( list, 2, DEFAULT_WINDOW )
+
+  titles := []string{}
+
+  for _, c := range charts {
+    titles = append(titles, c.Title())
+  }
+
+  want := []string{ "Amps", "Volts: Channel 1", "Volts: Channel 2" }
+
+  if !reflect.DeepEqual( titles, want ) {
+    t.Fatalf("charts %v, want %v", titles, want)
+  }
+
+  if v := charts[1].values(); !reflect.DeepEqual( v, []float64{ 100, 102 } ) {
+    t.Errorf("values %v, want them in time order", v)
+  }
+}
+
+/*
+    Procedure Name : TestCapability
+
+    Description    : Checks the mean, sigma and capability of a chart
+                     with two limits, one limit and no limits.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestCapability( t *testing.T ) {
+  lower, upper := 90.0, 110.0
+  values := []float64{ 100, 102, 100, 102 }
+  sigma := 2/D2
+
+  tests := []struct {
+    name string
+    lsl *float64
+    usl *float64
+    cp float64
+    cpk float64
+  }{
+    { "both limits", &lower, &upper, 20/(6*sigma), 9/(3*sigma) },
+
+    { "lower limit", &lower, nil, math.NaN(), 11/(3*sigma) },
+
+    { "upper limit", nil, &upper, math.NaN(), 9/(3*sigma) },
+
+    { "no limits", nil, nil, math.NaN(), math.NaN() },
+  }
+
+  same := func( a, b float64 ) bool {
+    return (math.IsNaN( a ) && math.IsNaN( b )) || math.Abs( a - b ) < 1e-9
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      c := Chart{ LSL: tt.lsl, USL: tt.usl }
+
+      for _, v := range values {
+        c.Points = append(c.Points, Point{ Value: v })
+      }
+
+      c.compute( DEFAULT_WINDOW )
+
+      if !same( c.Mean, 101 ) || !same( c.Sigma, sigma ) {
+        t.Errorf("mean %g sigma %g, want 101 and %g", c.Mean, c.Sigma, sigma)
+      }
+
+      if !same( c.Cp, tt.cp ) || !same( c.Cpk, tt.cpk ) {
+        t.Errorf("Cp %g Cpk %g, want %g and %g", c.Cp, c.Cpk, tt.cp, tt.cpk)
+      }
+    })
+  }
+}
+
+/*
+    Procedure Name : TestDrift
+
+    Description    : Checks that a move of the recent window and a steady
+                     rise at the end of a chart are flagged.
+
+    Arguments      : t - Test state
+
+    Return Value   : This routine has no return value.
+*/
+
+func TestDrift( t *testing.T ) {
+  tests := []struct {
+    name string
+    values []float64
+    drift bool
+    trend bool
+  }{
+    { "steady", []float64{ 10, 11, 10, 11, 10, 11, 10, 11 }, false, false },
+
+    { "shifted", []float64{ 10, 11, 10, 11, 14, 15, 14, 15 }, true, false },
+
+    { "rising", []float64{ 10, 10, 11, 12, 13, 14, 15, 16 }, true, true },
+  }
+
+  for _, tt := range tests {
+    t.Run( tt.name, func( t *testing.T ) {
+      c := Chart{}
+
+      for _, v := range tt.values {
+        c.Points = append(c.Points, Point{ Value: v })
+      }
+
+      c.compute( 4 )
+
+      if c.Drift != tt.drift || c.Trend != tt.trend {
+        t.Errorf("drift %v trend %v (shift %g), want %v and %v", c.Drift, c.Trend, c.Shift, tt.drift, tt.trend)
+      }
+    })
+  }
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+        "flag"
+        "os"
+        "path/filepath"
+        "strings"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/results"
+        "github.com/questrail/IEMTestDB/spc"
+)
+
+/*
+    Procedure Name : RunSPC
+
+    Description    : The spc subcommand. Writes the control statistics of
+                     every test with ranged results, and optionally a
+                     control chart of each as an SVG file.
+
+                       iemtestdb spc [-from 2026-01-01] [-to 2026-03-31] [-test "80 Volt"] [-svg charts]
+
+    Arguments      : args - Command line after "spc"
+
+    Return Value   : Any error making the statistics
+*/
+
+func RunSPC( args []string ) error {
+  flags := flag.NewFlagSet( "spc", flag.ContinueOnError )
+
+  configFile := flags.String("config", DEFAULT_CONFIG_FILE, "tester configuration file")
+  fromDate := flags.String("from", "", "first day to include, YYYY-MM-DD in UTC")
+  toDate := flags.String("to", "", "last day to include, YYYY-MM-DD in UTC")
+  test := flags.String("test", "", "only tests whose name or label contains this")
+  minPoints := flags.Int("min", 10, "fewest values a test needs to be charted")
+  window := flags.Int("window", spc.DEFAULT_WINDOW, "recent values checked for drift")
+  svgDir := flags.String("svg", "", "directory to write an SVG chart of each test to")
+
+  if flags.Parse( args ) != nil {
+    return ExitError{ EXIT_TESTER }
+  }
+
+  var from, to time.Time
+  var err error
+
+  if *fromDate != "" {
+    from, err = time.Parse( "2006-01-02", *fromDate )
+  }
+
+  if err == nil && *toDate != "" {
+    to, err = time.Parse( "2006-01-02", *toDate )
+  }
+
+  if err == nil {
+    Settings, err = LoadConfig( *configFile )
+  }
+
+  var db *couchdb.Database
+
+  if err == nil {
+    db, err = couchdb.NewDatabase( Settings.Database )
+  }
+
+  if err == nil {
+    _, err = results.InstallViews( db )
+  }
+
+  var list []results.TestResult
+
+  if err == nil {
+    list, err = datedResults( db, from, to )
+  }
+
+  if err != nil {
+    return err
+  }
+
+  selected := []results.TestResult{}
+
+  for _, r := range list {
+    if strings.Contains( r.TestName, *test ) || strings.Contains( r.Label, *test ) {
+      selected = append(selected, r)
+    }
+  }
+
+  charts := spc.Charts( selected, *minPoints, *window )
+
+  err = spc.Text( os.Stdout, charts )
+
+  if err == nil && *svgDir != "" {
+    err = os.MkdirAll( *svgDir, 0755 )
+
+    for i := 0;i < len(charts) && err == nil;i++ {
+      var f *os.File
+
+      f, err = os.Create( filepath.Join( *svgDir, chartFile( charts[i].Title() ) ) )
+
+      if err == nil {
+        err = charts[i].SVG( f )
+
+        if closeErr := f.Close(); err == nil {
+          err = closeErr
+        }
+      }
+    }
+  }
+
+  return err
+}
+
+/*
+    Procedure Name : chartFile
+
+    Description    : Turns a test name into the name of its chart file.
+
+    Arguments      : name - Test name
+
+    Return Value   : The file name
+*/
+
+func chartFile( name string ) string {
+  return strings.Map( func( r rune ) rune {
+    switch {
+      case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.' :
+        return r
+    }
+
+    return '_'
+  }, name ) + ".svg"
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+        "errors"
+        "fmt"
+        "log"
+        "sync"
+        "time"
+
+        "github.com/leesper/couchdb-golang"
+        "github.com/questrail/IEMTestDB/journal"
+        "github.com/questrail/IEMTestDB/results"
+        "github.com/questrail/IEMTestDB/sinks"
+)
+
+const DEFAULT_DATABASE = "http://127.0.0.1:5984/testdb"
+const DEFAULT_JOURNAL_DIR = "journal"
+
+var ResultJournal *journal.Journal         // Results waiting to be saved in the test database, nil without a couchdb sink
+var ResultSinks []sinks.ResultSink         // Everywhere results are saved
+var sinkTypes []string                     // Configured type of each sink, for messages
+var UnsavedResults int                     // Number of results that could not be saved this run
+
+var testDBLock sync.Mutex                  // Protects TestDB and viewsInstalled
+var viewsInstalled bool                    // Set once the views are known to be current
+
+/*
+    Procedure Name : OpenStorage
+
+    Description    : Opens every result sink in the configuration.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error opening a sink
+*/
+
+func OpenStorage() error {
+  for _, c := range Settings.Sinks {
+    sink, err := OpenSink( c )
+
+    if err != nil {
+      return fmt.Errorf("%s sink: %s", c.Type, err)
+    }
+
+    ResultSinks = append(ResultSinks, sink)
+    sinkTypes = append(sinkTypes, c.Type)
+  }
+
+  return nil
+}
+
+/*
+    Procedure Name : OpenCouchDBSink
+
+    Description    : Opens the result journal and starts copying it to the
+                     test database in the background. The database does
+                     not need to be reachable; results wait in the
+                     journal until it is.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The sink
+                     Any error opening the journal
+*/
+
+func OpenCouchDBSink() (sinks.ResultSink, error) {
+  if ResultJournal == nil {
+    j, err := journal.Open( Settings.Journal )
+
+    if err != nil {
+      return nil, err
+    }
+
+    ResultJournal = j
+    ResultJournal.Permanent = rejectedByCouchDB
+
+    go ResultJournal.Replicate( openTestDB, journal.DEFAULT_RETRY_INTERVAL )
+
+    /*
+        Connect now, rather than when the first result is saved, so that
+        the views are upgraded when the tester starts.
+    */
+
+    go openTestDB()
+  }
+
+  return sinks.NewCouchDB( ResultJournal ), nil
+}
+
+/*
+    Procedure Name : rejectedByCouchDB
+
+    Description    : Tells whether CouchDB refused a document in a way
+                     that saving it again will not change: a malformed
+                     document, or one a validation function forbids.
+
+    Arguments      : err - Error saving the document
+
+    Return Value   : true if the document can never be saved
+*/
+
+func rejectedByCouchDB( err error ) bool {
+  return errors.Is( err, couchdb.ErrBadRequest ) || errors.Is( err, couchdb.ErrForbidden )
+}
+
+/*
+    Procedure Name : CloseStorage
+
+    Description    : Closes every result sink.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func CloseStorage() {
+  for i, sink := range ResultSinks {
+    err := sink.Close()
+
+    if err != nil {
+      log.Printf("%s sink: %s", sinkTypes[i], err)
+    }
+  }
+}
+
+/*
+    Procedure Name : openTestDB
+
+    Description    : Returns the test database, connecting to it if that
+                     has not been done yet, and checks that it answers.
+                     The first time it answers, the views are installed
+                     or upgraded.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : The test database
+                     Any error reaching it
+*/
+
+func openTestDB() (journal.Database, error) {
+  testDBLock.Lock()
+  defer testDBLock.Unlock()
+
+  if TestDB == nil {
+    db, err := couchdb.NewDatabase( Settings.Database )
+
+    if err != nil {
+      return nil, err
+    }
+
+    TestDB = db
+  }
+
+  err := TestDB.Available()
+
+  if err == nil && !viewsInstalled {
+    written, viewErr := results.InstallViews( TestDB )
+
+    if viewErr != nil {
+      log.Printf("error installing the test database views: %s", viewErr)
+    } else {
+      viewsInstalled = true
+
+      if written {
+        log.Printf("Installed test database views version %d", results.DESIGN_VERSION)
+      }
+    }
+  }
+
+  return TestDB, err
+}
+
+/*
+    Procedure Name : SaveResult
+
+    Description    : Saves a result in every sink. The couchdb sink writes
+                     it to the journal on the tester's disk straight away
+                     and to the test database in the background. A result
+                     that a sink cannot save is counted in UnsavedResults
+                     rather than returned as an error, so that a storage
+                     problem never stops a test or counts as a failure of
+                     the unit.
+
+    Arguments      : result - Result with its ID set
+
+    Return Value   : This routine has no return value.
+*/
+
+func SaveResult( result *results.TestResult ) {
+  saved := true
+
+  for i, sink := range ResultSinks {
+    err := sink.SaveResult( result )
+
+    if err != nil {
+      saved = false
+
+      log.Printf("%s sink: error saving result %s: %s", sinkTypes[i], result.GetID(), err)
+    }
+  }
+
+  if !saved {
+    UnsavedResults++
+  }
+}
+
+/*
+    Procedure Name : SaveRun
+
+    Description    : Saves or updates a run in every sink.
+
+    Arguments      : run - Run with its ID set
+
+    Return Value   : This routine has no return value.
+*/
+
+func SaveRun( run *results.Run ) {
+  for i, sink := range ResultSinks {
+    err := sink.SaveRun( run )
+
+    if err != nil {
+      log.Printf("%s sink: error saving run %s: %s", sinkTypes[i], run.GetID(), err)
+    }
+  }
+}
+
+/*
+    Procedure Name : FlushResults
+
+    Description    : Saves every result waiting in the journal in the test
+                     database now, and reports how it went.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error reaching the database or saving a result
+*/
+
+func FlushResults() error {
+  if ResultJournal == nil {
+    return fmt.Errorf("No couchdb sink is configured")
+  }
+
+  db, err := openTestDB()
+
+  saved := 0
+
+  if err == nil {
+    saved, err = ResultJournal.Flush( db )
+  }
+
+  fmt.Printf("%d results saved in the test database.\r\n", saved)
+
+  PrintStorageStatus()
+
+  return err
+}
+
+/*
+    Procedure Name : PrintStorageStatus
+
+    Description    : Tells the operator whether all results have reached
+                     the test database.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func PrintStorageStatus() {
+  if ResultJournal == nil {
+    return
+  }
+
+  status := ResultJournal.Status()
+
+  if status.Rejected > 0 {
+    fmt.Printf("Database: %d results could never be saved and are set aside in %s as .bad files.\r\n", status.Rejected, Settings.Journal)
+  }
+
+  if status.Pending == 0 && status.Rejected == 0 {
+    fmt.Printf("Database: all results saved.\r\n")
+  }
+
+  if status.Pending == 0 {
+    return
+  }
+
+  fmt.Printf("Database: %d results waiting to be saved", status.Pending)
+
+  if status.LastError != nil {
+    fmt.Printf(" (%s)", status.LastError)
+  }
+
+  if !status.LastSaved.IsZero() {
+    fmt.Printf(", last saved %s", status.LastSaved.Format( time.Kitchen ))
+  }
+
+  fmt.Printf(".\r\n")
+}
@@ -0,0 +1,183 @@
+package transport
+
+import (
+        "io"
+        "sync"
+        "time"
+)
+
+/* One direction of an in-memory link */
+
+type pipeBuffer struct {
+  mu sync.Mutex                    // Protects data and closed
+  data []byte                      // Bytes written but not yet read
+  closed bool                      // Set when either end is closed
+  ready chan struct{}              // Signalled whenever data arrives or the pipe closes
+}
+
+/* One end of an in-memory link */
+
+type pipeEnd struct {
+  in *pipeBuffer                   // Bytes coming to this end
+  out *pipeBuffer                  // Bytes going to the other end
+  timeout time.Duration            // Read timeout
+}
+
+/*
+    Procedure Name : Pipe
+
+    Description    : Creates a buffered in-memory link. Bytes written to
+                     one end are read from the other. Writes never block,
+                     so a simulated IEM can answer a command that the
+                     tester does not wait for.
+
+    Arguments      : timeout - Read timeout for both ends
+
+    Return Value   : The two ends of the link
+*/
+
+func Pipe( timeout time.Duration ) (Transport, Transport) {
+  a := &pipeBuffer{ ready: make(chan struct{}, 1) }
+  b := &pipeBuffer{ ready: make(chan struct{}, 1) }
+
+  return &pipeEnd{ in: a, out: b, timeout: timeout }, &pipeEnd{ in: b, out: a, timeout: timeout }
+}
+
+/*
+    Procedure Name : signal
+
+    Description    : Wakes up a reader waiting on the buffer.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : This routine has no return value.
+*/
+
+func (p *pipeBuffer) signal() {
+  select {
+    case p.ready <- struct{}{} :
+    default :
+  }
+}
+
+/*
+    Procedure Name : Read
+
+    Description    : Reads whatever bytes are waiting, waiting up to the
+                     read timeout for some to arrive.
+
+    Arguments      : b - Slice to read into
+
+    Return Value   : Number of bytes read
+                     io.EOF on a timeout
+                     io.ErrClosedPipe once the link has been closed
+*/
+
+func (p *pipeEnd) Read( b []byte ) (int, error) {
+  timer := time.NewTimer( p.timeout )
+
+  defer timer.Stop()
+
+  for {
+    p.in.mu.Lock()
+
+    if len(p.in.data) > 0 {
+      n := copy( b, p.in.data )
+
+      p.in.data = p.in.data[n:]
+
+      if len(p.in.data) > 0 {
+        p.in.signal()
+      }
+
+      p.in.mu.Unlock()
+
+      return n, nil
+    }
+
+    closed := p.in.closed
+
+    p.in.mu.Unlock()
+
+    if closed {
+      return 0, io.ErrClosedPipe
+    }
+
+    select {
+      case <-p.in.ready :
+      case <-timer.C :
+        return 0, io.EOF
+    }
+  }
+}
+
+/*
+    Procedure Name : Write
+
+    Description    : Queues bytes for the other end of the link.
+
+    Arguments      : b - Bytes to write
+
+    Return Value   : Number of bytes written
+                     io.ErrClosedPipe if the link has been closed
+*/
+
+func (p *pipeEnd) Write( b []byte ) (int, error) {
+  p.out.mu.Lock()
+
+  defer p.out.mu.Unlock()
+
+  if p.out.closed {
+    return 0, io.ErrClosedPipe
+  }
+
+  p.out.data = append(p.out.data, b...)
+
+  p.out.signal()
+
+  return len(b), nil
+}
+
+/*
+    Procedure Name : Flush
+
+    Description    : Discards any bytes waiting to be read.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Always nil
+*/
+
+func (p *pipeEnd) Flush() error {
+  p.in.mu.Lock()
+
+  p.in.data = p.in.data[:0]
+
+  p.in.mu.Unlock()
+
+  return nil
+}
+
+/*
+    Procedure Name : Close
+
+    Description    : Closes both directions of the link.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Always nil
+*/
+
+func (p *pipeEnd) Close() error {
+  for _, buf := range []*pipeBuffer{ p.in, p.out } {
+    buf.mu.Lock()
+
+    buf.closed = true
+
+    buf.signal()
+
+    buf.mu.Unlock()
+  }
+
+  return nil
+}
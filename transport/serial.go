@@ -0,0 +1,32 @@
+package transport
+
+import (
+        "github.com/tarm/serial"
+        "time"
+)
+
+/*
+    Procedure Name : OpenSerial
+
+    Description    : Opens a serial device such as /dev/ttyS0 or a USB
+                     serial adapter.
+
+    Arguments      : device  - Serial device name
+                     baud    - Baud rate
+                     timeout - Read timeout
+
+    Return Value   : The transport
+                     Any error opening the device
+*/
+
+func OpenSerial( device string, baud int, timeout time.Duration ) (Transport, error) {
+  c := &serial.Config{ Name: device, Baud: baud, ReadTimeout: timeout }
+
+  port, err := serial.OpenPort( c )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return port, nil
+}
@@ -0,0 +1,30 @@
+package transport
+
+import (
+        "net"
+        "time"
+)
+
+/*
+    Procedure Name : DialTCP
+
+    Description    : Connects to a networked serial server (ser2net style)
+                     that passes the IEM serial link through a TCP socket.
+
+    Arguments      : address     - host:port of the serial server
+                     dialTimeout - Time allowed to connect
+                     timeout     - Read timeout
+
+    Return Value   : The transport
+                     Any error connecting to the server
+*/
+
+func DialTCP( address string, dialTimeout time.Duration, timeout time.Duration ) (Transport, error) {
+  conn, err := net.DialTimeout( "tcp", address, dialTimeout )
+
+  if err != nil {
+    return nil, err
+  }
+
+  return NewConn( conn, timeout ), nil
+}
@@ -0,0 +1,157 @@
+/*
+   Package transport provides the byte links that the tester uses to talk
+   to the IEM. The original bench uses a serial port, but the same framed
+   protocol can be carried over a networked serial server or an in-memory
+   pipe.
+
+   Every transport behaves like the tarm/serial port that the tester was
+   written against: a Read that times out with no data returns zero bytes
+   and io.EOF.
+*/
+
+package transport
+
+import (
+        "errors"
+        "io"
+        "net"
+        "time"
+)
+
+/*
+   A read waits this long for bytes by default. The original bench waited
+   five seconds; the response deadlines are now kept by the IEM client,
+   which checks them between reads.
+*/
+
+const DEFAULT_READ_TIMEOUT = 100*time.Millisecond
+
+/* A networked serial server is given this long to accept a connection */
+
+const DEFAULT_DIAL_TIMEOUT = 5*time.Second
+
+/* Transport Interface */
+
+type Transport interface {
+  io.ReadWriter
+  Flush() error                    // Discard any input that has not been read
+  Close() error                    // Release the underlying link
+}
+
+/* Transport over a net.Conn */
+
+type connTransport struct {
+  conn net.Conn                    // Underlying connection
+  timeout time.Duration            // Read timeout for each Read call
+}
+
+/*
+    Procedure Name : NewConn
+
+    Description    : Wraps a network connection so that it behaves like
+                     the serial port transport.
+
+    Arguments      : conn    - Connection to wrap
+                     timeout - Read timeout for each Read call
+
+    Return Value   : The transport
+*/
+
+func NewConn( conn net.Conn, timeout time.Duration ) Transport {
+  return &connTransport{ conn: conn, timeout: timeout }
+}
+
+/*
+    Procedure Name : Read
+
+    Description    : Reads from the connection. A read that times out is
+                     reported as io.EOF the same way the serial port does,
+                     so a connection closed by the other end is reported
+                     as io.ErrClosedPipe instead.
+
+    Arguments      : b - Slice to read into
+
+    Return Value   : Number of bytes read
+                     io.EOF on a timeout
+                     io.ErrClosedPipe once the other end has closed
+                     Any other read error
+*/
+
+func (t *connTransport) Read( b []byte ) (int, error) {
+  t.conn.SetReadDeadline( time.Now().Add( t.timeout ) )
+
+  n, err := t.conn.Read( b )
+
+  if err != nil {
+    var netErr net.Error
+
+    switch {
+      case errors.As( err, &netErr ) && netErr.Timeout() :
+        err = io.EOF
+
+      case errors.Is( err, io.EOF ) :
+        err = io.ErrClosedPipe
+    }
+  }
+
+  return n, err
+}
+
+/*
+    Procedure Name : Write
+
+    Description    : Writes to the connection.
+
+    Arguments      : b - Bytes to write
+
+    Return Value   : Number of bytes written
+                     Any write error
+*/
+
+func (t *connTransport) Write( b []byte ) (int, error) {
+  return t.conn.Write( b )
+}
+
+/*
+    Procedure Name : Flush
+
+    Description    : Discards any input already waiting on the connection.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any error other than the expected timeout
+*/
+
+func (t *connTransport) Flush() error {
+  buf := make([]byte, 256)
+
+  for {
+    t.conn.SetReadDeadline( time.Now().Add( time.Millisecond ) )
+
+    _, err := t.conn.Read( buf )
+
+    if err != nil {
+      var netErr net.Error
+
+      if errors.As( err, &netErr ) && netErr.Timeout() {
+        return nil
+      }
+
+      return err
+    }
+  }
+}
+
+/*
+    Procedure Name : Close
+
+    Description    : Closes the connection.
+
+    Arguments      : This routine has no arguments.
+
+    Return Value   : Any close error
+*/
+
+func (t *connTransport) Close() error {
+  return t.conn.Close()
+}